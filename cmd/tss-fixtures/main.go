@@ -0,0 +1,65 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Command tss-fixtures generates keygen test fixtures into a directory, for keygen.LoadKeygenTestFixturesFromDir:
+//
+//	go run ./cmd/tss-fixtures -dir /tmp/fixtures -n 5 -t 2 -curve p256 -profile test
+package main
+
+import (
+	"context"
+	"crypto/elliptic"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"time"
+
+	"github.com/bnb-chain/tss-lib/test/fixtures"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+func main() {
+	var (
+		dir         = flag.String("dir", "", "directory to write the fixtures to (required)")
+		n           = flag.Int("n", 3, "number of parties")
+		t           = flag.Int("t", 1, "threshold; t+1 parties are needed to sign")
+		curve       = flag.String("curve", string(tss.Secp256k1), "curve: secp256k1 or p256")
+		profile     = flag.String("profile", string(fixtures.ProfileStandard), "security profile: standard or test")
+		seed        = flag.Int64("seed", 0, "seed for reproducible fixtures, with -concurrency 1; 0 uses crypto/rand")
+		concurrency = flag.Int("concurrency", 1, "goroutines to generate the pre-parameters with")
+		timeout     = flag.Duration("timeout", time.Hour, "give up after this long")
+	)
+	flag.Parse()
+	if *dir == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	tss.RegisterCurve("p256", elliptic.P256())
+
+	var source io.Reader
+	if *seed != 0 {
+		source = rand.New(rand.NewSource(*seed))
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	began := time.Now()
+	keys, err := fixtures.Generate(ctx, *dir, fixtures.Options{
+		Participants: *n,
+		Threshold:    *t,
+		Curve:        tss.CurveName(*curve),
+		Profile:      fixtures.Profile(*profile),
+		Rand:         source,
+		Concurrency:  *concurrency,
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Printf("wrote %d %s fixtures (threshold %d, %s profile) to %s in %s\n",
+		len(keys), *curve, *t, *profile, *dir, time.Since(began).Round(time.Second))
+}
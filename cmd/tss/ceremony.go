@@ -0,0 +1,140 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+const minSessionLen = 16
+
+type (
+	// ceremonyFlags are the flags shared by keygen and sign.
+	ceremonyFlags struct {
+		transport, dir, session string
+		timeout                 time.Duration
+	}
+
+	// ceremony relays the messages of the local party to and from the other processes.
+	ceremony struct {
+		pIDs     tss.SortedPartyIDs
+		self     *tss.PartyID
+		tr       transport
+		deadline time.Time
+	}
+)
+
+func (cf *ceremonyFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&cf.transport, "transport", "unix", "how the processes talk: unix (sockets) or dir (files)")
+	fs.StringVar(&cf.dir, "dir", "", "directory shared by the processes of the ceremony; use a fresh one per ceremony (required)")
+	fs.StringVar(&cf.session, "session", "", "session ID agreed by all parties, unique per ceremony, at least 16 bytes (required)")
+	fs.DurationVar(&cf.timeout, "timeout", 10*time.Minute, "give up after this long")
+}
+
+func (cf *ceremonyFlags) setSession(params *tss.Parameters) error {
+	if len(cf.session) < minSessionLen {
+		return fmt.Errorf("-session must be at least %d bytes", minSessionLen)
+	}
+	params.SetSessionNonceBytes([]byte(cf.session))
+	return nil
+}
+
+func (cf *ceremonyFlags) newCeremony(pIDs tss.SortedPartyIDs, self *tss.PartyID) (*ceremony, error) {
+	deadline := time.Now().Add(cf.timeout)
+	tr, err := newTransport(cf.transport, cf.dir, self, deadline)
+	if err != nil {
+		return nil, err
+	}
+	return &ceremony{pIDs: pIDs, self: self, tr: tr, deadline: deadline}, nil
+}
+
+// run starts P, which sends to `out`, and relays its messages until it finishes, fails or the deadline passes.
+func (c *ceremony) run(P tss.Party, out chan tss.Message) error {
+	defer c.tr.close()
+	sendErr := make(chan error, 1)
+	synced := make(chan struct{})
+	go func() {
+		for msg := range out {
+			if msg == nil {
+				synced <- struct{}{}
+				continue
+			}
+			if err := c.send(msg); err != nil {
+				select {
+				case sendErr <- err:
+				default:
+				}
+			}
+		}
+	}()
+	// the party only sends from within Start and Update
+	defer close(out)
+	flush := func() {
+		out <- nil
+		<-synced
+	}
+
+	if err := P.Start(); err != nil {
+		return err
+	}
+	flush()
+	timer := time.NewTimer(time.Until(c.deadline))
+	defer timer.Stop()
+	for P.Running() {
+		select {
+		case in := <-c.tr.frames():
+			msg, err := decodeFrame(in, c.self, c.pIDs)
+			if err != nil {
+				return err
+			}
+			if _, err := P.Update(msg); err != nil {
+				return err
+			}
+			flush()
+		case err := <-sendErr:
+			return err
+		case <-timer.C:
+			return fmt.Errorf("timed out waiting for %v", P.WaitingFor())
+		}
+	}
+	select {
+	case err := <-sendErr:
+		return err
+	default:
+		return nil
+	}
+}
+
+func (c *ceremony) send(msg tss.Message) error {
+	wire, _, err := msg.WireBytes()
+	if err != nil {
+		return err
+	}
+	frame := encodeFrame(c.self.Index, msg.IsBroadcast(), wire)
+	to := msg.GetTo()
+	if to == nil {
+		for _, Pj := range c.pIDs {
+			if Pj.Index != c.self.Index {
+				to = append(to, Pj)
+			}
+		}
+	}
+	if len(to) == 0 {
+		return errors.New("message without recipients")
+	}
+	for _, Pj := range to {
+		if err = c.tr.send(Pj, frame); err != nil {
+			return err
+		}
+	}
+	return nil
+}
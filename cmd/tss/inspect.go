@@ -0,0 +1,166 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/bnb-chain/tss-lib/ecdsa/encoding"
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+// runInspect prints the public fields of a key, or with -diff those that disagree with another party's key. It never
+// prints the share Xi or the Paillier and NTilde secrets.
+func runInspect(args []string, stdout io.Writer) error {
+	var passphraseFile string
+	fs := newFlagSet("inspect", &passphraseFile)
+	keyFile := fs.String("key", "", "key written by keygen, or a plain JSON LocalPartySaveData (required)")
+	diffFile := fs.String("diff", "", "key of another party to compare the public fields with")
+	diffPassphraseFile := fs.String("diff-passphrase-file", "", "file holding the passphrase of -diff; defaults to -passphrase-file")
+	asJSON := fs.Bool("json", false, "print the public fields as JSON")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *keyFile == "" {
+		return errors.New("-key is required")
+	}
+	key, err := readKey(*keyFile, passphraseFile)
+	if err != nil {
+		return err
+	}
+	info, err := key.Inspect()
+	if err != nil {
+		return fmt.Errorf("%s: %v", *keyFile, err)
+	}
+	if *diffFile != "" {
+		if *diffPassphraseFile == "" {
+			*diffPassphraseFile = passphraseFile
+		}
+		other, err := readKey(*diffFile, *diffPassphraseFile)
+		if err != nil {
+			return err
+		}
+		otherInfo, err := other.Inspect()
+		if err != nil {
+			return fmt.Errorf("%s: %v", *diffFile, err)
+		}
+		return printDiff(stdout, info.Diff(otherInfo), *asJSON)
+	}
+	if *asJSON {
+		return printJSON(stdout, info)
+	}
+
+	w := &fieldWriter{w: stdout}
+	w.field("curve", info.Curve)
+	if index, err := key.OriginalIndex(); err == nil {
+		w.field("party", fmt.Sprintf("%d of %d", index+1, info.PartyCount))
+	}
+	w.field("threshold", info.Threshold)
+	w.field("share ID", info.ShareID)
+	w.field("public key", info.PublicKeyCompressed)
+	w.field("uncompressed", info.PublicKeyUncompressed)
+	if info.Curve == tss.Secp256k1 {
+		if address, err := encoding.EthereumAddress(key.ECDSAPub); err == nil {
+			w.field("eth address", address)
+		}
+	}
+	if info.ChainCode != "" {
+		w.field("chain code", info.ChainCode)
+	}
+	for j := range info.Ks {
+		w.field(fmt.Sprintf("party %d", j+1), fmt.Sprintf("k %s, paillier %s, ntilde %s",
+			info.Ks[j], info.PaillierFingerprints[j], info.NTildeFingerprints[j]))
+	}
+	if info.Weights != nil {
+		w.field("weights", info.Weights)
+	}
+	if info.Ranks != nil {
+		w.field("ranks", info.Ranks)
+	}
+	return w.err
+}
+
+// readKey reads an encrypted key written by keygen or, failing that, a plain JSON LocalPartySaveData such as the
+// test fixtures; the passphrase is only read for an encrypted key.
+func readKey(path, passphraseFile string) (*keygen.LocalPartySaveData, error) {
+	bz, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key := new(keygen.LocalPartySaveData)
+	var file saveFile
+	if err = json.Unmarshal(bz, &file); err == nil && file.Ciphertext != nil {
+		passphrase, err := readPassphrase(passphraseFile)
+		if err != nil {
+			return nil, err
+		}
+		return key, readEncrypted(path, key, passphrase)
+	}
+	if err = json.Unmarshal(bz, key); err != nil {
+		return nil, fmt.Errorf("%s is neither an encrypted save file nor a save data: %v", path, err)
+	}
+	return key, nil
+}
+
+// printDiff prints the fields that disagree; it fails if there are any, for scripts to check the exit status
+func printDiff(stdout io.Writer, diffs []keygen.FieldDiff, asJSON bool) error {
+	if asJSON {
+		if diffs == nil {
+			diffs = []keygen.FieldDiff{}
+		}
+		if err := printJSON(stdout, diffs); err != nil || len(diffs) == 0 {
+			return err
+		}
+		return fmt.Errorf("%d public fields disagree", len(diffs))
+	}
+	if len(diffs) == 0 {
+		_, err := fmt.Fprintln(stdout, "the public fields agree")
+		return err
+	}
+	w := &fieldWriter{w: stdout}
+	for _, d := range diffs {
+		w.field(d.Field, fmt.Sprintf("%s != %s", orMissing(d.A), orMissing(d.B)))
+	}
+	if w.err != nil {
+		return w.err
+	}
+	return fmt.Errorf("%d public fields disagree", len(diffs))
+}
+
+func printJSON(stdout io.Writer, v interface{}) error {
+	bz, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(stdout, string(bz))
+	return err
+}
+
+func orMissing(value string) string {
+	if value == "" {
+		return "(missing)"
+	}
+	return value
+}
+
+// fieldWriter prints aligned "name: value" lines and keeps the first error
+type fieldWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (w *fieldWriter) field(name string, value interface{}) {
+	if w.err != nil {
+		return
+	}
+	_, w.err = fmt.Fprintf(w.w, "%-12s %v\n", name+":", value)
+}
@@ -0,0 +1,127 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"runtime"
+	"time"
+
+	"github.com/bnb-chain/tss-lib/ecdsa/encoding"
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+func runPreParams(args []string, stdout io.Writer) error {
+	var passphraseFile string
+	fs := newFlagSet("preparams", &passphraseFile)
+	out := fs.String("out", "", "file to write the encrypted pre-parameters to (required)")
+	concurrency := fs.Int("concurrency", runtime.NumCPU(), "goroutines to search for safe primes with")
+	timeout := fs.Duration("timeout", 30*time.Minute, "give up after this long")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *out == "" {
+		return errors.New("-out is required")
+	}
+	passphrase, err := readPassphrase(passphraseFile)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	began := time.Now()
+	preParams, err := keygen.GeneratePreParamsWithContext(ctx, *concurrency)
+	if err != nil {
+		return err
+	}
+	if err = writeEncrypted(*out, preParams, passphrase); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "wrote pre-parameters to %s in %s\n", *out, time.Since(began).Round(time.Second))
+	return nil
+}
+
+func runKeygen(args []string, stdout io.Writer) error {
+	var (
+		passphraseFile string
+		cf             ceremonyFlags
+	)
+	fs := newFlagSet("keygen", &passphraseFile)
+	cf.register(fs)
+	party := fs.Int("party", 0, "number of this party, from 1 to -parties (required)")
+	parties := fs.Int("parties", 0, "number of parties (required)")
+	threshold := fs.Int("threshold", 0, "threshold; threshold+1 parties are needed to sign (required)")
+	curve := fs.String("curve", string(tss.Secp256k1), "curve: secp256k1 or p256")
+	preParamsFile := fs.String("preparams", "", "encrypted pre-parameters written by preparams; generated if not given")
+	out := fs.String("out", "", "file to write the encrypted key to (required)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	switch {
+	case *out == "":
+		return errors.New("-out is required")
+	case *parties < 2:
+		return errors.New("-parties must be at least 2")
+	case *party < 1 || *parties < *party:
+		return fmt.Errorf("-party must be between 1 and %d", *parties)
+	case *threshold < 1 || *parties <= *threshold:
+		return fmt.Errorf("-threshold must be between 1 and %d", *parties-1)
+	}
+	ec, ok := tss.GetCurveByName(tss.CurveName(*curve))
+	if !ok {
+		return fmt.Errorf("unknown curve %q", *curve)
+	}
+	passphrase, err := readPassphrase(passphraseFile)
+	if err != nil {
+		return err
+	}
+	var optionalPreParams []keygen.LocalPreParams
+	if *preParamsFile != "" {
+		var preParams keygen.LocalPreParams
+		if err = readEncrypted(*preParamsFile, &preParams, passphrase); err != nil {
+			return err
+		}
+		if !preParams.ValidateWithProof() {
+			return fmt.Errorf("%s does not hold valid pre-parameters", *preParamsFile)
+		}
+		optionalPreParams = append(optionalPreParams, preParams)
+	}
+
+	unsorted := make(tss.UnSortedPartyIDs, *parties)
+	for i := range unsorted {
+		unsorted[i] = partyID(i+1, big.NewInt(int64(i+1)))
+	}
+	pIDs := tss.SortPartyIDs(unsorted)
+	self := pIDs[*party-1]
+	params := tss.NewParameters(ec, tss.NewPeerContext(pIDs), self, *parties, *threshold)
+	if err = cf.setSession(params); err != nil {
+		return err
+	}
+	c, err := cf.newCeremony(pIDs, self)
+	if err != nil {
+		return err
+	}
+	outCh := make(chan tss.Message)
+	endCh := make(chan keygen.LocalPartySaveData, 1)
+	P := keygen.NewLocalParty(params, outCh, endCh, optionalPreParams...)
+	if err = c.run(P, outCh); err != nil {
+		return err
+	}
+	key := <-endCh
+	if err = writeEncrypted(*out, &key, passphrase); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "wrote the key of party %d to %s; public key %s\n",
+		*party, *out, hex.EncodeToString(encoding.SerializeCompressed(key.ECDSAPub)))
+	return nil
+}
@@ -0,0 +1,108 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Command tss runs keygen and signing ceremonies between processes on one machine, which talk over Unix sockets
+// or through files in a shared directory. It is the reference integration of the library and a smoke test. The
+// parties are not authenticated, as any process of the user may write to the directory: it is for local testing only.
+//
+// A 2-of-3 key, then a signature by parties 1 and 3, each line run in its own shell:
+//
+//	tss preparams -out pre1.json                    # and pre2.json, pre3.json; slow
+//	tss keygen -party 1 -parties 3 -threshold 1 -preparams pre1.json -out key1.json -dir /tmp/kg -session <id>
+//	tss sign -key key1.json -signers 1,3 -message hello -out sig.json -dir /tmp/sg -session <other id>
+//	tss verify -signature sig.json
+//	tss inspect -key key1.json -diff key2.json      # public fields that disagree between two parties
+//
+// Pre-parameters and keys are saved encrypted, under the passphrase in -passphrase-file or $TSS_PASSPHRASE.
+package main
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strconv"
+
+	"github.com/ipfs/go-log"
+
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+// P256 is registered so that keygen can use it with -curve p256.
+const P256 tss.CurveName = "p256"
+
+type command struct {
+	name, summary string
+	run           func(args []string, stdout io.Writer) error
+}
+
+var commands = []command{
+	{"preparams", "generate the Paillier and NTilde pre-parameters of a party", runPreParams},
+	{"keygen", "run a keygen ceremony as one of the parties", runKeygen},
+	{"sign", "run a signing ceremony as one of the signers", runSign},
+	{"verify", "verify a signature written by sign", runVerify},
+	{"inspect", "print the public fields of a key, or diff those of two keys", runInspect},
+}
+
+func init() {
+	tss.RegisterCurve(P256, elliptic.P256())
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil && !errors.Is(err, flag.ErrHelp) {
+		fmt.Fprintf(os.Stderr, "tss: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		usage()
+		return errors.New("missing command")
+	}
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			return cmd.run(args[1:], stdout)
+		}
+	}
+	usage()
+	return fmt.Errorf("unknown command %q", args[0])
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: tss <command> [flags]; tss <command> -h for the flags of a command")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+}
+
+// newFlagSet returns the flags of a command, with those every command shares.
+func newFlagSet(name string, passphraseFile *string) *flag.FlagSet {
+	fs := flag.NewFlagSet("tss "+name, flag.ContinueOnError)
+	if passphraseFile != nil {
+		fs.StringVar(passphraseFile, "passphrase-file", "", "file holding the passphrase of the save files; defaults to $"+passphraseEnv)
+	}
+	fs.String("log-level", "error", "log level of the library: debug, info, warn or error")
+	return fs
+}
+
+// parseFlags parses the flags of a command and applies -log-level.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	return log.SetLogLevel("tss-lib", fs.Lookup("log-level").Value.String())
+}
+
+// partyID returns the ID of the party numbered `number`, from 1, whose key is `key`. All processes derive the
+// same IDs from the same numbers.
+func partyID(number int, key *big.Int) *tss.PartyID {
+	name := strconv.Itoa(number)
+	return tss.NewPartyID(name, name, key)
+}
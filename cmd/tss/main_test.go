@@ -0,0 +1,227 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+const testPassphrase = "correct horse battery staple"
+
+func writePassphrase(t *testing.T, dir string) string {
+	path := filepath.Join(dir, "passphrase")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(testPassphrase+"\n"), 0600))
+	return path
+}
+
+func TestSaveFileRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "save.json")
+	in := map[string]string{"Xi": "secret"}
+	assert.NoError(t, writeEncrypted(path, in, []byte(testPassphrase)))
+
+	bz, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(bz), "secret", "the payload must not be stored in the clear")
+
+	var out map[string]string
+	assert.NoError(t, readEncrypted(path, &out, []byte(testPassphrase)))
+	assert.Equal(t, in, out)
+	assert.Error(t, readEncrypted(path, &out, []byte("wrong")))
+
+	passphrase, err := readPassphrase(writePassphrase(t, dir))
+	assert.NoError(t, err)
+	assert.Equal(t, testPassphrase, string(passphrase))
+}
+
+func TestParseSigners(t *testing.T) {
+	numbers, err := parseSigners("3, 1", 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 3}, numbers)
+	for _, bad := range []string{"", "1", "1,1", "0,1", "1,4", "1,x"} {
+		_, err = parseSigners(bad, 3)
+		assert.Error(t, err, "signers %q", bad)
+	}
+}
+
+func TestInspectDiff(t *testing.T) {
+	fixtures, _, err := keygen.LoadKeygenTestFixtures(3)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	dir := t.TempDir()
+	passphraseFile := writePassphrase(t, dir)
+	plain, encrypted, tampered := filepath.Join(dir, "plain.json"), filepath.Join(dir, "key.json"), filepath.Join(dir, "tampered.json")
+	bz, err := json.Marshal(&fixtures[0])
+	assert.NoError(t, err)
+	assert.NoError(t, ioutil.WriteFile(plain, bz, 0600))
+	assert.NoError(t, writeEncrypted(encrypted, &fixtures[1], []byte(testPassphrase)))
+	fixtures[2].Ks = append([]*big.Int{}, fixtures[2].Ks...)
+	fixtures[2].Ks[1] = big.NewInt(42)
+	assert.NoError(t, writeEncrypted(tampered, &fixtures[2], []byte(testPassphrase)))
+
+	var stdout bytes.Buffer
+	assert.NoError(t, run([]string{"inspect", "-key", plain}, &stdout), "a plain key needs no passphrase")
+	assert.Contains(t, stdout.String(), fmt.Sprintf("threshold:   %d", keygen.TestThreshold))
+	assert.NotContains(t, stdout.String(), fixtures[0].Xi.String())
+
+	stdout.Reset()
+	assert.NoError(t, run([]string{"inspect", "-key", plain, "-diff", encrypted, "-passphrase-file", passphraseFile}, &stdout))
+	assert.Equal(t, "the public fields agree\n", stdout.String())
+
+	stdout.Reset()
+	assert.Error(t, run([]string{"inspect", "-key", encrypted, "-diff", tampered, "-passphrase-file", passphraseFile}, &stdout))
+	assert.Contains(t, stdout.String(), "Ks[1]:")
+	assert.Contains(t, stdout.String(), "!= 42")
+	assert.NotContains(t, stdout.String(), "Ks[0]")
+}
+
+func TestSignRequiresThresholdSigners(t *testing.T) {
+	fixtures, _, err := keygen.LoadKeygenTestFixtures(1)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	dir := t.TempDir()
+	passphraseFile := writePassphrase(t, dir)
+	key := filepath.Join(dir, "key.json")
+	assert.NoError(t, writeEncrypted(key, &fixtures[0], []byte(testPassphrase)))
+
+	var stdout bytes.Buffer
+	err = run([]string{"sign", "-key", key, "-signers", "1,2", "-message", "hello", "-passphrase-file", passphraseFile,
+		"-dir", filepath.Join(dir, "sg"), "-session", "signing-session-0001"}, &stdout)
+	if assert.Error(t, err, "the fixtures need threshold+1 signers") {
+		assert.Contains(t, err.Error(), fmt.Sprintf("at least %d parties", keygen.TestThreshold+1))
+	}
+}
+
+func TestTransportChecksSender(t *testing.T) {
+	pIDs := tss.SortPartyIDs(tss.UnSortedPartyIDs{partyID(1, big.NewInt(1)), partyID(2, big.NewInt(2)), partyID(3, big.NewInt(3))})
+	dir := t.TempDir()
+	deadline := time.Now().Add(time.Minute)
+	tr, err := newTransport("dir", dir, pIDs[0], deadline)
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = newTransport("dir", dir, pIDs[0], deadline)
+	assert.Error(t, err, "the inbox of an earlier run must be refused")
+
+	peer, err := newTransport("dir", dir, pIDs[1], deadline)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer peer.close()
+	wire, _, err := keygen.NewKGRound3Message(pIDs[1], nil).WireBytes()
+	assert.NoError(t, err)
+	// P[2] sends one frame in its own name and one in P[3]'s
+	assert.NoError(t, peer.send(pIDs[0], encodeFrame(pIDs[1].Index, true, wire)))
+	assert.NoError(t, peer.send(pIDs[0], encodeFrame(pIDs[2].Index, true, wire)))
+	for k := 0; k < 2; k++ {
+		select {
+		case in := <-tr.frames():
+			_, err = decodeFrame(in, pIDs[0], pIDs)
+			if k == 0 {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err, "a frame sent by P[2] in the name of P[3] must be rejected")
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatal("no frame received")
+		}
+	}
+	assert.NoError(t, tr.close())
+
+	_, err = decodeFrame(inbound{frame: encodeFrame(pIDs[0].Index, true, wire)}, pIDs[0], pIDs)
+	assert.Error(t, err, "a frame in the name of the receiver must be rejected")
+}
+
+// runParties runs the command with the arguments of each party concurrently, as separate processes would.
+func runParties(t *testing.T, args [][]string) []string {
+	outs := make([]bytes.Buffer, len(args))
+	errs := make([]error, len(args))
+	var wg sync.WaitGroup
+	for i := range args {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = run(args[i], &outs[i])
+		}(i)
+	}
+	wg.Wait()
+	stdouts := make([]string, len(args))
+	for i := range args {
+		assert.NoError(t, errs[i], "party %d: %v", i+1, args[i])
+		stdouts[i] = outs[i].String()
+	}
+	return stdouts
+}
+
+func TestLocalCeremony(t *testing.T) {
+	fixtures, _, err := keygen.LoadKeygenTestFixtures(3)
+	if err != nil {
+		t.Skip("test fixtures required (LocalPreParams) to run a quick keygen")
+	}
+	dir := t.TempDir()
+	passphraseFile := writePassphrase(t, dir)
+
+	// keygen over Unix sockets
+	var keygenArgs [][]string
+	for i := 1; i <= 3; i++ {
+		preParams := filepath.Join(dir, fmt.Sprintf("pre%d.json", i))
+		assert.NoError(t, writeEncrypted(preParams, fixtures[i-1].LocalPreParams, []byte(testPassphrase)))
+		keygenArgs = append(keygenArgs, []string{"keygen",
+			"-party", fmt.Sprint(i), "-parties", "3", "-threshold", "1",
+			"-preparams", preParams, "-out", filepath.Join(dir, fmt.Sprintf("key%d.json", i)),
+			"-passphrase-file", passphraseFile,
+			"-transport", "unix", "-dir", filepath.Join(dir, "kg"), "-session", "keygen-session-0001"})
+	}
+	outs := runParties(t, keygenArgs)
+	if t.Failed() {
+		return
+	}
+	pub := outs[0][strings.LastIndex(outs[0], " ")+1:]
+	for _, out := range outs {
+		assert.True(t, strings.HasSuffix(out, pub), "every party has the same public key")
+	}
+
+	// signing by parties 1 and 3 through a shared directory
+	var signArgs [][]string
+	for _, i := range []int{1, 3} {
+		signArgs = append(signArgs, []string{"sign",
+			"-key", filepath.Join(dir, fmt.Sprintf("key%d.json", i)), "-signers", "1,3", "-message", "hello",
+			"-out", filepath.Join(dir, fmt.Sprintf("sig%d.json", i)),
+			"-passphrase-file", passphraseFile,
+			"-transport", "dir", "-dir", filepath.Join(dir, "sg"), "-session", "signing-session-0001"})
+	}
+	runParties(t, signArgs)
+	if t.Failed() {
+		return
+	}
+	var stdout bytes.Buffer
+	sig := filepath.Join(dir, "sig1.json")
+	assert.NoError(t, run([]string{"verify", "-signature", sig, "-pubkey", strings.TrimSpace(pub)}, &stdout))
+	assert.Contains(t, stdout.String(), "valid")
+	assert.Error(t, run([]string{"verify", "-signature", sig, "-digest", strings.Repeat("00", 32)}, &stdout))
+
+	stdout.Reset()
+	assert.NoError(t, run([]string{"inspect", "-key", filepath.Join(dir, "key3.json"), "-passphrase-file", passphraseFile}, &stdout))
+	assert.Contains(t, stdout.String(), "party:       3 of 3")
+	assert.Contains(t, stdout.String(), strings.TrimSpace(pub))
+}
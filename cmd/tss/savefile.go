@@ -0,0 +1,143 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package main
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	saveFileVersion = 1
+	// scrypt parameters recommended for interactive logins as of 2017
+	scryptN, scryptR, scryptP = 1 << 15, 8, 1
+	saveFileKeyLen            = 32
+	saveFileSaltLen           = 16
+
+	passphraseEnv = "TSS_PASSPHRASE"
+)
+
+// saveFile is the envelope of an encrypted save file: the JSON of the payload sealed with AES-256-GCM, under a key
+// derived from the passphrase with scrypt.
+type saveFile struct {
+	Version    int
+	KDF        string
+	N, R, P    int
+	Salt       []byte
+	Nonce      []byte
+	Ciphertext []byte
+}
+
+// writeEncrypted seals the JSON encoding of `v` and writes it to `path`, readable by the owner only.
+func writeEncrypted(path string, v interface{}, passphrase []byte) error {
+	plaintext, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	file := saveFile{
+		Version: saveFileVersion,
+		KDF:     "scrypt",
+		N:       scryptN,
+		R:       scryptR,
+		P:       scryptP,
+		Salt:    make([]byte, saveFileSaltLen),
+	}
+	if _, err = io.ReadFull(rand.Reader, file.Salt); err != nil {
+		return err
+	}
+	aead, err := file.aead(passphrase)
+	if err != nil {
+		return err
+	}
+	file.Nonce = make([]byte, aead.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, file.Nonce); err != nil {
+		return err
+	}
+	file.Ciphertext = aead.Seal(nil, file.Nonce, plaintext, nil)
+	bz, err := json.MarshalIndent(&file, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, bz, 0600)
+}
+
+// readEncrypted opens the save file at `path` and decodes its payload into `v`.
+func readEncrypted(path string, v interface{}, passphrase []byte) error {
+	bz, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var file saveFile
+	if err = json.Unmarshal(bz, &file); err != nil {
+		return fmt.Errorf("%s is not an encrypted save file: %v", path, err)
+	}
+	if file.Version != saveFileVersion || file.KDF != "scrypt" {
+		return fmt.Errorf("%s: unsupported save file version %d (%s)", path, file.Version, file.KDF)
+	}
+	// bound the work a crafted file can ask for
+	if scryptN < file.N || scryptR < file.R || scryptP < file.P {
+		return fmt.Errorf("%s: scrypt parameters above the supported maximum", path)
+	}
+	aead, err := file.aead(passphrase)
+	if err != nil {
+		return err
+	}
+	if len(file.Nonce) != aead.NonceSize() {
+		return fmt.Errorf("%s: bad nonce length %d", path, len(file.Nonce))
+	}
+	plaintext, err := aead.Open(nil, file.Nonce, file.Ciphertext, nil)
+	if err != nil {
+		return fmt.Errorf("%s: wrong passphrase or corrupted file", path)
+	}
+	return json.Unmarshal(plaintext, v)
+}
+
+// readPassphrase returns the first line of `path`, or the TSS_PASSPHRASE environment variable if `path` is empty.
+func readPassphrase(path string) ([]byte, error) {
+	if path == "" {
+		passphrase := os.Getenv(passphraseEnv)
+		if passphrase == "" {
+			return nil, fmt.Errorf("set -passphrase-file or %s to encrypt and decrypt save files", passphraseEnv)
+		}
+		return []byte(passphrase), nil
+	}
+	bz, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if i := bytes.IndexAny(bz, "\r\n"); 0 <= i {
+		bz = bz[:i]
+	}
+	if len(bz) == 0 {
+		return nil, errors.New("the passphrase file is empty")
+	}
+	return bz, nil
+}
+
+// ----- //
+
+func (file *saveFile) aead(passphrase []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key(passphrase, file.Salt, file.N, file.R, file.P, saveFileKeyLen)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,236 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/ecdsa/encoding"
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/ecdsa/signing"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+// signatureFile is the output of sign and the input of verify; the byte fields are hex.
+type signatureFile struct {
+	Curve tss.CurveName
+	// PublicKey is in the SEC1 compressed form
+	PublicKey string
+	Digest    string
+	R, S      string
+	Recovery  int
+}
+
+func runSign(args []string, stdout io.Writer) error {
+	var (
+		passphraseFile string
+		cf             ceremonyFlags
+	)
+	fs := newFlagSet("sign", &passphraseFile)
+	cf.register(fs)
+	keyFile := fs.String("key", "", "encrypted key written by keygen (required)")
+	signers := fs.String("signers", "", "comma-separated numbers of the signing parties, including this one (required)")
+	digestHex := fs.String("digest", "", "hex digest to sign")
+	message := fs.String("message", "", "message to sign, hashed with SHA-256; instead of -digest")
+	out := fs.String("out", "", "file to write the signature to; defaults to the standard output")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *keyFile == "" {
+		return errors.New("-key is required")
+	}
+	var digest []byte
+	switch {
+	case *digestHex != "" && *message != "":
+		return errors.New("give -digest or -message, not both")
+	case *digestHex != "":
+		var err error
+		if digest, err = hex.DecodeString(*digestHex); err != nil || len(digest) == 0 {
+			return errors.New("-digest must be non-empty hex")
+		}
+	case *message != "":
+		sum := sha256.Sum256([]byte(*message))
+		digest = sum[:]
+	default:
+		return errors.New("-digest or -message is required")
+	}
+	passphrase, err := readPassphrase(passphraseFile)
+	if err != nil {
+		return err
+	}
+	var key keygen.LocalPartySaveData
+	if err = readEncrypted(*keyFile, &key, passphrase); err != nil {
+		return err
+	}
+	info, err := key.Inspect()
+	if err != nil {
+		return err
+	}
+	if info.Threshold < 0 {
+		return errors.New("the public shares of the key do not match its public key")
+	}
+	numbers, err := parseSigners(*signers, len(key.Ks))
+	if err != nil {
+		return err
+	}
+	if len(numbers) <= info.Threshold {
+		return fmt.Errorf("-signers: the key has threshold %d, so at least %d parties must sign", info.Threshold, info.Threshold+1)
+	}
+	self, err := key.OriginalIndex()
+	if err != nil {
+		return err
+	}
+
+	unsorted := make(tss.UnSortedPartyIDs, 0, len(numbers))
+	for _, number := range numbers {
+		unsorted = append(unsorted, partyID(number, key.Ks[number-1]))
+	}
+	pIDs := tss.SortPartyIDs(unsorted)
+	var selfID *tss.PartyID
+	for _, pID := range pIDs {
+		if pID.Moniker == strconv.Itoa(self+1) {
+			selfID = pID
+		}
+	}
+	if selfID == nil {
+		return fmt.Errorf("this key belongs to party %d, which is not one of the signers", self+1)
+	}
+	ec := key.ECDSAPub.Curve()
+	params := tss.NewParameters(ec, tss.NewPeerContext(pIDs), selfID, len(pIDs), info.Threshold)
+	if err = cf.setSession(params); err != nil {
+		return err
+	}
+	c, err := cf.newCeremony(pIDs, selfID)
+	if err != nil {
+		return err
+	}
+	outCh := make(chan tss.Message)
+	endCh := make(chan common.SignatureData, 1)
+	P := signing.NewLocalParty(new(big.Int).SetBytes(digest), params, key, outCh, endCh, len(digest))
+	if err = c.run(P, outCh); err != nil {
+		return err
+	}
+	// R || S, both padded to the byte length of the curve order
+	rs := (<-endCh).Signature
+	r, s := rs[:len(rs)/2], rs[len(rs)/2:]
+	curve, _ := tss.GetCurveName(ec)
+	bz, err := json.MarshalIndent(&signatureFile{
+		Curve:     curve,
+		PublicKey: hex.EncodeToString(encoding.SerializeCompressed(key.ECDSAPub)),
+		Digest:    hex.EncodeToString(digest),
+		R:         hex.EncodeToString(r),
+		S:         hex.EncodeToString(s),
+		Recovery:  recoveryID(ec, r, s, digest, key.ECDSAPub),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if *out == "" {
+		_, err = fmt.Fprintln(stdout, string(bz))
+		return err
+	}
+	return ioutil.WriteFile(*out, bz, 0644)
+}
+
+func runVerify(args []string, stdout io.Writer) error {
+	fs := newFlagSet("verify", nil)
+	sigFile := fs.String("signature", "", "signature written by sign (required)")
+	pubKeyHex := fs.String("pubkey", "", "hex SEC1 public key the signature must be by; defaults to the one in the file")
+	digestHex := fs.String("digest", "", "hex digest the signature must be of; defaults to the one in the file")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *sigFile == "" {
+		return errors.New("-signature is required")
+	}
+	bz, err := ioutil.ReadFile(*sigFile)
+	if err != nil {
+		return err
+	}
+	var sig signatureFile
+	if err = json.Unmarshal(bz, &sig); err != nil {
+		return fmt.Errorf("%s is not a signature file: %v", *sigFile, err)
+	}
+	ec, ok := tss.GetCurveByName(sig.Curve)
+	if !ok {
+		return fmt.Errorf("unknown curve %q", sig.Curve)
+	}
+	if *pubKeyHex == "" {
+		*pubKeyHex = sig.PublicKey
+	}
+	if *digestHex == "" {
+		*digestHex = sig.Digest
+	}
+	var pubBz, digest, r, s []byte
+	for _, field := range []struct {
+		name, hex string
+		out       *[]byte
+	}{{"public key", *pubKeyHex, &pubBz}, {"digest", *digestHex, &digest}, {"R", sig.R, &r}, {"S", sig.S, &s}} {
+		if *field.out, err = hex.DecodeString(field.hex); err != nil {
+			return fmt.Errorf("bad %s: %v", field.name, err)
+		}
+	}
+	pub, err := encoding.ParsePublicKey(ec, pubBz)
+	if err != nil {
+		return err
+	}
+	if !ecdsa.Verify(pub.ToECDSAPubKey(), digest, new(big.Int).SetBytes(r), new(big.Int).SetBytes(s)) {
+		return errors.New("the signature is NOT valid")
+	}
+	_, err = fmt.Fprintln(stdout, "the signature is valid")
+	return err
+}
+
+// recoveryID returns the recovery byte that recovers `pub` from the signature, or -1 if none does.
+func recoveryID(ec elliptic.Curve, r, s, digest []byte, pub *crypto.ECPoint) int {
+	for id := 0; id < 4; id++ {
+		data := &common.SignatureData{R: r, S: s, SignatureRecovery: []byte{byte(id)}}
+		if encoding.CheckRecovery(ec, data, digest, pub) == nil {
+			return id
+		}
+	}
+	return -1
+}
+
+// parseSigners parses the comma-separated party numbers of -signers, each between 1 and `parties`.
+func parseSigners(list string, parties int) ([]int, error) {
+	if list == "" {
+		return nil, errors.New("-signers is required")
+	}
+	seen := make(map[int]bool)
+	var numbers []int
+	for _, field := range strings.Split(list, ",") {
+		number, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || number < 1 || parties < number {
+			return nil, fmt.Errorf("-signers: %q is not a party number between 1 and %d", field, parties)
+		}
+		if seen[number] {
+			return nil, fmt.Errorf("-signers: party %d is listed twice", number)
+		}
+		seen[number] = true
+		numbers = append(numbers, number)
+	}
+	if len(numbers) < 2 {
+		return nil, errors.New("-signers needs at least 2 parties")
+	}
+	sort.Ints(numbers)
+	return numbers, nil
+}
@@ -0,0 +1,330 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+const (
+	// a frame is the sender's index in the ceremony, a broadcast flag, then the wire bytes of the message
+	frameHeaderLen = 5
+	maxFrameLen    = 16 << 20
+
+	dialRetry = 50 * time.Millisecond
+	pollEvery = 20 * time.Millisecond
+
+	// startedMarker is created in the inbox of a party when its dir transport starts
+	startedMarker = ".started"
+)
+
+type (
+	// transport carries frames between the parties of one ceremony, which meet in a shared directory. Neither
+	// transport authenticates the peers: any process that may write to the directory can send a frame in the name
+	// of any party, so they are only fit for local testing by a single user.
+	transport interface {
+		send(to *tss.PartyID, frame []byte) error
+		// frames delivers the frames sent to this party, in the order each peer sent them
+		frames() <-chan inbound
+		close() error
+	}
+
+	// inbound is a received frame with the moniker of the party the transport got it from, if it knows it.
+	inbound struct {
+		origin string
+		frame  []byte
+	}
+
+	// unixTransport listens on <dir>/<moniker>.sock and dials the sockets of its peers. A connection carries the
+	// frames of a single sender; it is dropped when a frame claims another.
+	unixTransport struct {
+		dir      string
+		deadline time.Time
+		ln       net.Listener
+		in       chan inbound
+		quit     chan struct{}
+
+		mtx   sync.Mutex
+		conns map[string]net.Conn
+	}
+
+	// dirTransport writes each frame to a file in the inbox <dir>/<moniker>/ of the recipient, named after the
+	// sender, and polls its own. It refuses an inbox that an earlier run of the same party used, whose frames
+	// would be replayed.
+	dirTransport struct {
+		dir, self string
+		in        chan inbound
+		quit      chan struct{}
+
+		mtx sync.Mutex
+		seq uint64
+	}
+)
+
+func newTransport(kind, dir string, self *tss.PartyID, deadline time.Time) (transport, error) {
+	if dir == "" {
+		return nil, errors.New("-dir is required")
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	switch kind {
+	case "unix":
+		return newUnixTransport(dir, self, deadline)
+	case "dir":
+		return newDirTransport(dir, self)
+	default:
+		return nil, fmt.Errorf("unknown transport %q; use unix or dir", kind)
+	}
+}
+
+func encodeFrame(from int, isBroadcast bool, wire []byte) []byte {
+	frame := make([]byte, frameHeaderLen+len(wire))
+	binary.BigEndian.PutUint32(frame, uint32(from))
+	if isBroadcast {
+		frame[4] = 1
+	}
+	copy(frame[frameHeaderLen:], wire)
+	return frame
+}
+
+// decodeFrame parses a frame received by `self` with tss.ParseWireMessage, `pIDs` being the parties of the ceremony.
+// The sender in the header must be a peer and, if the transport knows where the frame came from, that party.
+func decodeFrame(in inbound, self *tss.PartyID, pIDs tss.SortedPartyIDs) (tss.ParsedMessage, error) {
+	frame := in.frame
+	if len(frame) < frameHeaderLen {
+		return nil, errors.New("short frame")
+	}
+	from := int(binary.BigEndian.Uint32(frame))
+	if len(pIDs) <= from || from == self.Index {
+		return nil, fmt.Errorf("frame from unknown party %d", from)
+	}
+	if in.origin != "" && in.origin != pIDs[from].Moniker {
+		return nil, fmt.Errorf("frame from party %s claims to be from %s", in.origin, pIDs[from])
+	}
+	return tss.ParseWireMessage(frame[frameHeaderLen:], pIDs[from], frame[4] == 1)
+}
+
+// ----- //
+
+func newUnixTransport(dir string, self *tss.PartyID, deadline time.Time) (*unixTransport, error) {
+	path := socketPath(dir, self)
+	// a socket left behind by an earlier run of the same party
+	_ = os.Remove(path)
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	t := &unixTransport{
+		dir:      dir,
+		deadline: deadline,
+		ln:       ln,
+		in:       make(chan inbound, 64),
+		quit:     make(chan struct{}),
+		conns:    make(map[string]net.Conn),
+	}
+	go t.accept()
+	return t, nil
+}
+
+func (t *unixTransport) send(to *tss.PartyID, frame []byte) error {
+	conn, err := t.conn(to)
+	if err != nil {
+		return err
+	}
+	header := make([]byte, 4)
+	binary.BigEndian.PutUint32(header, uint32(len(frame)))
+	if _, err = conn.Write(append(header, frame...)); err != nil {
+		return fmt.Errorf("sending to %s: %v", to, err)
+	}
+	return nil
+}
+
+func (t *unixTransport) frames() <-chan inbound {
+	return t.in
+}
+
+func (t *unixTransport) close() error {
+	close(t.quit)
+	t.mtx.Lock()
+	for _, conn := range t.conns {
+		_ = conn.Close()
+	}
+	t.mtx.Unlock()
+	return t.ln.Close()
+}
+
+// conn dials the peer, waiting for it to listen until the deadline.
+func (t *unixTransport) conn(to *tss.PartyID) (net.Conn, error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if conn, ok := t.conns[to.Moniker]; ok {
+		return conn, nil
+	}
+	for {
+		conn, err := net.Dial("unix", socketPath(t.dir, to))
+		if err == nil {
+			t.conns[to.Moniker] = conn
+			return conn, nil
+		}
+		if time.Now().After(t.deadline) {
+			return nil, fmt.Errorf("party %s is not listening: %v", to, err)
+		}
+		time.Sleep(dialRetry)
+	}
+}
+
+func (t *unixTransport) accept() {
+	for {
+		conn, err := t.ln.Accept()
+		if err != nil {
+			return
+		}
+		go t.read(conn)
+	}
+}
+
+func (t *unixTransport) read(conn net.Conn) {
+	defer conn.Close()
+	header := make([]byte, 4)
+	var sender []byte
+	for {
+		if _, err := io.ReadFull(conn, header); err != nil {
+			return
+		}
+		n := binary.BigEndian.Uint32(header)
+		if maxFrameLen < n || n < frameHeaderLen {
+			return
+		}
+		frame := make([]byte, n)
+		if _, err := io.ReadFull(conn, frame); err != nil {
+			return
+		}
+		if sender == nil {
+			sender = frame[:4]
+		} else if !bytes.Equal(sender, frame[:4]) {
+			return
+		}
+		select {
+		case t.in <- inbound{frame: frame}:
+		case <-t.quit:
+			return
+		}
+	}
+}
+
+func socketPath(dir string, party *tss.PartyID) string {
+	return filepath.Join(dir, party.Moniker+".sock")
+}
+
+// ----- //
+
+func newDirTransport(dir string, self *tss.PartyID) (*dirTransport, error) {
+	inbox := filepath.Join(dir, self.Moniker)
+	if err := os.MkdirAll(inbox, 0700); err != nil {
+		return nil, err
+	}
+	// peers may write to the inbox before this party starts, so only the marker tells a stale inbox apart
+	marker, err := os.OpenFile(filepath.Join(inbox, startedMarker), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if os.IsExist(err) {
+		return nil, fmt.Errorf("%s was used by an earlier run; use a fresh -dir per ceremony", inbox)
+	}
+	if err != nil {
+		return nil, err
+	}
+	_ = marker.Close()
+	t := &dirTransport{
+		dir:  dir,
+		self: self.Moniker,
+		in:   make(chan inbound, 64),
+		quit: make(chan struct{}),
+	}
+	go t.poll()
+	return t, nil
+}
+
+// send writes the frame under a temporary name and renames it, so the recipient never reads a partial frame.
+func (t *dirTransport) send(to *tss.PartyID, frame []byte) error {
+	inbox := filepath.Join(t.dir, to.Moniker)
+	if err := os.MkdirAll(inbox, 0700); err != nil {
+		return err
+	}
+	t.mtx.Lock()
+	t.seq++
+	name := fmt.Sprintf("%s-%012d.msg", t.self, t.seq)
+	t.mtx.Unlock()
+	tmp := filepath.Join(inbox, "."+name)
+	if err := ioutil.WriteFile(tmp, frame, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(inbox, name))
+}
+
+func (t *dirTransport) frames() <-chan inbound {
+	return t.in
+}
+
+func (t *dirTransport) close() error {
+	close(t.quit)
+	return nil
+}
+
+func (t *dirTransport) poll() {
+	inbox := filepath.Join(t.dir, t.self)
+	ticker := time.NewTicker(pollEvery)
+	defer ticker.Stop()
+	for {
+		infos, err := ioutil.ReadDir(inbox)
+		if err != nil {
+			return
+		}
+		var names []string
+		for _, info := range infos {
+			if name := info.Name(); strings.HasSuffix(name, ".msg") && !strings.HasPrefix(name, ".") {
+				names = append(names, name)
+			}
+		}
+		// the sequence numbers are zero-padded, so this keeps each sender's frames in order
+		sort.Strings(names)
+		for _, name := range names {
+			path := filepath.Join(inbox, name)
+			frame, err := ioutil.ReadFile(path)
+			if err != nil {
+				continue
+			}
+			_ = os.Remove(path)
+			origin := strings.TrimSuffix(name, ".msg")
+			if i := strings.LastIndex(origin, "-"); 0 <= i {
+				origin = origin[:i]
+			}
+			select {
+			case t.in <- inbound{origin: origin, frame: frame}:
+			case <-t.quit:
+				return
+			}
+		}
+		select {
+		case <-ticker.C:
+		case <-t.quit:
+			return
+		}
+	}
+}
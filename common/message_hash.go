@@ -0,0 +1,69 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package common
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// MessageHash identifies the hash function applied to a raw message before it is signed.
+// The value is recorded in SignatureData.HashId.
+type MessageHash uint32
+
+const (
+	// MessageHashNone means the caller supplied the digest itself
+	MessageHashNone MessageHash = iota
+	MessageHashSHA256
+	// MessageHashDoubleSHA256 is SHA-256(SHA-256(msg)), as used by Bitcoin
+	MessageHashDoubleSHA256
+	// MessageHashKeccak256 is the original Keccak-256 used by Ethereum, not FIPS-202 SHA3-256
+	MessageHashKeccak256
+	MessageHashSHA512_256
+)
+
+// Digest hashes `msg` with the selected function
+func (h MessageHash) Digest(msg []byte) ([]byte, error) {
+	switch h {
+	case MessageHashSHA256:
+		sum := sha256.Sum256(msg)
+		return sum[:], nil
+	case MessageHashDoubleSHA256:
+		first := sha256.Sum256(msg)
+		sum := sha256.Sum256(first[:])
+		return sum[:], nil
+	case MessageHashKeccak256:
+		state := sha3.NewLegacyKeccak256()
+		_, _ = state.Write(msg)
+		return state.Sum(nil), nil
+	case MessageHashSHA512_256:
+		sum := sha512.Sum512_256(msg)
+		return sum[:], nil
+	default:
+		return nil, fmt.Errorf("unsupported message hash %s", h)
+	}
+}
+
+func (h MessageHash) String() string {
+	switch h {
+	case MessageHashNone:
+		return "none"
+	case MessageHashSHA256:
+		return "sha256"
+	case MessageHashDoubleSHA256:
+		return "double-sha256"
+	case MessageHashKeccak256:
+		return "keccak256"
+	case MessageHashSHA512_256:
+		return "sha512/256"
+	default:
+		return fmt.Sprintf("MessageHash(%d)", uint32(h))
+	}
+}
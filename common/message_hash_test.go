@@ -0,0 +1,44 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package common_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/bnb-chain/tss-lib/common"
+)
+
+func TestMessageHashDigest(t *testing.T) {
+	tests := []struct {
+		hash common.MessageHash
+		msg  string
+		want string
+	}{
+		{common.MessageHashSHA256, "abc", "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		{common.MessageHashDoubleSHA256, "abc", "4f8b42c22dd3729b519ba6f68d2da7cc5b2d606d05daed5ad5128cc03e6c6358"},
+		{common.MessageHashKeccak256, "", "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470"},
+		{common.MessageHashSHA512_256, "abc", "53048e2681941ef99b2e29b76b4c7dabe4c2d0c634fc6d46e0e2f13107e7af23"},
+	}
+	for _, tt := range tests {
+		got, err := tt.hash.Digest([]byte(tt.msg))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.hash, err)
+		}
+		if hex.EncodeToString(got) != tt.want {
+			t.Errorf("%s(%q) = %x, want %s", tt.hash, tt.msg, got, tt.want)
+		}
+	}
+}
+
+func TestMessageHashDigestRejectsUnknown(t *testing.T) {
+	for _, h := range []common.MessageHash{common.MessageHashNone, common.MessageHash(99)} {
+		if _, err := h.Digest([]byte("abc")); err == nil {
+			t.Errorf("%s must be rejected", h)
+		}
+	}
+}
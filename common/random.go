@@ -7,8 +7,11 @@
 package common
 
 import (
-	"crypto/rand"
+	"crypto/aes"
+	"crypto/cipher"
+	cryptorand "crypto/rand"
 	"fmt"
+	"io"
 	"math/big"
 
 	"github.com/pkg/errors"
@@ -20,6 +23,11 @@ const (
 
 // MustGetRandomInt panics if it is unable to gather entropy from `rand.Reader` or when `bits` is <= 0
 func MustGetRandomInt(bits int) *big.Int {
+	return MustGetRandomIntFrom(cryptorand.Reader, bits)
+}
+
+// MustGetRandomIntFrom is MustGetRandomInt drawing from `rand`, e.g. a seeded reader in tests
+func MustGetRandomIntFrom(rand io.Reader, bits int) *big.Int {
 	if bits <= 0 || mustGetRandomIntMaxBits < bits {
 		panic(fmt.Errorf("MustGetRandomInt: bits should be positive, non-zero and less than %d", mustGetRandomIntMaxBits))
 	}
@@ -28,7 +36,7 @@ func MustGetRandomInt(bits int) *big.Int {
 	max = max.Exp(two, big.NewInt(int64(bits)), nil).Sub(max, one)
 
 	// Generate cryptographically strong pseudo-random int between 0 - max
-	n, err := rand.Int(rand.Reader, max)
+	n, err := cryptorand.Int(rand, max)
 	if err != nil {
 		panic(errors.Wrap(err, "rand.Int failure in MustGetRandomInt!"))
 	}
@@ -36,12 +44,16 @@ func MustGetRandomInt(bits int) *big.Int {
 }
 
 func GetRandomPositiveInt(lessThan *big.Int) *big.Int {
+	return GetRandomPositiveIntFrom(cryptorand.Reader, lessThan)
+}
+
+func GetRandomPositiveIntFrom(rand io.Reader, lessThan *big.Int) *big.Int {
 	if lessThan == nil || lessThan.Cmp(one) <= 0 {
 		return nil
 	}
 	var try *big.Int
 	for {
-		try = MustGetRandomInt(lessThan.BitLen())
+		try = MustGetRandomIntFrom(rand, lessThan.BitLen())
 		if try.Cmp(lessThan) < 0 && try.Sign() > 0 {
 			break
 		}
@@ -49,13 +61,13 @@ func GetRandomPositiveInt(lessThan *big.Int) *big.Int {
 	return try
 }
 
-func getRandomNonNegativeInt(lessThan *big.Int) *big.Int {
+func getRandomNonNegativeInt(rand io.Reader, lessThan *big.Int) *big.Int {
 	if lessThan == nil || lessThan.Sign() <= 0 {
 		return nil
 	}
 	var try *big.Int
 	for {
-		try = MustGetRandomInt(lessThan.BitLen())
+		try = MustGetRandomIntFrom(rand, lessThan.BitLen())
 		if try.Cmp(lessThan) < 0 {
 			break
 		}
@@ -65,6 +77,10 @@ func getRandomNonNegativeInt(lessThan *big.Int) *big.Int {
 
 // Sample an integer in range (-limit, limit)
 func GetRandomInt(limit *big.Int) *big.Int {
+	return GetRandomIntFrom(cryptorand.Reader, limit)
+}
+
+func GetRandomIntFrom(rand io.Reader, limit *big.Int) *big.Int {
 	if limit == nil || limit.Sign() <= 0 {
 		return nil
 	}
@@ -72,21 +88,30 @@ func GetRandomInt(limit *big.Int) *big.Int {
 	limitDoubleMinus1 := new(big.Int).Add(limit, limitMinus1)
 	// get an integer in [0, 2*limit-1) and subtract limit-1
 	// to get an integer in [-limit+1, limit-1]
-	i := getRandomNonNegativeInt(limitDoubleMinus1)
+	i := getRandomNonNegativeInt(rand, limitDoubleMinus1)
 	i = i.Sub(i, limitMinus1)
 	return i
 }
 
 func GetRandomPrimeInt(bits int) *big.Int {
+	return GetRandomPrimeIntFrom(cryptorand.Reader, bits)
+}
+
+func GetRandomPrimeIntFrom(rand io.Reader, bits int) *big.Int {
 	if bits <= 0 {
 		return nil
 	}
-	try, err := rand.Prime(rand.Reader, bits)
-	if err != nil ||
+	var try *big.Int
+	var err error
+	// recent versions of crypto/rand.Prime ignore any reader but crypto/rand's own
+	if rand == cryptorand.Reader {
+		try, err = cryptorand.Prime(rand, bits)
+	}
+	if try == nil || err != nil ||
 		try.Cmp(zero) == 0 {
 		// fallback to older method
 		for {
-			try = MustGetRandomInt(bits)
+			try = MustGetRandomIntFrom(rand, bits)
 			if probablyPrime(try) {
 				break
 			}
@@ -98,12 +123,16 @@ func GetRandomPrimeInt(bits int) *big.Int {
 // Generate a random element in the group of all the elements in Z/nZ that
 // has a multiplicative inverse.
 func GetRandomPositiveRelativelyPrimeInt(n *big.Int) *big.Int {
+	return GetRandomPositiveRelativelyPrimeIntFrom(cryptorand.Reader, n)
+}
+
+func GetRandomPositiveRelativelyPrimeIntFrom(rand io.Reader, n *big.Int) *big.Int {
 	if n == nil || zero.Cmp(n) != -1 {
 		return nil
 	}
 	var try *big.Int
 	for {
-		try = MustGetRandomInt(n.BitLen())
+		try = MustGetRandomIntFrom(rand, n.BitLen())
 		if IsNumberInMultiplicativeGroup(n, try) {
 			break
 		}
@@ -125,22 +154,61 @@ func IsNumberInMultiplicativeGroup(n, v *big.Int) bool {
 //
 // https://github.com/didiercrunch/paillier/blob/d03e8850a8e4c53d04e8016a2ce8762af3278b71/utils.go#L39
 func GetRandomGeneratorOfTheQuadraticResidue(n *big.Int) *big.Int {
-	f := GetRandomPositiveRelativelyPrimeInt(n)
+	return GetRandomGeneratorOfTheQuadraticResidueFrom(cryptorand.Reader, n)
+}
+
+func GetRandomGeneratorOfTheQuadraticResidueFrom(rand io.Reader, n *big.Int) *big.Int {
+	f := GetRandomPositiveRelativelyPrimeIntFrom(rand, n)
 	fSq := new(big.Int).Mul(f, f)
 	return fSq.Mod(fSq, n)
 }
 
 // Sample an integer in range (-2^power, 2^power)
 func GetRandomIntIn2PowerRange(power uint) *big.Int {
+	return GetRandomIntIn2PowerRangeFrom(cryptorand.Reader, power)
+}
+
+func GetRandomIntIn2PowerRangeFrom(rand io.Reader, power uint) *big.Int {
 	limit := big.NewInt(1)
 	limit.Lsh(limit, power)
-	return GetRandomInt(limit)
+	return GetRandomIntFrom(rand, limit)
 }
 
 // Sample an integer in range (-2^power * multiplier, 2^power * multiplier)
 func GetRandomIntIn2PowerMulRange(power uint, multiplier *big.Int) *big.Int {
+	return GetRandomIntIn2PowerMulRangeFrom(cryptorand.Reader, power, multiplier)
+}
+
+func GetRandomIntIn2PowerMulRangeFrom(rand io.Reader, power uint, multiplier *big.Int) *big.Int {
 	limit := big.NewInt(1)
 	limit.Lsh(limit, power)
 	limit.Mul(limit, multiplier)
-	return GetRandomInt(limit)
+	return GetRandomIntFrom(rand, limit)
+}
+
+// ForkRand returns a reader for a goroutine of its own. crypto/rand's Reader is safe for concurrent use and is
+// returned as it is; any other reader, e.g. a seeded one in tests, keys a stream of its own with 32 bytes read
+// from it now, so that what each goroutine draws doesn't depend on how the goroutines are scheduled.
+func ForkRand(rand io.Reader) io.Reader {
+	if rand == cryptorand.Reader {
+		return rand
+	}
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(rand, key); err != nil {
+		panic(errors.Wrap(err, "ForkRand: unable to read the key of the stream"))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		panic(err)
+	}
+	return cipher.StreamReader{S: cipher.NewCTR(block, make([]byte, aes.BlockSize)), R: zeroReader{}}
+}
+
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+	return len(p), nil
 }
@@ -7,7 +7,10 @@
 package common_test
 
 import (
+	"crypto/rand"
+	"io"
 	"math/big"
+	mrand "math/rand"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -57,3 +60,37 @@ func TestGetRandomPrimeInt(t *testing.T) {
 	assert.NotZero(t, prime, "rand prime should not be zero")
 	assert.True(t, prime.ProbablyPrime(50), "rand prime should be prime")
 }
+
+func TestGetRandomIntsFromSeededReaderAreReproducible(t *testing.T) {
+	limit := common.MustGetRandomInt(randomIntBitLen)
+	draw := func() []*big.Int {
+		rand := mrand.New(mrand.NewSource(1))
+		return []*big.Int{
+			common.MustGetRandomIntFrom(rand, randomIntBitLen),
+			common.GetRandomPositiveIntFrom(rand, limit),
+			common.GetRandomIntFrom(rand, limit),
+			common.GetRandomPositiveRelativelyPrimeIntFrom(rand, limit),
+			common.GetRandomPrimeIntFrom(rand, 256),
+		}
+	}
+	first := draw()
+	assert.Equal(t, first, draw())
+	assert.True(t, first[4].ProbablyPrime(50), "rand prime should be prime")
+}
+
+func TestForkRand(t *testing.T) {
+	assert.Equal(t, rand.Reader, common.ForkRand(rand.Reader), "crypto/rand needs no fork")
+
+	read := func(r io.Reader) []byte {
+		buf := make([]byte, 64)
+		_, err := io.ReadFull(r, buf)
+		assert.NoError(t, err)
+		return buf
+	}
+	seeded := mrand.New(mrand.NewSource(1))
+	fork1, fork2 := common.ForkRand(seeded), common.ForkRand(seeded)
+	assert.NotEqual(t, read(fork1), read(fork2), "forks of one reader must differ")
+
+	again := common.ForkRand(mrand.New(mrand.NewSource(1)))
+	assert.Equal(t, read(common.ForkRand(mrand.New(mrand.NewSource(1)))), read(again))
+}
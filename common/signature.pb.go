@@ -40,6 +40,9 @@ type SignatureData struct {
 	S []byte `protobuf:"bytes,4,opt,name=s,proto3" json:"s,omitempty"`
 	// M represents the original message digest that was signed M
 	M []byte `protobuf:"bytes,5,opt,name=m,proto3" json:"m,omitempty"`
+	// Identifies the hash function that produced M from a raw message (see common.MessageHash);
+	// zero when the signer was given a pre-hashed message
+	HashId uint32 `protobuf:"varint,6,opt,name=hash_id,json=hashId,proto3" json:"hash_id,omitempty"`
 }
 
 func (x *SignatureData) Reset() {
@@ -109,12 +112,19 @@ func (x *SignatureData) GetM() []byte {
 	return nil
 }
 
+func (x *SignatureData) GetHashId() uint32 {
+	if x != nil {
+		return x.HashId
+	}
+	return 0
+}
+
 var File_protob_signature_proto protoreflect.FileDescriptor
 
 var file_protob_signature_proto_rawDesc = []byte{
 	0x0a, 0x16, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x2f, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75,
 	0x72, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0e, 0x62, 0x69, 0x6e, 0x61, 0x6e, 0x63,
-	0x65, 0x2e, 0x74, 0x73, 0x73, 0x6c, 0x69, 0x62, 0x22, 0x86, 0x01, 0x0a, 0x0d, 0x53, 0x69, 0x67,
+	0x65, 0x2e, 0x74, 0x73, 0x73, 0x6c, 0x69, 0x62, 0x22, 0x9f, 0x01, 0x0a, 0x0d, 0x53, 0x69, 0x67,
 	0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x44, 0x61, 0x74, 0x61, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69,
 	0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73,
 	0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x2d, 0x0a, 0x12, 0x73, 0x69, 0x67, 0x6e,
@@ -123,8 +133,9 @@ var file_protob_signature_proto_rawDesc = []byte{
 	0x65, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x12, 0x0c, 0x0a, 0x01, 0x72, 0x18, 0x03, 0x20, 0x01,
 	0x28, 0x0c, 0x52, 0x01, 0x72, 0x12, 0x0c, 0x0a, 0x01, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c,
 	0x52, 0x01, 0x73, 0x12, 0x0c, 0x0a, 0x01, 0x6d, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x01,
-	0x6d, 0x42, 0x0a, 0x5a, 0x08, 0x2e, 0x2f, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x62, 0x06, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x6d, 0x12, 0x17, 0x0a, 0x07, 0x68, 0x61, 0x73, 0x68, 0x5f, 0x69, 0x64, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x06, 0x68, 0x61, 0x73, 0x68, 0x49, 0x64, 0x42, 0x0a, 0x5a, 0x08, 0x2e, 0x2f,
+	0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
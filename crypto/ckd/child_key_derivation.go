@@ -11,12 +11,14 @@ import (
 	"crypto/sha512"
 	"encoding/binary"
 	"errors"
+	"fmt"
 	"hash"
 	"math/big"
 
 	"github.com/bnb-chain/tss-lib/common"
 	"github.com/bnb-chain/tss-lib/crypto"
 	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcutil/base58"
 	"golang.org/x/crypto/ripemd160"
 )
@@ -49,6 +51,10 @@ const (
 
 	serializedKeyLen = 78
 
+	chainCodeLen = 32
+
+	fingerprintLen = 4
+
 	// MinSeedBytes is the minimum number of bytes allowed for a seed to
 	// a master node.
 	MinSeedBytes = 16 // 128 bits
@@ -58,6 +64,47 @@ const (
 	MaxSeedBytes = 64 // 512 bits
 )
 
+// NewMasterPublicKey returns the depth 0 extended public key of `pub` and `chainCode`, with the version bytes of
+// `net` and `script` (xpub, tpub, ypub, zpub, ...) and a zero parent fingerprint.
+func NewMasterPublicKey(pub *ecdsa.PublicKey, chainCode []byte, net *chaincfg.Params, script ScriptType) (*ExtendedKey, error) {
+	if pub == nil || pub.Curve == nil || pub.X == nil || !pub.Curve.IsOnCurve(pub.X, pub.Y) {
+		return nil, errors.New("master public key is not a curve point")
+	}
+	if len(chainCode) != chainCodeLen {
+		return nil, fmt.Errorf("chain code must be %d bytes, got %d", chainCodeLen, len(chainCode))
+	}
+	version, err := PublicVersion(net, script)
+	if err != nil {
+		return nil, err
+	}
+	return &ExtendedKey{
+		PublicKey:  *pub,
+		Depth:      0,
+		ChildIndex: 0,
+		ChainCode:  append([]byte(nil), chainCode...),
+		ParentFP:   make([]byte, fingerprintLen),
+		Version:    version,
+	}, nil
+}
+
+// Fingerprint returns the BIP-32 fingerprint of the key: the first 4 bytes of HASH160 of its compressed public key.
+// It is the ParentFP of the key's children.
+func (k *ExtendedKey) Fingerprint() []byte {
+	return hash160(serializeCompressed(k.X, k.Y))[:fingerprintLen]
+}
+
+// WithVersion returns a copy of the key carrying the version bytes of `net` and `script`, e.g. to export an xpub as
+// a zpub. The key material is unchanged.
+func (k *ExtendedKey) WithVersion(net *chaincfg.Params, script ScriptType) (*ExtendedKey, error) {
+	version, err := PublicVersion(net, script)
+	if err != nil {
+		return nil, err
+	}
+	out := *k
+	out.Version = version
+	return &out, nil
+}
+
 // Extended public key serialization, defined in BIP32
 func (k *ExtendedKey) String() string {
 	// version(4) || depth(1) || parentFP (4) || childinde(4) || chaincode (32) || key(33) || checksum(4)
@@ -65,9 +112,9 @@ func (k *ExtendedKey) String() string {
 	binary.BigEndian.PutUint32(childNumBytes[:], k.ChildIndex)
 
 	serializedBytes := make([]byte, 0, serializedKeyLen+4)
-	serializedBytes = append(serializedBytes, k.Version...)
+	serializedBytes = append(serializedBytes, paddedBytes(4, k.Version)...)
 	serializedBytes = append(serializedBytes, k.Depth)
-	serializedBytes = append(serializedBytes, k.ParentFP...)
+	serializedBytes = append(serializedBytes, paddedBytes(fingerprintLen, k.ParentFP)...)
 	serializedBytes = append(serializedBytes, childNumBytes[:]...)
 	serializedBytes = append(serializedBytes, k.ChainCode...)
 	pubKeyBytes := serializeCompressed(k.PublicKey.X, k.PublicKey.Y)
@@ -103,6 +150,13 @@ func NewExtendedKeyFromString(key string, curve elliptic.Curve) (*ExtendedKey, e
 	chainCode := payload[13:45]
 	keyData := payload[45:78]
 
+	if isPrivateVersion(version) {
+		return nil, errors.New("private extended keys are not supported")
+	}
+	if depth == 0 && (childNum != 0 || !bytes.Equal(parentFP, make([]byte, fingerprintLen))) {
+		return nil, errors.New("invalid extended key: a master key must have a zero parent fingerprint and child index")
+	}
+
 	var pubKey ecdsa.PublicKey
 
 	if c, ok := curve.(*btcec.KoblitzCurve); ok {
@@ -114,7 +168,10 @@ func NewExtendedKeyFromString(key string, curve elliptic.Curve) (*ExtendedKey, e
 		}
 		pubKey = ecdsa.PublicKey(*pk)
 	} else {
-		px, py := elliptic.Unmarshal(curve, keyData)
+		px, py := elliptic.UnmarshalCompressed(curve, keyData)
+		if px == nil {
+			return nil, errors.New("invalid extended key: malformed public key")
+		}
 		pubKey = ecdsa.PublicKey{
 			Curve: curve,
 			X:     px,
@@ -178,6 +235,9 @@ func serializeCompressed(publicKeyX *big.Int, publicKeyY *big.Int) []byte {
 	return paddedAppend(b, 32, publicKeyX.Bytes())
 }
 
+// DeriveChildKeyFromHierarchy derives the descendant of `pk` along `indicesHierarchy`. It returns the sum of the IL
+// values mod `mod`, i.e. the key derivation delta to add to the parent's shares, and the descendant, whose ParentFP
+// is the fingerprint of its immediate parent.
 func DeriveChildKeyFromHierarchy(indicesHierarchy []uint32, pk *ExtendedKey, mod *big.Int, curve elliptic.Curve) (*big.Int, *ExtendedKey, error) {
 	var k = pk
 	var err error
@@ -205,11 +265,13 @@ func DeriveChildKey(index uint32, pk *ExtendedKey, curve elliptic.Curve) (*big.I
 	if pk.Depth == maxDepth {
 		return nil, nil, errors.New("cannot derive key beyond max depth")
 	}
+	if len(pk.ChainCode) != chainCodeLen {
+		return nil, nil, fmt.Errorf("chain code must be %d bytes, got %d", chainCodeLen, len(pk.ChainCode))
+	}
 
 	cryptoPk, err := crypto.NewECPoint(curve, pk.X, pk.Y)
 	if err != nil {
-		common.Logger.Error("error getting pubkey from extendedkey")
-		return nil, nil, err
+		return nil, nil, fmt.Errorf("getting the public key of the extended key: %w", err)
 	}
 
 	pkPublicKeyBytes := serializeCompressed(pk.X, pk.Y)
@@ -228,21 +290,16 @@ func DeriveChildKey(index uint32, pk *ExtendedKey, curve elliptic.Curve) (*big.I
 
 	if ilNum.Cmp(curve.Params().N) >= 0 || ilNum.Sign() == 0 {
 		// falling outside of the valid range for curve private keys
-		err = errors.New("invalid derived key")
-		common.Logger.Error("error deriving child key")
-		return nil, nil, err
+		return nil, nil, errors.New("invalid derived key")
 	}
 
 	deltaG := crypto.ScalarBaseMult(curve, ilNum)
 	if deltaG.X().Sign() == 0 || deltaG.Y().Sign() == 0 {
-		err = errors.New("invalid child")
-		common.Logger.Error("error invalid child")
-		return nil, nil, err
+		return nil, nil, errors.New("invalid child")
 	}
 	childCryptoPk, err := cryptoPk.Add(deltaG)
 	if err != nil {
-		common.Logger.Error("error adding delta G to parent key")
-		return nil, nil, err
+		return nil, nil, fmt.Errorf("adding delta G to the parent key: %w", err)
 	}
 
 	childPk := &ExtendedKey{
@@ -250,7 +307,7 @@ func DeriveChildKey(index uint32, pk *ExtendedKey, curve elliptic.Curve) (*big.I
 		Depth:      pk.Depth + 1,
 		ChildIndex: index,
 		ChainCode:  childChainCode,
-		ParentFP:   hash160(pkPublicKeyBytes)[:4],
+		ParentFP:   hash160(pkPublicKeyBytes)[:fingerprintLen],
 		Version:    pk.Version,
 	}
 	return ilNum, childPk, nil
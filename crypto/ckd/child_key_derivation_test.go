@@ -7,10 +7,13 @@
 package ckd_test
 
 import (
+	"encoding/hex"
 	"testing"
 
 	. "github.com/bnb-chain/tss-lib/crypto/ckd"
 	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestPublicDerivation(t *testing.T) {
@@ -130,3 +133,112 @@ tests:
 		}
 	}
 }
+
+func TestDeriveChildKeyFromHierarchyBIP32Vectors(t *testing.T) {
+	// public derivation steps of BIP-32 test vector 1; m/0H is hardened and only the public child m/0H/1 is derived
+	tests := []struct {
+		parent    string
+		path      []uint32
+		want      string
+		wantDepth uint8
+	}{
+		{
+			parent:    "xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet8",
+			path:      []uint32{0, 1, 2, 2, 1000000000},
+			want:      "xpub6GX3zWVgSgPc5tgjE6ogT9nfwSADD3tdsxpzd7jJoJMqSY12Be6VQEFwDCp6wAQoZsH2iq5nNocHEaVDxBcobPrkZCjYW3QUmoDYzMFBDu9",
+			wantDepth: 5,
+		},
+		{
+			parent:    "xpub68Gmy5EdvgibQVfPdqkBBCHxA5htiqg55crXYuXoQRKfDBFA1WEjWgP6LHhwBZeNK1VTsfTFUHCdrfp1bgwQ9xv5ski8PX9rL2dZXvgGDnw",
+			path:      []uint32{1},
+			want:      "xpub6ASuArnXKPbfEwhqN6e3mwBcDTgzisQN1wXN9BJcM47sSikHjJf3UFHKkNAWbWMiGj7Wf5uMash7SyYq527Hqck2AxYysAA7xmALppuCkwQ",
+			wantDepth: 2,
+		},
+	}
+	for _, tt := range tests {
+		parent, err := NewExtendedKeyFromString(tt.parent, btcec.S256())
+		assert.NoError(t, err)
+		_, child, err := DeriveChildKeyFromHierarchy(tt.path, parent, btcec.S256().N, btcec.S256())
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, child.String())
+		assert.Equal(t, tt.wantDepth, child.Depth)
+
+		// the parent fingerprint is that of the immediate parent, not of the root
+		_, immediate, err := DeriveChildKeyFromHierarchy(tt.path[:len(tt.path)-1], parent, btcec.S256().N, btcec.S256())
+		assert.NoError(t, err)
+		assert.Equal(t, immediate.Fingerprint(), child.ParentFP)
+	}
+
+	master, err := NewExtendedKeyFromString(tests[0].parent, btcec.S256())
+	assert.NoError(t, err)
+	assert.Equal(t, "3442193e", hex.EncodeToString(master.Fingerprint()))
+}
+
+func TestSLIP132Versions(t *testing.T) {
+	// BIP-44/49/84 account keys m/{44,49,84}'/0'/0' of the "abandon ... about" mnemonic
+	bip84Account := "xpub6CatWdiZiodmUeTDp8LT5or8nmbKNcuyvz7WyksVFkKB4RHwCD3XyuvPEbvqAQY3rAPshWcMLoP2fMFMKHPJ4ZeZXYVUhLv1VMrjPC7PW6V"
+	bip49Account := "xpub6C6nQwHaWbSrzs5tZ1q7m5R9cPK9eYpNMFesiXsYrgc1P8bvLLAet9JfHjYXKjToD8cBRswJXXbbFpXgwsswVPAZzKMa1jUp2kVkGVUaJa7"
+	tests := []struct {
+		xpub   string
+		script ScriptType
+		want   string
+	}{
+		{bip84Account, ScriptP2WPKH, "zpub6rFR7y4Q2AijBEqTUquhVz398htDFrtymD9xYYfG1m4wAcvPhXNfE3EfH1r1ADqtfSdVCToUG868RvUUkgDKf31mGDtKsAYz2oz2AGutZYs"},
+		{bip49Account, ScriptP2SHP2WPKH, "ypub6Ww3ibxVfGzLrAH1PNcjyAWenMTbbAosGNB6VvmSEgytSER9azLDWCxoJwW7Ke7icmizBMXrzBx9979FfaHxHcrArf3zbeJJJUZPf663zsP"},
+		{bip84Account, ScriptP2PKH, bip84Account},
+	}
+	for _, tt := range tests {
+		key, err := NewExtendedKeyFromString(tt.xpub, btcec.S256())
+		assert.NoError(t, err)
+		converted, err := key.WithVersion(&chaincfg.MainNetParams, tt.script)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, converted.String())
+
+		parsed, err := NewExtendedKeyFromString(tt.want, btcec.S256())
+		assert.NoError(t, err)
+		script, mainnet, ok := ScriptTypeOf(parsed.Version)
+		assert.True(t, ok)
+		assert.True(t, mainnet)
+		assert.Equal(t, tt.script, script)
+		assert.Equal(t, tt.xpub, key.String(), "WithVersion must not modify the receiver")
+	}
+
+	for _, tt := range []struct {
+		net    *chaincfg.Params
+		script ScriptType
+		prefix string
+	}{
+		{&chaincfg.MainNetParams, ScriptP2PKH, "xpub"},
+		{&chaincfg.TestNet3Params, ScriptP2PKH, "tpub"},
+		{&chaincfg.RegressionNetParams, ScriptP2PKH, "tpub"},
+		{&chaincfg.TestNet3Params, ScriptP2SHP2WPKH, "upub"},
+		{&chaincfg.RegressionNetParams, ScriptP2WPKH, "vpub"},
+	} {
+		version, err := PublicVersion(tt.net, tt.script)
+		assert.NoError(t, err)
+		pub, err := NewExtendedKeyFromString(bip84Account, btcec.S256())
+		assert.NoError(t, err)
+		master, err := NewMasterPublicKey(&pub.PublicKey, pub.ChainCode, tt.net, tt.script)
+		assert.NoError(t, err)
+		assert.Equal(t, version, master.Version)
+		assert.Equal(t, tt.prefix, master.String()[:4])
+	}
+	_, err := PublicVersion(&chaincfg.SimNetParams, ScriptP2WPKH)
+	assert.Error(t, err)
+}
+
+func TestNewExtendedKeyFromStringRejectsInvalidKeys(t *testing.T) {
+	for _, key := range []string{
+		// private keys are not supported
+		"xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi",
+		// BIP-32 test vector 5: zero depth with a non-zero parent fingerprint
+		"xpub661no6RGEX3uJkY4bNnPcw4URcQTrSibUZ4NqJEw5eBkv7ovTwgiT91XX27VbEXGENhYRCf7hyEbWrR3FewATdCEebj6znwMfQkhRYHRLpJ",
+		// BIP-32 test vector 5: zero depth with a non-zero child index
+		"xpub661MyMwAuDcm6CRQ5N4qiHKrJ39Xe1R1NyfouMKTTWcguwVcfrZJaNvhpebzGerh7gucBvzEQWRugZDuDXjNDRmXzSZe4c7mnTK97pTvGS8",
+		// bad checksum
+		"xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet9",
+	} {
+		_, err := NewExtendedKeyFromString(key, btcec.S256())
+		assert.Error(t, err, key)
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package ckd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// ScriptType selects the SLIP-132 version bytes of a serialized extended key, which tell wallets which address
+// type to derive from it. See https://github.com/satoshilabs/slips/blob/master/slip-0132.md
+type ScriptType int
+
+const (
+	// ScriptP2PKH is the BIP-44 legacy xpub/tpub
+	ScriptP2PKH ScriptType = iota
+	// ScriptP2SHP2WPKH is the BIP-49 nested segwit ypub/upub
+	ScriptP2SHP2WPKH
+	// ScriptP2WPKH is the BIP-84 native segwit zpub/vpub
+	ScriptP2WPKH
+)
+
+type versionInfo struct {
+	public  [4]byte
+	private [4]byte
+	mainnet bool
+	script  ScriptType
+}
+
+var versions = []versionInfo{
+	{[4]byte{0x04, 0x88, 0xb2, 0x1e}, [4]byte{0x04, 0x88, 0xad, 0xe4}, true, ScriptP2PKH},       // xpub/xprv
+	{[4]byte{0x04, 0x9d, 0x7c, 0xb2}, [4]byte{0x04, 0x9d, 0x78, 0x78}, true, ScriptP2SHP2WPKH},  // ypub/yprv
+	{[4]byte{0x04, 0xb2, 0x47, 0x46}, [4]byte{0x04, 0xb2, 0x43, 0x0c}, true, ScriptP2WPKH},      // zpub/zprv
+	{[4]byte{0x04, 0x35, 0x87, 0xcf}, [4]byte{0x04, 0x35, 0x83, 0x94}, false, ScriptP2PKH},      // tpub/tprv
+	{[4]byte{0x04, 0x4a, 0x52, 0x62}, [4]byte{0x04, 0x4a, 0x4e, 0x28}, false, ScriptP2SHP2WPKH}, // upub/uprv
+	{[4]byte{0x04, 0x5f, 0x1c, 0xf6}, [4]byte{0x04, 0x5f, 0x18, 0xbc}, false, ScriptP2WPKH},     // vpub/vprv
+}
+
+func (s ScriptType) String() string {
+	switch s {
+	case ScriptP2PKH:
+		return "p2pkh"
+	case ScriptP2SHP2WPKH:
+		return "p2sh-p2wpkh"
+	case ScriptP2WPKH:
+		return "p2wpkh"
+	default:
+		return fmt.Sprintf("ScriptType(%d)", int(s))
+	}
+}
+
+// PublicVersion returns the 4 version bytes of an extended public key for `net` and `script`, e.g. xpub for
+// (MainNetParams, ScriptP2PKH), tpub for (TestNet3Params, ScriptP2PKH) or zpub for (MainNetParams, ScriptP2WPKH).
+// Legacy keys use the network's own HDPublicKeyID; the SLIP-132 variants are only defined for mainnet and the
+// networks sharing testnet's version bytes (testnet3 and regtest).
+func PublicVersion(net *chaincfg.Params, script ScriptType) ([]byte, error) {
+	if net == nil {
+		return nil, errors.New("nil network params")
+	}
+	if script == ScriptP2PKH {
+		return append([]byte(nil), net.HDPublicKeyID[:]...), nil
+	}
+	var mainnet bool
+	switch net.HDPublicKeyID {
+	case chaincfg.MainNetParams.HDPublicKeyID:
+		mainnet = true
+	case chaincfg.TestNet3Params.HDPublicKeyID:
+		mainnet = false
+	default:
+		return nil, fmt.Errorf("no %s extended key version is defined for network %s", script, net.Name)
+	}
+	for _, v := range versions {
+		if v.mainnet == mainnet && v.script == script {
+			return append([]byte(nil), v.public[:]...), nil
+		}
+	}
+	return nil, fmt.Errorf("unknown script type %s", script)
+}
+
+// ScriptTypeOf reports the script type and whether the version bytes belong to mainnet. `ok` is false for
+// versions that are not a known xpub/ypub/zpub/tpub/upub/vpub.
+func ScriptTypeOf(version []byte) (script ScriptType, mainnet bool, ok bool) {
+	for _, v := range versions {
+		if bytes.Equal(version, v.public[:]) {
+			return v.script, v.mainnet, true
+		}
+	}
+	return ScriptP2PKH, false, false
+}
+
+func isPrivateVersion(version []byte) bool {
+	for _, v := range versions {
+		if bytes.Equal(version, v.private[:]) {
+			return true
+		}
+	}
+	return false
+}
@@ -10,6 +10,8 @@
 package commitments
 
 import (
+	"crypto/rand"
+	"io"
 	"math/big"
 
 	"github.com/bnb-chain/tss-lib/common"
@@ -44,7 +46,12 @@ func NewHashCommitmentWithRandomness(r *big.Int, secrets ...*big.Int) *HashCommi
 }
 
 func NewHashCommitment(secrets ...*big.Int) *HashCommitDecommit {
-	r := common.MustGetRandomInt(HashLength) // r
+	return NewHashCommitmentFrom(rand.Reader, secrets...)
+}
+
+// NewHashCommitmentFrom is NewHashCommitment drawing the blinding r from `rand`
+func NewHashCommitmentFrom(rand io.Reader, secrets ...*big.Int) *HashCommitDecommit {
+	r := common.MustGetRandomIntFrom(rand, HashLength) // r
 	return NewHashCommitmentWithRandomness(r, secrets...)
 }
 
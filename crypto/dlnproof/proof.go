@@ -12,7 +12,9 @@
 package dlnproof
 
 import (
+	"crypto/rand"
 	"fmt"
+	"io"
 	"math/big"
 
 	"github.com/bnb-chain/tss-lib/common"
@@ -40,13 +42,18 @@ var (
 )
 
 func NewDLNProof(h1, h2, x, p, q, N *big.Int, session ...[]byte) *Proof {
+	return NewDLNProofFrom(rand.Reader, h1, h2, x, p, q, N, session...)
+}
+
+// NewDLNProofFrom is NewDLNProof drawing the commitments from `rand`
+func NewDLNProofFrom(rand io.Reader, h1, h2, x, p, q, N *big.Int, session ...[]byte) *Proof {
 	Session := optionalSession(session)
 	pMulQ := new(big.Int).Mul(p, q)
 	modN, modPQ := common.ModInt(N), common.ModInt(pMulQ)
 	a := make([]*big.Int, Iterations)
 	alpha := [Iterations]*big.Int{}
 	for i := range alpha {
-		a[i] = common.GetRandomPositiveInt(pMulQ)
+		a[i] = common.GetRandomPositiveIntFrom(rand, pMulQ)
 		alpha[i] = modN.Exp(h1, a[i])
 	}
 	msg := append([]*big.Int{h1, h2, N}, alpha[:]...)
@@ -8,8 +8,10 @@ package mta
 
 import (
 	"crypto/elliptic"
+	"crypto/rand"
 	"errors"
 	"fmt"
+	"io"
 	"math/big"
 
 	"github.com/bnb-chain/tss-lib/common"
@@ -36,6 +38,11 @@ type (
 // ProveBobWC implements Bob's proof both with or without check "ProveMtawc_Bob" and "ProveMta_Bob" used in the MtA protocol from GG18Spec (9) Figs. 10 & 11.
 // an absent `X` generates the proof without the X consistency check X = g^x
 func ProveBobWC(ec elliptic.Curve, pk *paillier.PublicKey, NTilde, h1, h2, c1, c2, x, y, r *big.Int, X *crypto.ECPoint, session ...[]byte) (*ProofBobWC, error) {
+	return ProveBobWCFrom(rand.Reader, ec, pk, NTilde, h1, h2, c1, c2, x, y, r, X, session...)
+}
+
+// ProveBobWCFrom is ProveBobWC drawing the proof randomness from `rand`
+func ProveBobWCFrom(rand io.Reader, ec elliptic.Curve, pk *paillier.PublicKey, NTilde, h1, h2, c1, c2, x, y, r *big.Int, X *crypto.ECPoint, session ...[]byte) (*ProofBobWC, error) {
 	Session := optionalProofSession(session)
 	if ec == nil || pk == nil || NTilde == nil || h1 == nil || h2 == nil || c1 == nil || c2 == nil || x == nil || y == nil || r == nil {
 		return nil, errors.New("ProveBob() received a nil argument")
@@ -53,19 +60,19 @@ func ProveBobWC(ec elliptic.Curve, pk *paillier.PublicKey, NTilde, h1, h2, c1, c
 
 	// steps are numbered as shown in Fig. 10, but diverge slightly for Fig. 11
 	// 1.
-	alpha := common.GetRandomPositiveInt(q3)
+	alpha := common.GetRandomPositiveIntFrom(rand, q3)
 
 	// 2.
-	rho := common.GetRandomPositiveInt(qNTilde)
-	sigma := common.GetRandomPositiveInt(qNTilde)
-	tau := common.GetRandomPositiveInt(q3NTilde)
+	rho := common.GetRandomPositiveIntFrom(rand, qNTilde)
+	sigma := common.GetRandomPositiveIntFrom(rand, qNTilde)
+	tau := common.GetRandomPositiveIntFrom(rand, q3NTilde)
 
 	// 3.
-	rhoPrm := common.GetRandomPositiveInt(q3NTilde)
+	rhoPrm := common.GetRandomPositiveIntFrom(rand, q3NTilde)
 
 	// 4.
-	beta := common.GetRandomPositiveRelativelyPrimeInt(pk.N)
-	gamma := common.GetRandomPositiveInt(q7)
+	beta := common.GetRandomPositiveRelativelyPrimeIntFrom(rand, pk.N)
+	gamma := common.GetRandomPositiveIntFrom(rand, q7)
 
 	// 5.
 	u := crypto.NewECPointNoCurveCheck(ec, zero, zero) // initialization suppresses an IDE warning
@@ -139,9 +146,14 @@ func ProveBobWC(ec elliptic.Curve, pk *paillier.PublicKey, NTilde, h1, h2, c1, c
 
 // ProveBob implements Bob's proof "ProveMta_Bob" used in the MtA protocol from GG18Spec (9) Fig. 11.
 func ProveBob(ec elliptic.Curve, pk *paillier.PublicKey, NTilde, h1, h2, c1, c2, x, y, r *big.Int, session ...[]byte) (*ProofBob, error) {
+	return ProveBobFrom(rand.Reader, ec, pk, NTilde, h1, h2, c1, c2, x, y, r, session...)
+}
+
+// ProveBobFrom is ProveBob drawing the proof randomness from `rand`
+func ProveBobFrom(rand io.Reader, ec elliptic.Curve, pk *paillier.PublicKey, NTilde, h1, h2, c1, c2, x, y, r *big.Int, session ...[]byte) (*ProofBob, error) {
 	// the Bob proof ("with check") contains the ProofBob "without check"; this method extracts and returns it
 	// X is supplied as nil to exclude it from the proof hash
-	pf, err := ProveBobWC(ec, pk, NTilde, h1, h2, c1, c2, x, y, r, nil, session...)
+	pf, err := ProveBobWCFrom(rand, ec, pk, NTilde, h1, h2, c1, c2, x, y, r, nil, session...)
 	if err != nil {
 		return nil, err
 	}
@@ -8,8 +8,10 @@ package mta
 
 import (
 	"crypto/elliptic"
+	"crypto/rand"
 	"errors"
 	"fmt"
+	"io"
 	"math/big"
 
 	"github.com/bnb-chain/tss-lib/common"
@@ -49,6 +51,11 @@ type (
 
 // ProveRangeAlice implements Alice's range proof used in the MtA and MtAwc protocols from GG18Spec (9) Fig. 9.
 func ProveRangeAlice(ec elliptic.Curve, pk *paillier.PublicKey, c, NTilde, h1, h2, m, r *big.Int, session ...[]byte) (*RangeProofAlice, error) {
+	return ProveRangeAliceFrom(rand.Reader, ec, pk, c, NTilde, h1, h2, m, r, session...)
+}
+
+// ProveRangeAliceFrom is ProveRangeAlice drawing the proof randomness from `rand`
+func ProveRangeAliceFrom(rand io.Reader, ec elliptic.Curve, pk *paillier.PublicKey, c, NTilde, h1, h2, m, r *big.Int, session ...[]byte) (*RangeProofAlice, error) {
 	Session := optionalProofSession(session)
 	if ec == nil || pk == nil || NTilde == nil || h1 == nil || h2 == nil || c == nil || m == nil || r == nil {
 		return nil, errors.New("ProveRangeAlice constructor received nil value(s)")
@@ -61,15 +68,15 @@ func ProveRangeAlice(ec elliptic.Curve, pk *paillier.PublicKey, c, NTilde, h1, h
 	q3NTilde := new(big.Int).Mul(q3, NTilde)
 
 	// 1.
-	alpha := common.GetRandomPositiveInt(q3)
+	alpha := common.GetRandomPositiveIntFrom(rand, q3)
 	// 2.
-	beta := common.GetRandomPositiveRelativelyPrimeInt(pk.N)
+	beta := common.GetRandomPositiveRelativelyPrimeIntFrom(rand, pk.N)
 
 	// 3.
-	gamma := common.GetRandomPositiveInt(q3NTilde)
+	gamma := common.GetRandomPositiveIntFrom(rand, q3NTilde)
 
 	// 4.
-	rho := common.GetRandomPositiveInt(qNTilde)
+	rho := common.GetRandomPositiveIntFrom(rand, qNTilde)
 
 	// 5.
 	modNTilde := common.ModInt(NTilde)
@@ -8,7 +8,9 @@ package mta
 
 import (
 	"crypto/elliptic"
+	"crypto/rand"
 	"errors"
+	"io"
 	"math/big"
 
 	"github.com/bnb-chain/tss-lib/common"
@@ -27,11 +29,22 @@ func AliceInit(
 	a, NTildeB, h1B, h2B *big.Int,
 	session ...[]byte,
 ) (cA *big.Int, pf *RangeProofAlice, err error) {
-	cA, rA, err := pkA.EncryptAndReturnRandomness(a)
+	return AliceInitFrom(rand.Reader, ec, pkA, a, NTildeB, h1B, h2B, session...)
+}
+
+// AliceInitFrom is AliceInit drawing the encryption and proof randomness from `rand`
+func AliceInitFrom(
+	rand io.Reader,
+	ec elliptic.Curve,
+	pkA *paillier.PublicKey,
+	a, NTildeB, h1B, h2B *big.Int,
+	session ...[]byte,
+) (cA *big.Int, pf *RangeProofAlice, err error) {
+	cA, rA, err := pkA.EncryptAndReturnRandomnessFrom(rand, a)
 	if err != nil {
 		return nil, nil, err
 	}
-	pf, err = ProveRangeAlice(ec, pkA, cA, NTildeB, h1B, h2B, a, rA, session...)
+	pf, err = ProveRangeAliceFrom(rand, ec, pkA, cA, NTildeB, h1B, h2B, a, rA, session...)
 	return cA, pf, err
 }
 
@@ -41,6 +54,18 @@ func BobMid(
 	pf *RangeProofAlice,
 	b, cA, NTildeA, h1A, h2A, NTildeB, h1B, h2B *big.Int,
 	session ...[]byte,
+) (beta, cB, betaPrm *big.Int, piB *ProofBob, err error) {
+	return BobMidFrom(rand.Reader, ec, pkA, pf, b, cA, NTildeA, h1A, h2A, NTildeB, h1B, h2B, session...)
+}
+
+// BobMidFrom is BobMid drawing beta' and the proof randomness from `rand`
+func BobMidFrom(
+	rand io.Reader,
+	ec elliptic.Curve,
+	pkA *paillier.PublicKey,
+	pf *RangeProofAlice,
+	b, cA, NTildeA, h1A, h2A, NTildeB, h1B, h2B *big.Int,
+	session ...[]byte,
 ) (beta, cB, betaPrm *big.Int, piB *ProofBob, err error) {
 	if !pf.Verify(ec, pkA, NTildeB, h1B, h2B, cA, session...) {
 		err = ErrRangeProofVerify
@@ -50,8 +75,8 @@ func BobMid(
 	q5 := new(big.Int).Mul(q, q)
 	q5 = new(big.Int).Mul(q5, q5)
 	q5 = new(big.Int).Mul(q5, q)
-	betaPrm = common.GetRandomPositiveInt(q5)
-	cBetaPrm, cRand, err := pkA.EncryptAndReturnRandomness(betaPrm)
+	betaPrm = common.GetRandomPositiveIntFrom(rand, q5)
+	cBetaPrm, cRand, err := pkA.EncryptAndReturnRandomnessFrom(rand, betaPrm)
 	if err != nil {
 		return
 	}
@@ -64,7 +89,7 @@ func BobMid(
 		return
 	}
 	beta = common.ModInt(q).Sub(zero, betaPrm)
-	piB, err = ProveBob(ec, pkA, NTildeA, h1A, h2A, cA, cB, b, betaPrm, cRand, session...)
+	piB, err = ProveBobFrom(rand, ec, pkA, NTildeA, h1A, h2A, cA, cB, b, betaPrm, cRand, session...)
 	return
 }
 
@@ -75,6 +100,19 @@ func BobMidWC(
 	b, cA, NTildeA, h1A, h2A, NTildeB, h1B, h2B *big.Int,
 	B *crypto.ECPoint,
 	session ...[]byte,
+) (beta, cB, betaPrm *big.Int, piB *ProofBobWC, err error) {
+	return BobMidWCFrom(rand.Reader, ec, pkA, pf, b, cA, NTildeA, h1A, h2A, NTildeB, h1B, h2B, B, session...)
+}
+
+// BobMidWCFrom is BobMidWC drawing beta' and the proof randomness from `rand`
+func BobMidWCFrom(
+	rand io.Reader,
+	ec elliptic.Curve,
+	pkA *paillier.PublicKey,
+	pf *RangeProofAlice,
+	b, cA, NTildeA, h1A, h2A, NTildeB, h1B, h2B *big.Int,
+	B *crypto.ECPoint,
+	session ...[]byte,
 ) (beta, cB, betaPrm *big.Int, piB *ProofBobWC, err error) {
 	if !pf.Verify(ec, pkA, NTildeB, h1B, h2B, cA, session...) {
 		err = ErrRangeProofVerify
@@ -84,8 +122,8 @@ func BobMidWC(
 	q5 := new(big.Int).Mul(q, q)
 	q5 = new(big.Int).Mul(q5, q5)
 	q5 = new(big.Int).Mul(q5, q)
-	betaPrm = common.GetRandomPositiveInt(q5)
-	cBetaPrm, cRand, err := pkA.EncryptAndReturnRandomness(betaPrm)
+	betaPrm = common.GetRandomPositiveIntFrom(rand, q5)
+	cBetaPrm, cRand, err := pkA.EncryptAndReturnRandomnessFrom(rand, betaPrm)
 	if err != nil {
 		return
 	}
@@ -98,7 +136,7 @@ func BobMidWC(
 		return
 	}
 	beta = common.ModInt(q).Sub(zero, betaPrm)
-	piB, err = ProveBobWC(ec, pkA, NTildeA, h1A, h2A, cA, cB, b, betaPrm, cRand, B, session...)
+	piB, err = ProveBobWCFrom(rand, ec, pkA, NTildeA, h1A, h2A, cA, cB, b, betaPrm, cRand, B, session...)
 	return
 }
 
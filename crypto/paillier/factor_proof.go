@@ -1,7 +1,9 @@
 package paillier
 
 import (
+	"crypto/rand"
 	"fmt"
+	"io"
 	"math/big"
 
 	"github.com/bnb-chain/tss-lib/common"
@@ -40,20 +42,25 @@ type (
 // UC Non-Interactive, Proactive, Threshold ECDSA with Identifiable Aborts.
 // In: Cryptology ePrint Archive 2021/060
 func (privateKey *PrivateKey) FactorProof(N, s, t *big.Int, session ...[]byte) *FactorProof {
+	return privateKey.FactorProofFrom(rand.Reader, N, s, t, session...)
+}
+
+// FactorProofFrom is FactorProof drawing the masks from `rand`
+func (privateKey *PrivateKey) FactorProofFrom(rand io.Reader, N, s, t *big.Int, session ...[]byte) *FactorProof {
 	N0 := privateKey.PublicKey.N
 	p, q := privateKey.GetPQ()
 
-	a := common.GetRandomIntIn2PowerMulRange(PARAM_L+PARAM_E, new(big.Int).Sqrt(N0))
-	b := common.GetRandomIntIn2PowerMulRange(PARAM_L+PARAM_E, new(big.Int).Sqrt(N0))
+	a := common.GetRandomIntIn2PowerMulRangeFrom(rand, PARAM_L+PARAM_E, new(big.Int).Sqrt(N0))
+	b := common.GetRandomIntIn2PowerMulRangeFrom(rand, PARAM_L+PARAM_E, new(big.Int).Sqrt(N0))
 
-	mu := common.GetRandomIntIn2PowerMulRange(PARAM_L, N)
-	v := common.GetRandomIntIn2PowerMulRange(PARAM_L, N)
+	mu := common.GetRandomIntIn2PowerMulRangeFrom(rand, PARAM_L, N)
+	v := common.GetRandomIntIn2PowerMulRangeFrom(rand, PARAM_L, N)
 
-	sigma := common.GetRandomIntIn2PowerMulRange(PARAM_L, new(big.Int).Mul(N0, N))
-	r := common.GetRandomIntIn2PowerMulRange(PARAM_L+PARAM_E, new(big.Int).Mul(N0, N))
+	sigma := common.GetRandomIntIn2PowerMulRangeFrom(rand, PARAM_L, new(big.Int).Mul(N0, N))
+	r := common.GetRandomIntIn2PowerMulRangeFrom(rand, PARAM_L+PARAM_E, new(big.Int).Mul(N0, N))
 
-	x := common.GetRandomIntIn2PowerMulRange(PARAM_L+PARAM_E, N)
-	y := common.GetRandomIntIn2PowerMulRange(PARAM_L+PARAM_E, N)
+	x := common.GetRandomIntIn2PowerMulRangeFrom(rand, PARAM_L+PARAM_E, N)
+	y := common.GetRandomIntIn2PowerMulRangeFrom(rand, PARAM_L+PARAM_E, N)
 
 	modN := common.ModInt(N)
 
@@ -1,8 +1,10 @@
 package paillier
 
 import (
+	"crypto/rand"
 	"encoding/binary"
 	"fmt"
+	"io"
 	"math/big"
 
 	"github.com/bnb-chain/tss-lib/common"
@@ -32,13 +34,18 @@ type (
 // UC Non-Interactive, Proactive, Threshold ECDSA with Identifiable Aborts.
 // In: Cryptology ePrint Archive 2021/060
 func (privateKey *PrivateKey) ModProof(session ...[]byte) *ModProof {
+	return privateKey.ModProofFrom(rand.Reader, session...)
+}
+
+// ModProofFrom is ModProof drawing w from `rand`
+func (privateKey *PrivateKey) ModProofFrom(rand io.Reader, session ...[]byte) *ModProof {
 	N := privateKey.PublicKey.N
 	phiN := privateKey.PhiN
 	p, q := privateKey.GetPQ()
 
-	w := common.GetRandomPositiveInt(N)
+	w := common.GetRandomPositiveIntFrom(rand, N)
 	for big.Jacobi(w, N) != -1 {
-		w = common.GetRandomPositiveInt(N)
+		w = common.GetRandomPositiveIntFrom(rand, N)
 	}
 
 	y := ModChallenge(N, w, session...)
@@ -17,8 +17,10 @@ package paillier
 
 import (
 	"context"
+	"crypto/rand"
 	"errors"
 	"fmt"
+	"io"
 	gmath "math"
 	"math/big"
 	"runtime"
@@ -67,6 +69,12 @@ func init() {
 
 // len is the length of the modulus (each prime = len / 2)
 func GenerateKeyPair(ctx context.Context, modulusBitLen int, optionalConcurrency ...int) (privateKey *PrivateKey, publicKey *PublicKey, err error) {
+	return GenerateKeyPairFrom(ctx, rand.Reader, modulusBitLen, optionalConcurrency...)
+}
+
+// GenerateKeyPairFrom is GenerateKeyPair drawing the primes from `rand`; the key pair only depends on `rand` when
+// the concurrency is 1
+func GenerateKeyPairFrom(ctx context.Context, rand io.Reader, modulusBitLen int, optionalConcurrency ...int) (privateKey *PrivateKey, publicKey *PublicKey, err error) {
 	var concurrency int
 	if 0 < len(optionalConcurrency) {
 		if 1 < len(optionalConcurrency) {
@@ -82,7 +90,7 @@ func GenerateKeyPair(ctx context.Context, modulusBitLen int, optionalConcurrency
 	{
 		tmp := new(big.Int)
 		for {
-			sgps, err := common.GetRandomSafePrimesConcurrent(ctx, modulusBitLen/2, 2, concurrency)
+			sgps, err := common.GetRandomSafePrimesConcurrentFrom(ctx, rand, modulusBitLen/2, 2, concurrency)
 			if err != nil {
 				return nil, nil, err
 			}
@@ -111,10 +119,15 @@ func GenerateKeyPair(ctx context.Context, modulusBitLen int, optionalConcurrency
 // ----- //
 
 func (publicKey *PublicKey) EncryptAndReturnRandomness(m *big.Int) (c *big.Int, x *big.Int, err error) {
+	return publicKey.EncryptAndReturnRandomnessFrom(rand.Reader, m)
+}
+
+// EncryptAndReturnRandomnessFrom is EncryptAndReturnRandomness drawing the randomness from `rand`
+func (publicKey *PublicKey) EncryptAndReturnRandomnessFrom(rand io.Reader, m *big.Int) (c *big.Int, x *big.Int, err error) {
 	if m.Cmp(zero) == -1 || m.Cmp(publicKey.N) != -1 { // m < 0 || m >= N ?
 		return nil, nil, ErrMessageTooLong
 	}
-	x = common.GetRandomPositiveRelativelyPrimeInt(publicKey.N)
+	x = common.GetRandomPositiveRelativelyPrimeIntFrom(rand, publicKey.N)
 	N2 := publicKey.NSquare()
 	// 1. gamma^m mod N2
 	Gm := new(big.Int).Exp(publicKey.Gamma(), m, N2)
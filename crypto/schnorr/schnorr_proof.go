@@ -7,7 +7,9 @@
 package schnorr
 
 import (
+	"crypto/rand"
 	"errors"
+	"io"
 	"math/big"
 
 	"github.com/bnb-chain/tss-lib/common"
@@ -47,6 +49,11 @@ func NewZKProof(x *big.Int, X *crypto.ECPoint) (*ZKProof, error) {
 // NewZKProofWithSession constructs a Schnorr proof with the session bound into
 // the Fiat-Shamir challenge.
 func NewZKProofWithSession(session []byte, x *big.Int, X *crypto.ECPoint) (*ZKProof, error) {
+	return NewZKProofWithSessionFrom(rand.Reader, session, x, X)
+}
+
+// NewZKProofWithSessionFrom is NewZKProofWithSession drawing the nonce from `rand`.
+func NewZKProofWithSessionFrom(rand io.Reader, session []byte, x *big.Int, X *crypto.ECPoint) (*ZKProof, error) {
 	if x == nil || X == nil || !X.ValidateBasic() {
 		return nil, errors.New("ZKProof constructor received nil or invalid value(s)")
 	}
@@ -55,7 +62,7 @@ func NewZKProofWithSession(session []byte, x *big.Int, X *crypto.ECPoint) (*ZKPr
 	q := ecParams.N
 	g := crypto.NewECPointNoCurveCheck(ec, ecParams.Gx, ecParams.Gy) // already on the curve.
 
-	a := common.GetRandomPositiveInt(q)
+	a := common.GetRandomPositiveIntFrom(rand, q)
 	alpha := crypto.ScalarBaseMult(ec, a)
 
 	cHash := common.SHA512_256i_TAGGED(fsSessionZK(session), X.X(), X.Y(), g.X(), g.Y(), alpha.X(), alpha.Y())
@@ -118,6 +125,11 @@ func NewZKVProof(V, R *crypto.ECPoint, s, l *big.Int) (*ZKVProof, error) {
 // NewZKVProofWithSession constructs a Schnorr V proof with the session bound
 // into the Fiat-Shamir challenge.
 func NewZKVProofWithSession(session []byte, V, R *crypto.ECPoint, s, l *big.Int) (*ZKVProof, error) {
+	return NewZKVProofWithSessionFrom(rand.Reader, session, V, R, s, l)
+}
+
+// NewZKVProofWithSessionFrom is NewZKVProofWithSession drawing the nonces from `rand`.
+func NewZKVProofWithSessionFrom(rand io.Reader, session []byte, V, R *crypto.ECPoint, s, l *big.Int) (*ZKVProof, error) {
 	if V == nil || R == nil || s == nil || l == nil || !V.ValidateBasic() || !R.ValidateBasic() {
 		return nil, errors.New("ZKVProof constructor received nil value(s)")
 	}
@@ -126,7 +138,7 @@ func NewZKVProofWithSession(session []byte, V, R *crypto.ECPoint, s, l *big.Int)
 	q := ecParams.N
 	g := crypto.NewECPointNoCurveCheck(ec, ecParams.Gx, ecParams.Gy)
 
-	a, b := common.GetRandomPositiveInt(q), common.GetRandomPositiveInt(q)
+	a, b := common.GetRandomPositiveIntFrom(rand, q), common.GetRandomPositiveIntFrom(rand, q)
 	aR := R.ScalarMult(a)
 	bG := crypto.ScalarBaseMult(ec, b)
 	alpha, _ := aR.Add(bG) // already on the curve.
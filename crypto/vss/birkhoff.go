@@ -0,0 +1,215 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Hierarchical threshold secret sharing, based on Tamir Tassa, 2007., Hierarchical Threshold Secret Sharing.
+// In Journal of Cryptology 20, 237–264
+//
+
+package vss
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/crypto"
+)
+
+// ErrUnauthorizedSet is returned when a set of hierarchical shares cannot interpolate the secret
+var ErrUnauthorizedSet = errors.New("the shares do not form an authorized set of the access structure")
+
+// CreateHierarchical is Create for a hierarchical access structure: participant i gets the ranks[i]-th derivative
+// of the polynomial at indexes[i]. A participant of rank r only helps reconstruct the secret together with enough
+// participants of lower rank; e.g. with threshold 2, ranks 0 for a security team and 1 for everyone else, any
+// 3 participants including at least one of the security team are authorized. The Vs are the usual Feldman
+// commitments to the coefficients, and the full set of participants must be authorized.
+func CreateHierarchical(ec elliptic.Curve, threshold int, secret *big.Int, indexes []*big.Int, ranks []int) (Vs, Shares, error) {
+	return CreateHierarchicalFrom(rand.Reader, ec, threshold, secret, indexes, ranks)
+}
+
+// CreateHierarchicalFrom is CreateHierarchical sampling the polynomial from `rand`
+func CreateHierarchicalFrom(rand io.Reader, ec elliptic.Curve, threshold int, secret *big.Int, indexes []*big.Int, ranks []int) (Vs, Shares, error) {
+	if ec == nil {
+		return nil, nil, fmt.Errorf("vss ec == nil")
+	}
+	if secret == nil || indexes == nil {
+		return nil, nil, fmt.Errorf("vss secret or indexes == nil: %v %v", secret, indexes)
+	}
+	if threshold < 1 {
+		return nil, nil, errors.New("vss threshold < 1")
+	}
+	if len(ranks) != len(indexes) {
+		return nil, nil, fmt.Errorf("vss got %d ranks for %d indexes", len(ranks), len(indexes))
+	}
+	ids, err := CheckIndexes(ec, indexes)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err = BirkhoffCoefficients(ec, threshold, ids, ranks); err != nil {
+		return nil, nil, err
+	}
+
+	poly := samplePolynomial(rand, ec, threshold, secret)
+	v := make(Vs, len(poly))
+	for i, ai := range poly {
+		v[i] = crypto.ScalarBaseMult(ec, ai)
+	}
+
+	q := ec.Params().N
+	modQ := common.ModInt(q)
+	shares := make(Shares, len(ids))
+	for i := range ids {
+		share := big.NewInt(0)
+		for k, c := range derivativeCoefficients(q, threshold, ids[i], ranks[i]) {
+			share = modQ.Add(share, modQ.Mul(c, poly[k]))
+		}
+		shares[i] = &Share{Threshold: threshold, ID: ids[i], Share: share, Rank: ranks[i]}
+	}
+	return v, shares, nil
+}
+
+// EvaluateDerivative returns the commitment to the rank-th derivative of the committed polynomial at id, i.e.
+// f^(rank)(id)*G; with rank 0 it is the public share of the participant at id.
+func (vs Vs) EvaluateDerivative(ec elliptic.Curve, id *big.Int, rank int) (*crypto.ECPoint, error) {
+	threshold := len(vs) - 1
+	if threshold < 1 || rank < 0 || threshold < rank {
+		return nil, fmt.Errorf("vss cannot evaluate derivative %d of %d commitments", rank, len(vs))
+	}
+	var result *crypto.ECPoint
+	for k, c := range derivativeCoefficients(ec.Params().N, threshold, id, rank) {
+		if k < rank {
+			continue
+		}
+		if vs[k] == nil || !crypto.SameCurve(vs[k].Curve(), ec) || !vs[k].ValidateBasic() {
+			return nil, errors.New("vss commitment is not a valid point on the curve")
+		}
+		term := vs[k].ScalarMult(c)
+		if result == nil {
+			result = term
+			continue
+		}
+		var err error
+		if result, err = result.Add(term); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// BirkhoffCoefficients returns the coefficients c_i with sum(c_i * share_i) = secret for the participants at
+// indexes with ranks, or ErrUnauthorizedSet if they are not an authorized set. When the set is larger than
+// needed, the solution is the one with the free coefficients set to 1, so every participant computing the
+// coefficients for the same set gets the same ones, and none of them is zero. For threshold+1 participants of
+// rank 0 these are the Lagrange coefficients at 0.
+func BirkhoffCoefficients(ec elliptic.Curve, threshold int, indexes []*big.Int, ranks []int) ([]*big.Int, error) {
+	if len(ranks) != len(indexes) {
+		return nil, fmt.Errorf("vss got %d ranks for %d indexes", len(ranks), len(indexes))
+	}
+	q := ec.Params().N
+	modQ := common.ModInt(q)
+	n := len(indexes)
+	// row k of the augmented matrix is the coefficient of a_k in each share: sum_i c_i * m[k][i] = [k == 0]
+	m := make([][]*big.Int, threshold+1)
+	for k := range m {
+		m[k] = make([]*big.Int, n+1)
+		m[k][n] = big.NewInt(0)
+	}
+	m[0][n] = big.NewInt(1)
+	for i, id := range indexes {
+		if ranks[i] < 0 || threshold < ranks[i] {
+			return nil, fmt.Errorf("vss rank %d of participant %d is out of range [0, %d]", ranks[i], i, threshold)
+		}
+		for k, c := range derivativeCoefficients(q, threshold, id, ranks[i]) {
+			m[k][i] = c
+		}
+	}
+
+	// Gauss-Jordan elimination mod q
+	pivots := make([]int, 0, threshold+1) // pivots[row] is the column of the row's leading 1
+	row := 0
+	for col := 0; col < n && row <= threshold; col++ {
+		sel := -1
+		for r := row; r <= threshold; r++ {
+			if m[r][col].Sign() != 0 {
+				sel = r
+				break
+			}
+		}
+		if sel < 0 {
+			continue
+		}
+		m[row], m[sel] = m[sel], m[row]
+		inv := modQ.ModInverse(m[row][col])
+		for c := col; c <= n; c++ {
+			m[row][c] = modQ.Mul(m[row][c], inv)
+		}
+		for r := 0; r <= threshold; r++ {
+			if r == row || m[r][col].Sign() == 0 {
+				continue
+			}
+			f := m[r][col]
+			for c := col; c <= n; c++ {
+				m[r][c] = modQ.Sub(m[r][c], modQ.Mul(f, m[row][c]))
+			}
+		}
+		pivots = append(pivots, col)
+		row++
+	}
+	for r := row; r <= threshold; r++ {
+		if m[r][n].Sign() != 0 {
+			return nil, ErrUnauthorizedSet
+		}
+	}
+
+	coefs := make([]*big.Int, n)
+	isPivot := make(map[int]int, len(pivots))
+	for r, col := range pivots {
+		isPivot[col] = r
+	}
+	for i := range coefs {
+		if _, ok := isPivot[i]; !ok {
+			coefs[i] = big.NewInt(1)
+		}
+	}
+	for r, col := range pivots {
+		c := new(big.Int).Set(m[r][n])
+		for i := range coefs {
+			if _, ok := isPivot[i]; !ok {
+				c = modQ.Sub(c, m[r][i])
+			}
+		}
+		coefs[col] = c
+	}
+	for i, c := range coefs {
+		if c.Sign() == 0 {
+			return nil, fmt.Errorf("vss interpolation coefficient of participant %d is zero", i)
+		}
+	}
+	return coefs, nil
+}
+
+// derivativeCoefficients returns c_k, k = 0..threshold, such that the rank-th derivative of a polynomial
+// a_0 + a_1*x + ... + a_t*x^t at id is sum(c_k * a_k): c_k = k!/(k-rank)! * id^(k-rank), and 0 for k < rank
+func derivativeCoefficients(q *big.Int, threshold int, id *big.Int, rank int) []*big.Int {
+	modQ := common.ModInt(q)
+	coefs := make([]*big.Int, threshold+1)
+	for k := range coefs {
+		if k < rank {
+			coefs[k] = big.NewInt(0)
+			continue
+		}
+		fall := big.NewInt(1)
+		for f := k - rank + 1; f <= k; f++ {
+			fall = modQ.Mul(fall, big.NewInt(int64(f)))
+		}
+		coefs[k] = modQ.Mul(fall, modQ.Exp(id, big.NewInt(int64(k-rank))))
+	}
+	return coefs
+}
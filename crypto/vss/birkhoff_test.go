@@ -0,0 +1,126 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package vss_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/crypto"
+	. "github.com/bnb-chain/tss-lib/crypto/vss"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+func TestCreateHierarchicalAndVerify(t *testing.T) {
+	threshold := 2
+	ranks := []int{0, 1, 1, 1, 2}
+
+	secret := common.GetRandomPositiveInt(tss.EC().Params().N)
+	ids := make([]*big.Int, 0)
+	for i := 0; i < len(ranks); i++ {
+		ids = append(ids, common.GetRandomPositiveInt(tss.EC().Params().N))
+	}
+
+	vs, shares, err := CreateHierarchical(tss.EC(), threshold, secret, ids, ranks)
+	assert.NoError(t, err)
+	assert.Equal(t, threshold+1, len(vs))
+	assert.True(t, vs[0].Equals(crypto.ScalarBaseMult(tss.EC(), secret)))
+
+	for i, share := range shares {
+		assert.Equal(t, ranks[i], share.Rank)
+		assert.True(t, share.Verify(tss.EC(), threshold, vs))
+
+		pub, err := vs.EvaluateDerivative(tss.EC(), share.ID, share.Rank)
+		assert.NoError(t, err)
+		assert.True(t, pub.Equals(crypto.ScalarBaseMult(tss.EC(), share.Share)))
+
+		// a share does not verify as a share of another rank
+		wrongRank := *share
+		wrongRank.Rank = (share.Rank + 1) % (threshold + 1)
+		assert.False(t, wrongRank.Verify(tss.EC(), threshold, vs))
+	}
+}
+
+func TestCreateHierarchicalRejectsUnauthorizedParticipants(t *testing.T) {
+	threshold := 2
+	ids := []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3), big.NewInt(4)}
+	secret := common.GetRandomPositiveInt(tss.EC().Params().N)
+
+	// no participant of rank 0: nobody learns anything about the constant term
+	_, _, err := CreateHierarchical(tss.EC(), threshold, secret, ids, []int{1, 1, 1, 2})
+	assert.Equal(t, ErrUnauthorizedSet, err)
+
+	// a rank above the threshold is always zero
+	_, _, err = CreateHierarchical(tss.EC(), threshold, secret, ids, []int{0, 1, 1, 3})
+	assert.Error(t, err)
+
+	_, _, err = CreateHierarchical(tss.EC(), threshold, secret, ids, []int{0, 1})
+	assert.Error(t, err)
+}
+
+func TestHierarchicalReconstruct(t *testing.T) {
+	threshold := 2
+	// "any 3, at least one from the security team": P0 and P1 are rank 0, everyone else rank 1
+	ranks := []int{0, 0, 1, 1, 1}
+
+	secret := common.GetRandomPositiveInt(tss.EC().Params().N)
+	ids := make([]*big.Int, 0)
+	for i := 0; i < len(ranks); i++ {
+		ids = append(ids, common.GetRandomPositiveInt(tss.EC().Params().N))
+	}
+	_, shares, err := CreateHierarchical(tss.EC(), threshold, secret, ids, ranks)
+	assert.NoError(t, err)
+
+	authorized := []Shares{
+		{shares[0], shares[2], shares[3]},
+		{shares[1], shares[3], shares[4]},
+		{shares[0], shares[1], shares[2]},
+		{shares[0], shares[2], shares[3], shares[4]},
+		shares,
+	}
+	for _, set := range authorized {
+		got, err := set.ReConstruct(tss.EC())
+		assert.NoError(t, err)
+		assert.Zero(t, secret.Cmp(got))
+	}
+
+	_, err = Shares{shares[2], shares[3], shares[4]}.ReConstruct(tss.EC())
+	assert.Equal(t, ErrUnauthorizedSet, err)
+	_, err = Shares{shares[0], shares[2]}.ReConstruct(tss.EC())
+	assert.Equal(t, ErrNumSharesBelowThreshold, err)
+}
+
+func TestBirkhoffCoefficients(t *testing.T) {
+	q := tss.EC().Params().N
+	modQ := common.ModInt(q)
+	threshold := 2
+	ids := []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)}
+
+	// with only rank 0 and threshold+1 participants they are the Lagrange coefficients at 0: 3, -3, 1
+	coefs, err := BirkhoffCoefficients(tss.EC(), threshold, ids, []int{0, 0, 0})
+	assert.NoError(t, err)
+	for i, want := range []*big.Int{big.NewInt(3), modQ.Sub(big.NewInt(0), big.NewInt(3)), big.NewInt(1)} {
+		assert.Zero(t, want.Cmp(coefs[i]))
+	}
+
+	// deterministic for larger sets
+	ids = append(ids, big.NewInt(4))
+	ranks := []int{0, 1, 1, 2}
+	coefs1, err := BirkhoffCoefficients(tss.EC(), threshold, ids, ranks)
+	assert.NoError(t, err)
+	coefs2, err := BirkhoffCoefficients(tss.EC(), threshold, ids, ranks)
+	assert.NoError(t, err)
+	for i := range coefs1 {
+		assert.Zero(t, coefs1[i].Cmp(coefs2[i]))
+	}
+
+	_, err = BirkhoffCoefficients(tss.EC(), threshold, ids[1:], ranks[1:])
+	assert.Equal(t, ErrUnauthorizedSet, err)
+}
@@ -12,8 +12,10 @@ package vss
 
 import (
 	"crypto/elliptic"
+	"crypto/rand"
 	"errors"
 	"fmt"
+	"io"
 	"math/big"
 
 	"github.com/bnb-chain/tss-lib/common"
@@ -25,6 +27,9 @@ type (
 		Threshold int
 		ID,       // xi
 		Share *big.Int // Sigma i
+		// Rank is the order of the derivative of the polynomial that Share evaluates, 0 for Shamir's sharing;
+		// see CreateHierarchical
+		Rank int
 	}
 
 	Vs []*crypto.ECPoint // v0..vt
@@ -59,6 +64,11 @@ func CheckIndexes(ec elliptic.Curve, indexes []*big.Int) ([]*big.Int, error) {
 // Returns a new array of secret shares created by Shamir's Secret Sharing Algorithm,
 // requiring a minimum number of shares to recreate, of length shares, from the input secret
 func Create(ec elliptic.Curve, threshold int, secret *big.Int, indexes []*big.Int) (Vs, Shares, error) {
+	return CreateFrom(rand.Reader, ec, threshold, secret, indexes)
+}
+
+// CreateFrom is Create sampling the polynomial from `rand`
+func CreateFrom(rand io.Reader, ec elliptic.Curve, threshold int, secret *big.Int, indexes []*big.Int) (Vs, Shares, error) {
 	if ec == nil {
 		return nil, nil, fmt.Errorf("vss ec == nil")
 	}
@@ -79,7 +89,7 @@ func Create(ec elliptic.Curve, threshold int, secret *big.Int, indexes []*big.In
 		return nil, nil, ErrNumSharesBelowThreshold
 	}
 
-	poly := samplePolynomial(ec, threshold, secret)
+	poly := samplePolynomial(rand, ec, threshold, secret)
 	poly[0] = secret // becomes sigma*G in v
 	v := make(Vs, len(poly))
 	for i, ai := range poly {
@@ -104,6 +114,13 @@ func (share *Share) Verify(ec elliptic.Curve, threshold int, vs Vs) bool {
 	if idModQ.Sign() == 0 || share.Share.Sign() <= 0 || share.Share.Cmp(q) >= 0 {
 		return false
 	}
+	if share.Rank != 0 {
+		v, err := vs.EvaluateDerivative(ec, share.ID, share.Rank)
+		if err != nil {
+			return false
+		}
+		return crypto.ScalarBaseMult(ec, share.Share).Equals(v)
+	}
 	var err error
 	modQ := common.ModInt(q)
 	v, t := vs[0], one // YRO : we need to have our accumulator outside of the loop
@@ -157,6 +174,9 @@ func (shares Shares) ReConstruct(ec elliptic.Curve) (secret *big.Int, err error)
 	}
 	q := ec.Params().N
 	modN := common.ModInt(q)
+	if shares.hierarchical() {
+		return shares.reConstructHierarchical(ec, threshold)
+	}
 
 	// x coords. Reject zero or duplicate share IDs (mod q) up front: a zero
 	// ID encodes the secret directly, and two equal IDs make the Lagrange
@@ -200,12 +220,41 @@ func (shares Shares) ReConstruct(ec elliptic.Curve) (secret *big.Int, err error)
 	return secret, nil
 }
 
-func samplePolynomial(ec elliptic.Curve, threshold int, secret *big.Int) []*big.Int {
+func (shares Shares) hierarchical() bool {
+	for _, share := range shares {
+		if share.Rank != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (shares Shares) reConstructHierarchical(ec elliptic.Curve, threshold int) (*big.Int, error) {
+	ids, ranks := make([]*big.Int, len(shares)), make([]int, len(shares))
+	for i, share := range shares {
+		ids[i], ranks[i] = share.ID, share.Rank
+	}
+	if _, err := CheckIndexes(ec, ids); err != nil {
+		return nil, err
+	}
+	coefs, err := BirkhoffCoefficients(ec, threshold, ids, ranks)
+	if err != nil {
+		return nil, err
+	}
+	modQ := common.ModInt(ec.Params().N)
+	secret := big.NewInt(0)
+	for i, share := range shares {
+		secret = modQ.Add(secret, modQ.Mul(coefs[i], share.Share))
+	}
+	return secret, nil
+}
+
+func samplePolynomial(rand io.Reader, ec elliptic.Curve, threshold int, secret *big.Int) []*big.Int {
 	q := ec.Params().N
 	v := make([]*big.Int, threshold+1)
 	v[0] = secret
 	for i := 1; i <= threshold; i++ {
-		ai := common.GetRandomPositiveInt(q)
+		ai := common.GetRandomPositiveIntFrom(rand, q)
 		v[i] = ai
 	}
 	return v
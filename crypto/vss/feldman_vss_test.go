@@ -9,6 +9,7 @@ package vss_test
 import (
 	"crypto/elliptic"
 	"math/big"
+	mrand "math/rand"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -72,6 +73,33 @@ func TestCreate(t *testing.T) {
 	}
 }
 
+func TestCreateFromSeededReader(t *testing.T) {
+	num, threshold := 5, 3
+	secret := big.NewInt(42)
+	ids := make([]*big.Int, 0)
+	for i := 1; i <= num; i++ {
+		ids = append(ids, big.NewInt(int64(i)))
+	}
+	create := func(seed int64) (Vs, Shares) {
+		vs, shares, err := CreateFrom(mrand.New(mrand.NewSource(seed)), tss.EC(), threshold, secret, ids)
+		assert.NoError(t, err)
+		return vs, shares
+	}
+
+	vs, shares := create(1)
+	vs2, shares2 := create(1)
+	assert.Equal(t, vs, vs2)
+	assert.Equal(t, shares, shares2)
+	// a known answer, which only changes if the sampling of the polynomial does
+	assert.Equal(t, "c0206f83d1237733701c438ca3029e28d6fc174fb9e0ffd4feef3b88bbf072bc", shares[0].Share.Text(16))
+
+	_, shares3 := create(2)
+	assert.NotEqual(t, shares[0].Share, shares3[0].Share)
+	for _, share := range shares {
+		assert.True(t, share.Verify(tss.EC(), threshold, vs))
+	}
+}
+
 func TestVerify(t *testing.T) {
 	num, threshold := 5, 3
 
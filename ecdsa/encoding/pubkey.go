@@ -0,0 +1,172 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package encoding
+
+import (
+	"crypto/elliptic"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcutil"
+	"golang.org/x/crypto/sha3"
+
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/crypto/ckd"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+const (
+	sec1Even         byte = 0x02
+	sec1Odd          byte = 0x03
+	sec1Uncompressed byte = 0x04
+)
+
+// SerializeCompressed encodes the public key in the SEC1 compressed form: 0x02/0x03 || X
+func SerializeCompressed(pub *crypto.ECPoint) []byte {
+	size := coordinateSize(pub.Curve())
+	out := make([]byte, 1+size)
+	out[0] = sec1Even
+	if pub.Y().Bit(0) == 1 {
+		out[0] = sec1Odd
+	}
+	pub.X().FillBytes(out[1:])
+	return out
+}
+
+// SerializeUncompressed encodes the public key in the SEC1 uncompressed form: 0x04 || X || Y
+func SerializeUncompressed(pub *crypto.ECPoint) []byte {
+	size := coordinateSize(pub.Curve())
+	out := make([]byte, 1+2*size)
+	out[0] = sec1Uncompressed
+	pub.X().FillBytes(out[1 : 1+size])
+	pub.Y().FillBytes(out[1+size:])
+	return out
+}
+
+// ParsePublicKey decodes a SEC1 compressed or uncompressed public key on `ec`
+func ParsePublicKey(ec elliptic.Curve, b []byte) (*crypto.ECPoint, error) {
+	size := coordinateSize(ec)
+	switch {
+	case len(b) == 1+size && (b[0] == sec1Even || b[0] == sec1Odd):
+		x := new(big.Int).SetBytes(b[1:])
+		if x.Cmp(ec.Params().P) >= 0 {
+			return nil, errors.New("public key X is not a field element")
+		}
+		y, err := decompressY(ec, x, b[0] == sec1Odd)
+		if err != nil {
+			return nil, err
+		}
+		return crypto.NewECPoint(ec, x, y)
+	case len(b) == 1+2*size && b[0] == sec1Uncompressed:
+		return crypto.NewECPoint(ec, new(big.Int).SetBytes(b[1:1+size]), new(big.Int).SetBytes(b[1+size:]))
+	default:
+		return nil, fmt.Errorf("malformed SEC1 public key of %d bytes", len(b))
+	}
+}
+
+// PublicKeyFromExtendedKey returns the public key of an extended key, e.g. a child derived with crypto/ckd
+func PublicKeyFromExtendedKey(k *ckd.ExtendedKey) (*crypto.ECPoint, error) {
+	if k == nil || k.Curve == nil {
+		return nil, errors.New("extended key has no curve")
+	}
+	return crypto.NewECPoint(k.Curve, k.X, k.Y)
+}
+
+// EthereumAddress returns the EIP-55 checksummed address of a secp256k1 public key
+func EthereumAddress(pub *crypto.ECPoint) (string, error) {
+	if err := requireSecp256k1(pub); err != nil {
+		return "", err
+	}
+	state := sha3.NewLegacyKeccak256()
+	_, _ = state.Write(SerializeUncompressed(pub)[1:])
+	return toChecksumAddress(hex.EncodeToString(state.Sum(nil)[12:])), nil
+}
+
+// BitcoinP2PKH returns the pay-to-pubkey-hash address of the compressed public key on `net`
+// (e.g. chaincfg.MainNetParams, TestNet3Params or RegressionNetParams)
+func BitcoinP2PKH(pub *crypto.ECPoint, net *chaincfg.Params) (string, error) {
+	pkHash, err := bitcoinKeyHash(pub)
+	if err != nil {
+		return "", err
+	}
+	addr, err := btcutil.NewAddressPubKeyHash(pkHash, net)
+	if err != nil {
+		return "", err
+	}
+	return addr.EncodeAddress(), nil
+}
+
+// BitcoinP2WPKH returns the native segwit v0 (bech32) address of the compressed public key on `net`
+func BitcoinP2WPKH(pub *crypto.ECPoint, net *chaincfg.Params) (string, error) {
+	pkHash, err := bitcoinKeyHash(pub)
+	if err != nil {
+		return "", err
+	}
+	addr, err := btcutil.NewAddressWitnessPubKeyHash(pkHash, net)
+	if err != nil {
+		return "", err
+	}
+	return addr.EncodeAddress(), nil
+}
+
+// BitcoinP2SHP2WPKH returns the P2WPKH address of the compressed public key nested in P2SH on `net`
+func BitcoinP2SHP2WPKH(pub *crypto.ECPoint, net *chaincfg.Params) (string, error) {
+	pkHash, err := bitcoinKeyHash(pub)
+	if err != nil {
+		return "", err
+	}
+	// OP_0 <20-byte key hash>
+	redeemScript := append([]byte{0x00, 0x14}, pkHash...)
+	addr, err := btcutil.NewAddressScriptHash(redeemScript, net)
+	if err != nil {
+		return "", err
+	}
+	return addr.EncodeAddress(), nil
+}
+
+// ----- //
+
+func coordinateSize(ec elliptic.Curve) int {
+	return (ec.Params().BitSize + 7) / 8
+}
+
+func requireSecp256k1(pub *crypto.ECPoint) error {
+	if pub == nil || !pub.IsOnCurve() {
+		return errors.New("public key is not a curve point")
+	}
+	if !crypto.SameCurve(pub.Curve(), tss.S256()) {
+		return errors.New("addresses are only defined for secp256k1 public keys")
+	}
+	return nil
+}
+
+func bitcoinKeyHash(pub *crypto.ECPoint) ([]byte, error) {
+	if err := requireSecp256k1(pub); err != nil {
+		return nil, err
+	}
+	return btcutil.Hash160(SerializeCompressed(pub)), nil
+}
+
+// toChecksumAddress applies EIP-55 to a lowercase hex address without the 0x prefix
+func toChecksumAddress(lower string) string {
+	state := sha3.NewLegacyKeccak256()
+	_, _ = state.Write([]byte(lower))
+	hash := hex.EncodeToString(state.Sum(nil))
+	var b strings.Builder
+	b.WriteString("0x")
+	for i, c := range lower {
+		if c >= 'a' && c <= 'f' && hash[i] >= '8' {
+			c -= 'a' - 'A'
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
@@ -0,0 +1,128 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package encoding
+
+import (
+	"crypto/elliptic"
+	"math/big"
+	"testing"
+
+	s256k1 "github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcutil/hdkeychain"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/crypto/ckd"
+)
+
+// the public key of private key 1 is the generator
+func generator(t *testing.T) *crypto.ECPoint {
+	G := crypto.ScalarBaseMult(s256k1.S256(), big.NewInt(1))
+	assert.NotNil(t, G)
+	return G
+}
+
+func TestSEC1RoundTrip(t *testing.T) {
+	for _, ec := range []elliptic.Curve{s256k1.S256(), elliptic.P256()} {
+		for i := int64(1); i < 8; i++ {
+			pub := crypto.ScalarBaseMult(ec, big.NewInt(i))
+			compressed := SerializeCompressed(pub)
+			uncompressed := SerializeUncompressed(pub)
+			assert.Len(t, compressed, 33)
+			assert.Len(t, uncompressed, 65)
+			for _, b := range [][]byte{compressed, uncompressed} {
+				back, err := ParsePublicKey(ec, b)
+				assert.NoError(t, err)
+				assert.True(t, back.Equals(pub))
+			}
+		}
+	}
+	// btcec agrees on the encodings
+	_, btcPub := s256k1.PrivKeyFromBytes(s256k1.S256(), []byte{7})
+	pub, err := crypto.NewECPoint(s256k1.S256(), btcPub.X, btcPub.Y)
+	assert.NoError(t, err)
+	assert.Equal(t, btcPub.SerializeCompressed(), SerializeCompressed(pub))
+	assert.Equal(t, btcPub.SerializeUncompressed(), SerializeUncompressed(pub))
+
+	_, err = ParsePublicKey(s256k1.S256(), []byte{0x05, 1, 2})
+	assert.Error(t, err)
+	bad := SerializeCompressed(pub)
+	bad[0] = sec1Uncompressed
+	_, err = ParsePublicKey(s256k1.S256(), bad)
+	assert.Error(t, err)
+}
+
+func TestEthereumAddress(t *testing.T) {
+	addr, err := EthereumAddress(generator(t))
+	assert.NoError(t, err)
+	assert.Equal(t, "0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf", addr)
+
+	// EIP-55 test vector
+	assert.Equal(t, "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", toChecksumAddress("5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"))
+
+	_, err = EthereumAddress(crypto.ScalarBaseMult(elliptic.P256(), big.NewInt(1)))
+	assert.Error(t, err)
+}
+
+func TestBitcoinAddresses(t *testing.T) {
+	G := generator(t)
+	tests := []struct {
+		encode func(*crypto.ECPoint, *chaincfg.Params) (string, error)
+		net    *chaincfg.Params
+		want   string
+	}{
+		{BitcoinP2PKH, &chaincfg.MainNetParams, "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH"},
+		{BitcoinP2PKH, &chaincfg.TestNet3Params, "mrCDrCybB6J1vRfbwM5hemdJz73FwDBC8r"},
+		{BitcoinP2WPKH, &chaincfg.MainNetParams, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"},
+		{BitcoinP2WPKH, &chaincfg.TestNet3Params, "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx"},
+		{BitcoinP2SHP2WPKH, &chaincfg.MainNetParams, "3JvL6Ymt8MVWiCNHC7oWU6nLeHNJKLZGLN"},
+	}
+	for _, tt := range tests {
+		got, err := tt.encode(G, tt.net)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, got)
+	}
+
+	// regtest shares testnet's base58 prefixes but has its own bech32 prefix
+	p2pkh, err := BitcoinP2PKH(G, &chaincfg.RegressionNetParams)
+	assert.NoError(t, err)
+	assert.Equal(t, "mrCDrCybB6J1vRfbwM5hemdJz73FwDBC8r", p2pkh)
+	p2wpkh, err := BitcoinP2WPKH(G, &chaincfg.RegressionNetParams)
+	assert.NoError(t, err)
+	assert.Equal(t, "bcrt1", p2wpkh[:5])
+	p2sh, err := BitcoinP2SHP2WPKH(G, &chaincfg.RegressionNetParams)
+	assert.NoError(t, err)
+	assert.Equal(t, byte('2'), p2sh[0])
+}
+
+func TestAddressesOfDerivedChild(t *testing.T) {
+	seed := make([]byte, hdkeychain.RecommendedSeedLen)
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+	master, err := hdkeychain.NewMaster(seed, &chaincfg.MainNetParams)
+	assert.NoError(t, err)
+	xpub, err := master.Neuter()
+	assert.NoError(t, err)
+	btcChild, err := xpub.Child(5)
+	assert.NoError(t, err)
+	want, err := btcChild.Address(&chaincfg.MainNetParams)
+	assert.NoError(t, err)
+
+	parent, err := ckd.NewExtendedKeyFromString(xpub.String(), s256k1.S256())
+	assert.NoError(t, err)
+	_, child, err := ckd.DeriveChildKey(5, parent, s256k1.S256())
+	assert.NoError(t, err)
+	pub, err := PublicKeyFromExtendedKey(child)
+	assert.NoError(t, err)
+	got, err := BitcoinP2PKH(pub, &chaincfg.MainNetParams)
+	assert.NoError(t, err)
+	assert.Equal(t, want.EncodeAddress(), got)
+	_, err = EthereumAddress(pub)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,307 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package encoding converts the output of the ECDSA protocols into the formats used by blockchains and other
+// ECDSA implementations.
+package encoding
+
+import (
+	"crypto/elliptic"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+
+	s256k1 "github.com/btcsuite/btcd/btcec"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/crypto"
+)
+
+// SigHashAll is the Bitcoin sighash type appended to DER signatures in transaction inputs
+const SigHashAll byte = 0x01
+
+const (
+	ethereumV         = 27
+	eip155VOffset     = 35
+	bitcoinHeader     = 27
+	bitcoinCompressed = 4
+	compactLen        = 65
+)
+
+type derSignature struct {
+	R, S *big.Int
+}
+
+// ToDER encodes the signature as an ASN.1 DER SEQUENCE of R and S
+func ToDER(data *common.SignatureData) ([]byte, error) {
+	r, s, err := rs(data)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(derSignature{r, s})
+}
+
+// ToDERWithSigHash encodes the signature as DER followed by the sighash type byte, as in Bitcoin script
+func ToDERWithSigHash(data *common.SignatureData, sigHashType byte) ([]byte, error) {
+	der, err := ToDER(data)
+	if err != nil {
+		return nil, err
+	}
+	return append(der, sigHashType), nil
+}
+
+// ParseDER decodes a DER signature. The result has no recovery byte.
+func ParseDER(ec elliptic.Curve, der []byte) (*common.SignatureData, error) {
+	var sig derSignature
+	rest, err := asn1.Unmarshal(der, &sig)
+	if err != nil {
+		return nil, fmt.Errorf("malformed DER signature: %v", err)
+	}
+	if len(rest) != 0 {
+		return nil, errors.New("malformed DER signature: trailing bytes")
+	}
+	if canonical, err := asn1.Marshal(sig); err != nil || len(canonical) != len(der) {
+		return nil, errors.New("malformed DER signature: not canonically encoded")
+	}
+	return newSignatureData(ec, sig.R, sig.S, nil)
+}
+
+// ParseDERWithSigHash decodes a DER signature followed by a sighash type byte
+func ParseDERWithSigHash(ec elliptic.Curve, sig []byte) (*common.SignatureData, byte, error) {
+	if len(sig) < 2 {
+		return nil, 0, errors.New("malformed DER signature: too short")
+	}
+	data, err := ParseDER(ec, sig[:len(sig)-1])
+	if err != nil {
+		return nil, 0, err
+	}
+	return data, sig[len(sig)-1], nil
+}
+
+// EthereumV returns the Ethereum v value: 27/28 when chainID is nil, otherwise recid + 2*chainID + 35 (EIP-155)
+func EthereumV(data *common.SignatureData, chainID *big.Int) (*big.Int, error) {
+	recid, err := recoveryID(data)
+	if err != nil {
+		return nil, err
+	}
+	if recid > 1 {
+		return nil, fmt.Errorf("recovery id %d cannot be expressed in Ethereum's v", recid)
+	}
+	if chainID == nil {
+		return big.NewInt(int64(ethereumV + recid)), nil
+	}
+	if chainID.Sign() <= 0 {
+		return nil, errors.New("EIP-155 chain ID must be positive")
+	}
+	v := new(big.Int).Lsh(chainID, 1)
+	return v.Add(v, big.NewInt(int64(eip155VOffset+recid))), nil
+}
+
+// ToEthereum encodes the signature as the 65-byte r || s || v. With a chainID v follows EIP-155, which only fits in
+// the final byte for chain IDs up to 110; use EthereumV for larger ones.
+func ToEthereum(data *common.SignatureData, chainID *big.Int) ([]byte, error) {
+	v, err := EthereumV(data, chainID)
+	if err != nil {
+		return nil, err
+	}
+	if v.BitLen() > 8 {
+		return nil, fmt.Errorf("v=%s does not fit in a 65-byte signature", v)
+	}
+	sig, err := rsBytes(data)
+	if err != nil {
+		return nil, err
+	}
+	return append(sig, byte(v.Uint64())), nil
+}
+
+// ParseEthereum decodes a 65-byte r || s || v signature produced with the same chainID (nil for v=27/28)
+func ParseEthereum(sig []byte, chainID *big.Int) (*common.SignatureData, error) {
+	if len(sig) != compactLen {
+		return nil, fmt.Errorf("ethereum signature must be %d bytes, got %d", compactLen, len(sig))
+	}
+	base := big.NewInt(ethereumV)
+	if chainID != nil {
+		base = new(big.Int).Add(new(big.Int).Lsh(chainID, 1), big.NewInt(eip155VOffset))
+	}
+	recid := new(big.Int).Sub(big.NewInt(int64(sig[64])), base)
+	if recid.Sign() < 0 || recid.Cmp(big.NewInt(1)) > 0 {
+		return nil, fmt.Errorf("invalid v %d for this chain ID", sig[64])
+	}
+	return newSignatureData(s256k1.S256(), new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:64]), []byte{byte(recid.Int64())})
+}
+
+// ToBitcoinCompact encodes the signature as Bitcoin's 65-byte compact format: a header byte
+// 27 + recid (+4 for a compressed public key) followed by r || s
+func ToBitcoinCompact(data *common.SignatureData, compressed bool) ([]byte, error) {
+	recid, err := recoveryID(data)
+	if err != nil {
+		return nil, err
+	}
+	header := byte(bitcoinHeader + recid)
+	if compressed {
+		header += bitcoinCompressed
+	}
+	sig, err := rsBytes(data)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{header}, sig...), nil
+}
+
+// ParseBitcoinCompact decodes a Bitcoin compact signature and reports whether it refers to a compressed public key
+func ParseBitcoinCompact(sig []byte) (*common.SignatureData, bool, error) {
+	if len(sig) != compactLen {
+		return nil, false, fmt.Errorf("compact signature must be %d bytes, got %d", compactLen, len(sig))
+	}
+	header := int(sig[0]) - bitcoinHeader
+	if header < 0 || header > 7 {
+		return nil, false, fmt.Errorf("invalid compact signature header %d", sig[0])
+	}
+	compressed := header&bitcoinCompressed != 0
+	data, err := newSignatureData(s256k1.S256(), new(big.Int).SetBytes(sig[1:33]), new(big.Int).SetBytes(sig[33:]), []byte{byte(header & 3)})
+	return data, compressed, err
+}
+
+// RecoverPublicKey recovers the signer's public key from the signature, its recovery byte and the signed digest
+// (SEC 1 v2, 4.1.6). The digest is truncated to the curve order like crypto/ecdsa does.
+func RecoverPublicKey(ec elliptic.Curve, data *common.SignatureData, digest []byte) (*crypto.ECPoint, error) {
+	r, s, err := rs(data)
+	if err != nil {
+		return nil, err
+	}
+	recid, err := recoveryID(data)
+	if err != nil {
+		return nil, err
+	}
+	N := ec.Params().N
+	if r.Sign() <= 0 || r.Cmp(N) >= 0 || s.Sign() <= 0 || s.Cmp(N) >= 0 {
+		return nil, errors.New("signature values are out of range")
+	}
+	x := new(big.Int).Set(r)
+	if recid&2 != 0 {
+		x.Add(x, N)
+	}
+	if x.Cmp(ec.Params().P) >= 0 {
+		return nil, errors.New("recovery id points outside the field")
+	}
+	y, err := decompressY(ec, x, recid&1 == 1)
+	if err != nil {
+		return nil, err
+	}
+	// Q = r^-1 (sR - eG), where eG is the point at infinity if the digest reduces to zero
+	Qx, Qy := ec.ScalarMult(x, y, s.Bytes())
+	if e := new(big.Int).Mod(hashToInt(ec, digest), N); e.Sign() != 0 {
+		eGx, eGy := ec.ScalarBaseMult(e.Bytes())
+		eGy = new(big.Int).Sub(ec.Params().P, eGy)
+		Qx, Qy = ec.Add(Qx, Qy, eGx, eGy)
+	}
+	rInv := new(big.Int).ModInverse(r, N)
+	Qx, Qy = ec.ScalarMult(Qx, Qy, rInv.Bytes())
+	Q, err := crypto.NewECPoint(ec, Qx, Qy)
+	if err != nil {
+		return nil, errors.New("recovered public key is not on the curve")
+	}
+	return Q, nil
+}
+
+// CheckRecovery cross-checks the recovery byte of `data`: the key it recovers from `digest` must be `pub`
+func CheckRecovery(ec elliptic.Curve, data *common.SignatureData, digest []byte, pub *crypto.ECPoint) error {
+	Q, err := RecoverPublicKey(ec, data, digest)
+	if err != nil {
+		return err
+	}
+	if !Q.Equals(pub) {
+		return errors.New("the recovery byte does not recover the expected public key")
+	}
+	return nil
+}
+
+// ----- //
+
+func rs(data *common.SignatureData) (*big.Int, *big.Int, error) {
+	if data == nil || len(data.GetR()) == 0 || len(data.GetS()) == 0 {
+		return nil, nil, errors.New("signature data has no R or S")
+	}
+	return new(big.Int).SetBytes(data.GetR()), new(big.Int).SetBytes(data.GetS()), nil
+}
+
+// rsBytes returns r || s with each value left-padded to 32 bytes, as the secp256k1 formats require
+func rsBytes(data *common.SignatureData) ([]byte, error) {
+	r, s, err := rs(data)
+	if err != nil {
+		return nil, err
+	}
+	if r.BitLen() > 256 || s.BitLen() > 256 {
+		return nil, errors.New("signature values are wider than 32 bytes")
+	}
+	out := make([]byte, 64)
+	r.FillBytes(out[:32])
+	s.FillBytes(out[32:])
+	return out, nil
+}
+
+func recoveryID(data *common.SignatureData) (int, error) {
+	if data == nil || len(data.GetSignatureRecovery()) == 0 {
+		return 0, errors.New("signature data has no recovery byte")
+	}
+	recid := int(data.GetSignatureRecovery()[0])
+	if recid > 3 {
+		return 0, fmt.Errorf("invalid recovery id %d", recid)
+	}
+	return recid, nil
+}
+
+func newSignatureData(ec elliptic.Curve, r, s *big.Int, recovery []byte) (*common.SignatureData, error) {
+	N := ec.Params().N
+	if r.Sign() <= 0 || r.Cmp(N) >= 0 || s.Sign() <= 0 || s.Cmp(N) >= 0 {
+		return nil, errors.New("signature values are out of range")
+	}
+	size := (ec.Params().BitSize + 7) / 8
+	R := r.FillBytes(make([]byte, size))
+	S := s.FillBytes(make([]byte, size))
+	return &common.SignatureData{
+		Signature:         append(append([]byte{}, R...), S...),
+		SignatureRecovery: recovery,
+		R:                 R,
+		S:                 S,
+	}, nil
+}
+
+func hashToInt(ec elliptic.Curve, digest []byte) *big.Int {
+	orderBits := ec.Params().N.BitLen()
+	orderBytes := (orderBits + 7) / 8
+	if len(digest) > orderBytes {
+		digest = digest[:orderBytes]
+	}
+	e := new(big.Int).SetBytes(digest)
+	if excess := len(digest)*8 - orderBits; excess > 0 {
+		e.Rsh(e, uint(excess))
+	}
+	return e
+}
+
+// decompressY solves y^2 = x^3 + ax + b for the y with the given parity; a is 0 for secp256k1 and -3 for the
+// curves of crypto/elliptic
+func decompressY(ec elliptic.Curve, x *big.Int, odd bool) (*big.Int, error) {
+	P := ec.Params().P
+	y2 := new(big.Int).Exp(x, big.NewInt(3), P)
+	if _, koblitz := ec.(*s256k1.KoblitzCurve); !koblitz {
+		y2.Sub(y2, new(big.Int).Mul(x, big.NewInt(3)))
+	}
+	y2.Add(y2, ec.Params().B).Mod(y2, P)
+	y := new(big.Int).ModSqrt(y2, P)
+	if y == nil {
+		return nil, errors.New("x is not the coordinate of a curve point")
+	}
+	if (y.Bit(0) == 1) != odd {
+		y.Sub(P, y)
+	}
+	if !ec.IsOnCurve(x, y) {
+		return nil, errors.New("x is not the coordinate of a curve point")
+	}
+	return y, nil
+}
@@ -0,0 +1,178 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package encoding
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	s256k1 "github.com/btcsuite/btcd/btcec"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/crypto"
+)
+
+// btcecSignature signs `digest` with btcec and returns it in the shape finalize produces
+func btcecSignature(t *testing.T, priv *s256k1.PrivateKey, digest []byte) (*common.SignatureData, []byte) {
+	compact, err := s256k1.SignCompact(s256k1.S256(), priv, digest, true)
+	assert.NoError(t, err)
+	data := &common.SignatureData{
+		R:                 compact[1:33],
+		S:                 compact[33:],
+		Signature:         compact[1:],
+		SignatureRecovery: []byte{compact[0] - 27 - 4},
+	}
+	return data, compact
+}
+
+func TestSignatureEncodingsRoundTripWithBtcec(t *testing.T) {
+	for i := 0; i < 16; i++ {
+		priv, err := s256k1.NewPrivateKey(s256k1.S256())
+		assert.NoError(t, err)
+		digest := sha256.Sum256([]byte{byte(i)})
+		data, compact := btcecSignature(t, priv, digest[:])
+		btcSig := &s256k1.Signature{R: new(big.Int).SetBytes(data.R), S: new(big.Int).SetBytes(data.S)}
+
+		// DER
+		der, err := ToDER(data)
+		assert.NoError(t, err)
+		assert.Equal(t, btcSig.Serialize(), der)
+		parsed, err := s256k1.ParseDERSignature(der, s256k1.S256())
+		assert.NoError(t, err)
+		assert.True(t, parsed.IsEqual(btcSig))
+		back, err := ParseDER(s256k1.S256(), der)
+		assert.NoError(t, err)
+		assert.Equal(t, data.Signature, back.Signature)
+
+		withHash, err := ToDERWithSigHash(data, SigHashAll)
+		assert.NoError(t, err)
+		back, sigHash, err := ParseDERWithSigHash(s256k1.S256(), withHash)
+		assert.NoError(t, err)
+		assert.Equal(t, SigHashAll, sigHash)
+		assert.Equal(t, data.R, back.R)
+
+		// Bitcoin compact
+		ours, err := ToBitcoinCompact(data, true)
+		assert.NoError(t, err)
+		assert.Equal(t, compact, ours)
+		back, compressed, err := ParseBitcoinCompact(ours)
+		assert.NoError(t, err)
+		assert.True(t, compressed)
+		assert.Equal(t, data.SignatureRecovery, back.SignatureRecovery)
+		uncompressed, err := ToBitcoinCompact(data, false)
+		assert.NoError(t, err)
+		pub, wasCompressed, err := s256k1.RecoverCompact(s256k1.S256(), uncompressed, digest[:])
+		assert.NoError(t, err)
+		assert.False(t, wasCompressed)
+		assert.True(t, pub.IsEqual(priv.PubKey()))
+
+		// Ethereum
+		eth, err := ToEthereum(data, nil)
+		assert.NoError(t, err)
+		assert.Len(t, eth, 65)
+		assert.Equal(t, byte(27)+data.SignatureRecovery[0], eth[64])
+		assert.Equal(t, data.Signature, eth[:64])
+		back, err = ParseEthereum(eth, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, data.SignatureRecovery, back.SignatureRecovery)
+
+		eip155, err := ToEthereum(data, big.NewInt(1))
+		assert.NoError(t, err)
+		assert.Equal(t, byte(37)+data.SignatureRecovery[0], eip155[64])
+		back, err = ParseEthereum(eip155, big.NewInt(1))
+		assert.NoError(t, err)
+		assert.Equal(t, data.SignatureRecovery, back.SignatureRecovery)
+		_, err = ParseEthereum(eip155, nil)
+		assert.Error(t, err, "an EIP-155 v must not parse as a legacy v")
+
+		// recovery
+		Q, err := RecoverPublicKey(s256k1.S256(), data, digest[:])
+		assert.NoError(t, err)
+		assert.Equal(t, 0, Q.X().Cmp(priv.PubKey().X))
+		assert.Equal(t, 0, Q.Y().Cmp(priv.PubKey().Y))
+		pubPoint, err := crypto.NewECPoint(s256k1.S256(), priv.PubKey().X, priv.PubKey().Y)
+		assert.NoError(t, err)
+		assert.NoError(t, CheckRecovery(s256k1.S256(), data, digest[:], pubPoint))
+		flipped := &common.SignatureData{R: data.R, S: data.S, SignatureRecovery: []byte{data.SignatureRecovery[0] ^ 1}}
+		assert.Error(t, CheckRecovery(s256k1.S256(), flipped, digest[:], pubPoint))
+	}
+}
+
+func TestEthereumVForLargeChainIDs(t *testing.T) {
+	data := &common.SignatureData{R: []byte{1}, S: []byte{2}, SignatureRecovery: []byte{1}}
+	v, err := EthereumV(data, big.NewInt(1000))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2036), v.Int64())
+	_, err = ToEthereum(data, big.NewInt(1000))
+	assert.Error(t, err)
+
+	data.SignatureRecovery = []byte{2}
+	_, err = EthereumV(data, nil)
+	assert.Error(t, err)
+}
+
+func TestParseRejectsMalformed(t *testing.T) {
+	_, err := ParseDER(s256k1.S256(), []byte{0x30, 0x00})
+	assert.Error(t, err)
+	_, _, err = ParseBitcoinCompact(make([]byte, 64))
+	assert.Error(t, err)
+	sig := make([]byte, 65)
+	sig[0] = 40
+	_, _, err = ParseBitcoinCompact(sig)
+	assert.Error(t, err)
+	_, err = ParseEthereum(make([]byte, 65), nil)
+	assert.Error(t, err)
+
+	digest := sha256.Sum256([]byte("malformed"))
+	N := s256k1.S256().Params().N
+	one := big.NewInt(1).Bytes()
+	for name, data := range map[string]*common.SignatureData{
+		"r is zero": {R: []byte{0}, S: one, SignatureRecovery: []byte{0}},
+		"s is zero": {R: one, S: []byte{0}, SignatureRecovery: []byte{0}},
+		"r is N":    {R: N.Bytes(), S: one, SignatureRecovery: []byte{0}},
+		"s above N": {R: one, S: new(big.Int).Add(N, big.NewInt(1)).Bytes(), SignatureRecovery: []byte{0}},
+	} {
+		_, err = RecoverPublicKey(s256k1.S256(), data, digest[:])
+		assert.Error(t, err, name)
+	}
+}
+
+func TestRecoverPublicKeyDigestReducingToZero(t *testing.T) {
+	priv, err := s256k1.NewPrivateKey(s256k1.S256())
+	assert.NoError(t, err)
+	pub, err := crypto.NewECPoint(s256k1.S256(), priv.X, priv.Y)
+	assert.NoError(t, err)
+	// SEC 1 does not exclude e = 0, where Q = r^-1 sR
+	for name, digest := range map[string][]byte{"zero digest": make([]byte, 32), "digest equal to N": s256k1.S256().Params().N.Bytes()} {
+		data, _ := btcecSignature(t, priv, digest)
+		assert.NoError(t, CheckRecovery(s256k1.S256(), data, digest, pub), name)
+	}
+}
+
+func TestRecoverPublicKeyP256(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	pub, err := crypto.NewECPoint(elliptic.P256(), priv.X, priv.Y)
+	assert.NoError(t, err)
+	digest := sha256.Sum256([]byte("p-256"))
+	r, s, err := ecdsa.Sign(rand.Reader, priv, digest[:])
+	assert.NoError(t, err)
+
+	matched := 0
+	for recid := byte(0); recid < 2; recid++ {
+		data := &common.SignatureData{R: r.Bytes(), S: s.Bytes(), SignatureRecovery: []byte{recid}}
+		if CheckRecovery(elliptic.P256(), data, digest[:], pub) == nil {
+			matched++
+		}
+	}
+	assert.Equal(t, 1, matched, "exactly one recovery id must recover the key")
+}
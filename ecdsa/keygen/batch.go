@@ -0,0 +1,431 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+var batchNonceTag = []byte("tss-lib/ecdsa/keygen/batch")
+
+// Implements Party
+// Implements Stringer
+var _ tss.Party = (*BatchLocalParty)(nil)
+var _ fmt.Stringer = (*BatchLocalParty)(nil)
+var _ tss.Round = (*batchRound)(nil)
+
+type (
+	// BatchLocalParty generates several independent keys in a single keygen ceremony. Every key has its own
+	// VSS polynomial, commitments and complaint phase, but all keys share the party's pre-params: the Paillier
+	// and NTilde proofs (DLN, mod, factor and Paillier key proofs) are sent and verified once, for the first
+	// key, and the messages of all keys travel together in one KGBatchMessage per round and recipient.
+	// The save data of every key is delivered together, in key order, on `end`.
+	BatchLocalParty struct {
+		*tss.BaseParty
+		params *tss.Parameters
+
+		items   []*LocalParty
+		itemOut []chan tss.Message
+		itemEnd []chan LocalPartySaveData
+
+		// outbound messaging
+		out chan<- tss.Message
+		end chan<- []LocalPartySaveData
+	}
+
+	// batchRound runs the same round of every key in lockstep
+	batchRound struct {
+		party  *BatchLocalParty
+		rounds []tss.Round
+		number int
+	}
+
+	// BatchItemError is the cause of a *tss.Error returned by a BatchLocalParty. Index is the position of the
+	// key whose generation failed; the *tss.Error's culprits are the culprits for that key.
+	BatchItemError struct {
+		Index int
+		Err   error
+	}
+)
+
+func (e *BatchItemError) Error() string {
+	return fmt.Sprintf("batch key %d: %v", e.Index, e.Err)
+}
+
+func (e *BatchItemError) Unwrap() error {
+	return e.Err
+}
+
+// NewBatchLocalParty returns a party that generates `keyCount` keys in one ceremony. All parties must use the
+// same keyCount. As with NewLocalParty, `optionalPreParams` skips the generation of the safe primes.
+func NewBatchLocalParty(
+	params *tss.Parameters,
+	keyCount int,
+	out chan<- tss.Message,
+	end chan<- []LocalPartySaveData,
+	optionalPreParams ...LocalPreParams,
+) tss.Party {
+	if keyCount < 1 {
+		panic(fmt.Errorf("keygen.NewBatchLocalParty: keyCount must be positive, got %d", keyCount))
+	}
+	partyCount := params.PartyCount()
+	p := &BatchLocalParty{
+		BaseParty: new(tss.BaseParty),
+		params:    params,
+		items:     make([]*LocalParty, keyCount),
+		itemOut:   make([]chan tss.Message, keyCount),
+		itemEnd:   make([]chan LocalPartySaveData, keyCount),
+		out:       out,
+		end:       end,
+	}
+	for k := range p.items {
+		// each key runs under its own session nonce, derived in Start
+		itemParams := *params
+		p.itemOut[k] = make(chan tss.Message, partyCount)
+		p.itemEnd[k] = make(chan LocalPartySaveData, 1)
+		p.items[k] = NewLocalParty(&itemParams, p.itemOut[k], p.itemEnd[k], optionalPreParams...).(*LocalParty)
+		if k > 0 {
+			p.items[k].temp.leader = &p.items[0].temp
+		}
+	}
+	return p
+}
+
+func (p *BatchLocalParty) FirstRound() tss.Round {
+	rounds := make([]tss.Round, len(p.items))
+	for k, item := range p.items {
+		rounds[k] = item.FirstRound()
+	}
+	return &batchRound{party: p, rounds: rounds, number: 1}
+}
+
+func (p *BatchLocalParty) Start() *tss.Error {
+	return tss.BaseStart(p, TaskName, func(round tss.Round) *tss.Error {
+		nonce := p.params.SessionNonce()
+		if nonce == nil || nonce.Sign() <= 0 {
+			return round.WrapError(errors.New("batch keygen requires tss.Parameters.SetSessionNonce(<unique positive per-ceremony nonce>) before Start"))
+		}
+		for k, item := range p.items {
+			item.params.SetSessionNonce(common.SHA512_256i_TAGGED(batchNonceTag, nonce, big.NewInt(int64(k))))
+		}
+		return nil
+	})
+}
+
+func (p *BatchLocalParty) Update(msg tss.ParsedMessage) (ok bool, err *tss.Error) {
+	return tss.BaseUpdate(p, msg, TaskName)
+}
+
+func (p *BatchLocalParty) UpdateFromBytes(wireBytes []byte, from *tss.PartyID, isBroadcast bool) (bool, *tss.Error) {
+	msg, err := tss.ParseWireMessage(wireBytes, from, isBroadcast)
+	if err != nil {
+		return false, p.WrapError(err)
+	}
+	return p.Update(msg)
+}
+
+func (p *BatchLocalParty) ValidateMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
+	if ok, err := p.BaseParty.ValidateMessage(msg); !ok || err != nil {
+		return ok, err
+	}
+	// check that the message's "from index" will fit into the array
+	if maxFromIdx := p.params.PartyCount() - 1; maxFromIdx < msg.GetFrom().Index {
+		return false, p.WrapError(fmt.Errorf("received msg with a sender index too great (%d <= %d)",
+			p.params.PartyCount(), msg.GetFrom().Index), msg.GetFrom())
+	}
+	return true, nil
+}
+
+// StoreMessage unpacks a KGBatchMessage and stores each item with the party generating the matching key. The items
+// of the further keys are always completed with the Paillier and NTilde proofs of the first key's item in the same
+// batch, as their own parties skip those proofs. Only the round 4 justifications may leave the item of a key empty,
+// for a dealer that was not accused in that key's complaint phase.
+func (p *BatchLocalParty) StoreMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
+	if ok, err := p.ValidateMessage(msg); !ok || err != nil {
+		return ok, err
+	}
+	batch, ok := msg.Content().(*KGBatchMessage)
+	if !ok { // unrecognised message, just ignore!
+		p.params.Logger().With(tss.LogKeyTask, TaskName).Warn("unrecognised message ignored", "msg", msg.String())
+		return false, nil
+	}
+	if len(batch.GetItems()) != len(p.items) {
+		return false, p.WrapError(fmt.Errorf("batch message from party %d holds %d items, expected %d",
+			msg.GetFrom().Index, len(batch.GetItems()), len(p.items)), msg.GetFrom())
+	}
+	itemMsgs := make([]tss.ParsedMessage, len(p.items))
+	empty, justifications := 0, true
+	for k, bz := range batch.GetItems() {
+		if len(bz) == 0 {
+			empty++
+			continue
+		}
+		itemMsg, err := tss.ParseWireMessage(bz, msg.GetFrom(), msg.IsBroadcast())
+		if err != nil {
+			return false, p.WrapError(&BatchItemError{Index: k, Err: err}, msg.GetFrom())
+		}
+		if _, ok := itemMsg.Content().(*KGRound4Message); !ok {
+			justifications = false
+		}
+		itemMsgs[k] = itemMsg
+	}
+	if empty == len(itemMsgs) || (0 < empty && !justifications) {
+		return false, p.WrapError(fmt.Errorf("batch message from party %d leaves %d of %d items empty outside the complaint phase",
+			msg.GetFrom().Index, empty, len(itemMsgs)), msg.GetFrom())
+	}
+	for k, itemMsg := range itemMsgs {
+		if itemMsg == nil {
+			continue // nothing for this key
+		}
+		if k > 0 && itemMsgs[0] != nil {
+			content, err := expandFollowerContent(itemMsg.Content(), itemMsgs[0].Content())
+			if err != nil {
+				return false, p.WrapError(&BatchItemError{Index: k, Err: err}, msg.GetFrom())
+			}
+			meta := tss.MessageRouting{From: msg.GetFrom(), IsBroadcast: msg.IsBroadcast()}
+			itemMsg = tss.NewMessage(meta, content, tss.NewMessageWrapper(meta, content))
+		}
+		ok, tssErr := p.items[k].StoreMessage(itemMsg)
+		if tssErr != nil {
+			return false, p.WrapError(&BatchItemError{Index: k, Err: tssErr.Cause()}, tssErr.Culprits()...)
+		}
+		if !ok {
+			return false, p.WrapError(&BatchItemError{Index: k, Err: fmt.Errorf("unexpected %s", itemMsg.Type())}, msg.GetFrom())
+		}
+	}
+	return true, nil
+}
+
+func (p *BatchLocalParty) PartyID() *tss.PartyID {
+	return p.params.PartyID()
+}
+
+func (p *BatchLocalParty) String() string {
+	return fmt.Sprintf("id: %s, batch of %d, %s", p.PartyID(), len(p.items), p.BaseParty.String())
+}
+
+// ----- //
+
+func (round *batchRound) Params() *tss.Parameters {
+	return round.party.params
+}
+
+func (round *batchRound) RoundNumber() int {
+	return round.number
+}
+
+func (round *batchRound) Start() *tss.Error {
+	items := round.party.items
+	for k, r := range round.rounds {
+		if round.number == 1 && k > 0 {
+			// the leader has loaded or generated the pre-params by now; every key uses them
+			items[k].data.LocalPreParams = items[0].data.LocalPreParams
+		}
+		if err := r.Start(); err != nil {
+			return round.wrapItemError(k, err)
+		}
+	}
+	if _, final := round.rounds[0].(*round6); final {
+		saves := make([]LocalPartySaveData, len(items))
+		for k := range items {
+			saves[k] = <-round.party.itemEnd[k]
+		}
+		round.party.end <- saves
+		return nil
+	}
+	return round.flush()
+}
+
+// flush bundles the messages the keys' parties just sent, one KGBatchMessage per recipient. A key with nothing to
+// send to a recipient, such as a dealer that was not accused in that key's complaint phase, has an empty item.
+func (round *batchRound) flush() *tss.Error {
+	keyCount := len(round.party.items)
+	var bundles [][]tss.Message // one message or nil per key, all with the same routing
+	for k, ch := range round.party.itemOut {
+	drain:
+		for {
+			select {
+			case msg := <-ch:
+				bundle := -1
+				for b := range bundles {
+					if bundles[b][k] == nil && sameRouting(bundleRouting(bundles[b]), msg) {
+						bundle = b
+						break
+					}
+				}
+				if bundle < 0 {
+					bundle = len(bundles)
+					bundles = append(bundles, make([]tss.Message, keyCount))
+				}
+				bundles[bundle][k] = msg
+			default:
+				break drain
+			}
+		}
+	}
+	for _, bundle := range bundles {
+		items := make([][]byte, keyCount)
+		for k, msg := range bundle {
+			if msg == nil {
+				continue
+			}
+			// the receiver completes a further key's message with the first key's, so it is only compacted alongside one
+			if k > 0 && bundle[0] != nil {
+				msg = newFollowerMessage(round.party.PartyID(), msg.GetTo(), compactFollowerContent(msg.(tss.ParsedMessage).Content()))
+			}
+			bz, _, err := msg.WireBytes()
+			if err != nil {
+				return round.WrapError(&BatchItemError{Index: k, Err: err})
+			}
+			items[k] = bz
+		}
+		first := bundleRouting(bundle)
+		round.party.out <- NewKGBatchMessage(first.GetTo(), round.party.PartyID(), items)
+	}
+	return nil
+}
+
+// bundleRouting returns the first message of a bundle, whose routing every other message of it shares
+func bundleRouting(bundle []tss.Message) tss.Message {
+	for _, msg := range bundle {
+		if msg != nil {
+			return msg
+		}
+	}
+	return nil
+}
+
+func (round *batchRound) Update() (bool, *tss.Error) {
+	ret := true
+	for k, r := range round.rounds {
+		ok, err := r.Update()
+		if err != nil {
+			return false, round.wrapItemError(k, err)
+		}
+		ret = ret && ok
+	}
+	return ret, nil
+}
+
+func (round *batchRound) CanAccept(msg tss.ParsedMessage) bool {
+	_, ok := msg.Content().(*KGBatchMessage)
+	return ok
+}
+
+func (round *batchRound) CanProceed() bool {
+	for _, r := range round.rounds {
+		if !r.CanProceed() {
+			return false
+		}
+	}
+	return true
+}
+
+func (round *batchRound) NextRound() tss.Round {
+	next := make([]tss.Round, len(round.rounds))
+	for k, r := range round.rounds {
+		next[k] = r.NextRound()
+	}
+	if next[0] == nil {
+		return nil // finished!
+	}
+	return &batchRound{party: round.party, rounds: next, number: round.number + 1}
+}
+
+// WaitingFor reports the parties that the round of some key is still waiting for
+func (round *batchRound) WaitingFor() []*tss.PartyID {
+	waiting := make(map[int]bool)
+	for _, r := range round.rounds {
+		for _, Pj := range r.WaitingFor() {
+			waiting[Pj.Index] = true
+		}
+	}
+	ids := make([]*tss.PartyID, 0, len(waiting))
+	for _, Pj := range round.Params().Parties().IDs() {
+		if waiting[Pj.Index] {
+			ids = append(ids, Pj)
+		}
+	}
+	return ids
+}
+
+func (round *batchRound) WrapError(err error, culprits ...*tss.PartyID) *tss.Error {
+	return tss.NewError(err, TaskName, round.number, round.party.PartyID(), culprits...)
+}
+
+func (round *batchRound) wrapItemError(index int, err *tss.Error) *tss.Error {
+	return round.WrapError(&BatchItemError{Index: index, Err: err.Cause()}, err.Culprits()...)
+}
+
+// ----- //
+
+// newFollowerMessage wraps the content of a batch follower's message. A nil `to` broadcasts it.
+func newFollowerMessage(from *tss.PartyID, to []*tss.PartyID, content tss.MessageContent) tss.ParsedMessage {
+	meta := tss.MessageRouting{
+		From:        from,
+		To:          to,
+		IsBroadcast: to == nil,
+	}
+	return tss.NewMessage(meta, content, tss.NewMessageWrapper(meta, content))
+}
+
+// compactFollowerContent drops what a further key's message shares with the first key's: the Paillier and
+// NTilde keys and the proofs about them
+func compactFollowerContent(content tss.MessageContent) tss.MessageContent {
+	switch c := content.(type) {
+	case *KGRound1Message:
+		return &KGRound1Message{Commitment: c.GetCommitment()}
+	case *KGRound2Message1:
+		return &KGRound2Message1{Share: c.GetShare(), SubShares: c.GetSubShares()}
+	case *KGRound5Message:
+		return &KGRound5Message{}
+	default:
+		return content
+	}
+}
+
+// expandFollowerContent completes a compacted message of a further key with the first key's. Anything the
+// sender put in place of the shared fields is ignored, so every key is bound to the material the first key's
+// proofs were verified for.
+func expandFollowerContent(content, leader tss.MessageContent) (tss.MessageContent, error) {
+	if proto.MessageName(content) != proto.MessageName(leader) {
+		return nil, fmt.Errorf("got %s alongside %s", proto.MessageName(content), proto.MessageName(leader))
+	}
+	switch c := content.(type) {
+	case *KGRound1Message:
+		out := proto.Clone(leader).(*KGRound1Message)
+		out.Commitment = c.GetCommitment()
+		return out, nil
+	case *KGRound2Message1:
+		out := proto.Clone(leader).(*KGRound2Message1)
+		out.Share, out.SubShares = c.GetShare(), c.GetSubShares()
+		return out, nil
+	case *KGRound5Message:
+		return proto.Clone(leader).(*KGRound5Message), nil
+	default:
+		return content, nil
+	}
+}
+
+func sameRouting(a, b tss.Message) bool {
+	if a.IsBroadcast() != b.IsBroadcast() || len(a.GetTo()) != len(b.GetTo()) {
+		return false
+	}
+	for j := range a.GetTo() {
+		if a.GetTo()[j].Index != b.GetTo()[j].Index {
+			return false
+		}
+	}
+	return true
+}
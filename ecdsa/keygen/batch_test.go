@@ -0,0 +1,270 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/test"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+// runBatchKeygen generates keyCount keys with n parties and returns the per-party results or errors
+func runBatchKeygen(t *testing.T, n, threshold, keyCount, settled int, tamper func(tss.ParsedMessage) tss.ParsedMessage) (map[int][]LocalPartySaveData, map[int]*tss.Error) {
+	fixtures, pIDs, err := LoadKeygenTestFixtures(n)
+	if err != nil {
+		t.Skip("test fixtures required (LocalPreParams) to run a quick keygen")
+	}
+	p2pCtx := tss.NewPeerContext(pIDs)
+	parties := make([]*BatchLocalParty, 0, len(pIDs))
+
+	errCh := make(chan *tss.Error, len(pIDs)*len(pIDs))
+	outCh := make(chan tss.Message, len(pIDs)*len(pIDs))
+	endCh := make(chan []LocalPartySaveData, len(pIDs))
+
+	for i := 0; i < len(pIDs); i++ {
+		params := tss.NewParameters(tss.S256(), p2pCtx, pIDs[i], len(pIDs), threshold)
+		params.SetSessionNonce(big.NewInt(9))
+		P := NewBatchLocalParty(params, keyCount, outCh, endCh, fixtures[i].LocalPreParams).(*BatchLocalParty)
+		parties = append(parties, P)
+		go func(P *BatchLocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	results := make(map[int][]LocalPartySaveData, len(pIDs))
+	failed := make(map[int]*tss.Error, len(pIDs))
+	for len(results)+len(failed) < settled {
+		select {
+		case err := <-errCh:
+			if _, ok := failed[err.Victim().Index]; !ok {
+				failed[err.Victim().Index] = err
+			}
+		case msg := <-outCh:
+			batch, ok := msg.(tss.ParsedMessage).Content().(*KGBatchMessage)
+			if assert.True(t, ok, "a batch party must only send batch messages") {
+				assert.Len(t, batch.Items, keyCount)
+			}
+			msg = tamper(msg.(tss.ParsedMessage))
+			dest := msg.GetTo()
+			if dest == nil {
+				for _, P := range parties {
+					if P.PartyID().Index == msg.GetFrom().Index {
+						continue
+					}
+					go test.SharedPartyUpdater(P, msg, errCh)
+				}
+			} else {
+				go test.SharedPartyUpdater(parties[dest[0].Index], msg, errCh)
+			}
+		case saves := <-endCh:
+			// keyed by arrival order; the ShareIDs tell the parties apart
+			results[len(results)] = saves
+		}
+	}
+	return results, failed
+}
+
+func TestE2EBatchKeygen(t *testing.T) {
+	setUp("info")
+	const keyCount = 3
+	keep := func(msg tss.ParsedMessage) tss.ParsedMessage { return msg }
+	results, failed := runBatchKeygen(t, 3, 1, keyCount, 3, keep)
+	if !assert.Empty(t, failed) || !assert.Len(t, results, 3) {
+		return
+	}
+	for _, saves := range results {
+		if !assert.Len(t, saves, keyCount) {
+			return
+		}
+		for k, save := range saves {
+			assert.True(t, save.ECDSAPub.Equals(results[0][k].ECDSAPub), "every party must output the same key %d", k)
+			assert.Equal(t, 0, save.PaillierSK.N.Cmp(saves[0].PaillierSK.N), "the keys share the Paillier key")
+			assert.Equal(t, 0, save.NTildei.Cmp(saves[0].NTildei), "the keys share NTilde")
+			i := 0
+			for j, id := range save.Ks {
+				if id.Cmp(save.ShareID) == 0 {
+					i = j
+				}
+			}
+			BigXi := crypto.ScalarBaseMult(tss.S256(), save.Xi)
+			assert.True(t, BigXi.Equals(save.BigXj[i]))
+			for j := 0; j < k; j++ {
+				assert.False(t, save.ECDSAPub.Equals(saves[j].ECDSAPub), "the keys must be independent")
+			}
+		}
+	}
+}
+
+func TestE2EBatchKeygenBadFollowerCommitmentIsBlamed(t *testing.T) {
+	setUp("info")
+	const odd, oddKey = 2, 1
+	tamper := func(msg tss.ParsedMessage) tss.ParsedMessage {
+		batch, ok := msg.Content().(*KGBatchMessage)
+		if !ok || msg.GetFrom().Index != odd {
+			return msg
+		}
+		item, err := tss.ParseWireMessage(batch.Items[oddKey], msg.GetFrom(), msg.IsBroadcast())
+		if err != nil {
+			return msg
+		}
+		r1msg, ok := item.Content().(*KGRound1Message)
+		if !ok {
+			return msg
+		}
+		r1msg.Commitment = big.NewInt(1).Bytes()
+		bz, _, err := newFollowerMessage(msg.GetFrom(), nil, r1msg).WireBytes()
+		assert.NoError(t, err)
+		items := append([][]byte(nil), batch.Items...)
+		items[oddKey] = bz
+		return NewKGBatchMessage(msg.GetTo(), msg.GetFrom(), items)
+	}
+	// the tampering party sees nothing wrong and waits for the others
+	_, failed := runBatchKeygen(t, 3, 1, 2, 2, tamper)
+	if !assert.Len(t, failed, 2) {
+		return
+	}
+	for _, err := range failed {
+		assert.Equal(t, 3, err.Round())
+		var itemErr *BatchItemError
+		if assert.True(t, errors.As(err, &itemErr)) {
+			assert.Equal(t, oddKey, itemErr.Index)
+		}
+		if assert.Len(t, err.Culprits(), 1) {
+			assert.Equal(t, odd, err.Culprits()[0].Index)
+		}
+	}
+}
+
+func TestE2EBatchKeygenComplaintInOneKeyBlamesDealer(t *testing.T) {
+	setUp("info")
+	const odd, victim, oddKey = 0, 1, 1
+	// P[odd] deals a bad share to P[victim] for one key only and fails to justify it, so only that key has a
+	// complaint phase with something to send
+	tamper := func(msg tss.ParsedMessage) tss.ParsedMessage {
+		batch, ok := msg.Content().(*KGBatchMessage)
+		if !ok || msg.GetFrom().Index != odd || len(batch.Items[oddKey]) == 0 {
+			return msg
+		}
+		item, err := tss.ParseWireMessage(batch.Items[oddKey], msg.GetFrom(), msg.IsBroadcast())
+		if err != nil {
+			return msg
+		}
+		var bad tss.MessageContent
+		switch content := item.Content().(type) {
+		case *KGRound2Message1:
+			if msg.GetTo()[0].Index != victim {
+				return msg
+			}
+			bad = &KGRound2Message1{Share: new(big.Int).Add(content.UnmarshalShare(), big.NewInt(1)).Bytes(), SubShares: content.SubShares}
+		case *KGRound4Message:
+			assert.Empty(t, batch.Items[0], "P[odd] was not accused for the first key")
+			shares := append([][]byte(nil), content.Shares...)
+			shares[0] = new(big.Int).Add(new(big.Int).SetBytes(shares[0]), big.NewInt(1)).Bytes()
+			bad = &KGRound4Message{Complainers: content.Complainers, Shares: shares}
+		default:
+			return msg
+		}
+		bz, _, err := newFollowerMessage(msg.GetFrom(), msg.GetTo(), bad).WireBytes()
+		assert.NoError(t, err)
+		items := append([][]byte(nil), batch.Items...)
+		items[oddKey] = bz
+		return NewKGBatchMessage(msg.GetTo(), msg.GetFrom(), items)
+	}
+	_, failed := runBatchKeygen(t, 3, 1, 2, 2, tamper)
+	if !assert.Len(t, failed, 2) {
+		return
+	}
+	for _, err := range failed {
+		assert.Equal(t, 5, err.Round())
+		var itemErr *BatchItemError
+		if assert.True(t, errors.As(err, &itemErr)) {
+			assert.Equal(t, oddKey, itemErr.Index)
+		}
+		if assert.Len(t, err.Culprits(), 1) {
+			assert.Equal(t, odd, err.Culprits()[0].Index)
+		}
+	}
+}
+
+func TestBatchStoreMessageRejectsWrongItemCount(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(2)
+	params := tss.NewParameters(tss.S256(), tss.NewPeerContext(pIDs), pIDs[0], len(pIDs), 1)
+	P := NewBatchLocalParty(params, 2, nil, nil).(*BatchLocalParty)
+
+	r1, _, err := newFollowerMessage(pIDs[1], nil, &KGRound1Message{Commitment: []byte{1}}).WireBytes()
+	assert.NoError(t, err)
+
+	ok, tssErr := P.StoreMessage(NewKGBatchMessage(nil, pIDs[1], [][]byte{r1}))
+	assert.False(t, ok)
+	if assert.NotNil(t, tssErr) {
+		assert.Equal(t, []*tss.PartyID{pIDs[1]}, tssErr.Culprits())
+	}
+
+	assert.Panics(t, func() {
+		NewBatchLocalParty(params, 0, nil, nil)
+	})
+}
+
+func TestBatchStoreMessageRequiresLeaderItem(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(2)
+	params := tss.NewParameters(tss.S256(), tss.NewPeerContext(pIDs), pIDs[0], len(pIDs), 1)
+	P := NewBatchLocalParty(params, 2, nil, nil).(*BatchLocalParty)
+
+	// a further key's round 1 item without the first key's would carry an unproven Paillier N and NTilde
+	forged, _, err := newFollowerMessage(pIDs[1], nil, &KGRound1Message{
+		Commitment: []byte{1}, PaillierN: []byte{2}, NTilde: []byte{3}, H1: []byte{4}, H2: []byte{5},
+	}).WireBytes()
+	assert.NoError(t, err)
+	ok, tssErr := P.StoreMessage(NewKGBatchMessage(nil, pIDs[1], [][]byte{nil, forged}))
+	assert.False(t, ok)
+	if assert.NotNil(t, tssErr) {
+		assert.Equal(t, []*tss.PartyID{pIDs[1]}, tssErr.Culprits())
+	}
+	assert.Nil(t, P.items[1].temp.kgRound1Messages[1], "the forged item must not be stored")
+
+	ok, tssErr = P.StoreMessage(NewKGBatchMessage(nil, pIDs[1], [][]byte{nil, nil}))
+	assert.False(t, ok)
+	assert.NotNil(t, tssErr)
+
+	// a dealer accused for the second key only justifies it alone
+	r4, _, err := NewKGRound4Message(pIDs[1], []int{0}, []*big.Int{big.NewInt(1)}).WireBytes()
+	assert.NoError(t, err)
+	ok, tssErr = P.StoreMessage(NewKGBatchMessage(nil, pIDs[1], [][]byte{nil, r4}))
+	assert.True(t, ok)
+	assert.Nil(t, tssErr)
+}
+
+func TestBatchFollowerContentRoundTrip(t *testing.T) {
+	leader := &KGRound1Message{Commitment: []byte{1}, PaillierN: []byte{2}, NTilde: []byte{3}, H1: []byte{4}, H2: []byte{5}}
+	follower := &KGRound1Message{Commitment: []byte{6}, PaillierN: []byte{7}, NTilde: []byte{8}, H1: []byte{9}, H2: []byte{10}}
+
+	compact := compactFollowerContent(follower).(*KGRound1Message)
+	assert.Equal(t, []byte{6}, compact.Commitment)
+	assert.Empty(t, compact.PaillierN)
+	assert.Empty(t, compact.NTilde)
+
+	// a follower cannot substitute its own Paillier or NTilde keys for the leader's
+	expanded, err := expandFollowerContent(follower, leader)
+	assert.NoError(t, err)
+	r1msg := expanded.(*KGRound1Message)
+	assert.Equal(t, []byte{6}, r1msg.Commitment)
+	assert.Equal(t, leader.PaillierN, r1msg.PaillierN)
+	assert.Equal(t, leader.NTilde, r1msg.NTilde)
+	assert.Equal(t, leader.H1, r1msg.H1)
+	assert.Equal(t, leader.H2, r1msg.H2)
+
+	_, err = expandFollowerContent(&KGRound5Message{}, leader)
+	assert.Error(t, err)
+}
@@ -0,0 +1,185 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+const (
+	defaultAttemptTimeout   = 10 * time.Minute
+	defaultMaxAttempts      = 5
+	defaultPreParamsTimeout = 5 * time.Minute // same as the tss.Parameters safe prime default
+)
+
+type (
+	// Coordinator runs keygen for the local party and, when an attempt fails with culprits or times out, re-runs it
+	// with a fresh session over the remaining parties. Every party runs its own Coordinator with the same settings;
+	// they stay in step as long as they observe the same culprits, which holds for the publicly attributable faults
+	// keygen reports (e.g. a dealer that fails the complaint phase).
+	Coordinator struct {
+		EC        elliptic.Curve
+		Parties   tss.SortedPartyIDs // all n parties of the first attempt
+		PartyID   *tss.PartyID       // the local party; must be one of Parties
+		Threshold int
+		// MaxExcluded is the most parties that may be excluded before the coordinator gives up. The zero value allows
+		// n-(t+1), as many as leave enough parties for the threshold; a negative value allows none.
+		MaxExcluded int
+		// MaxAttempts bounds the number of attempts (default 5)
+		MaxAttempts int
+		// SessionID is agreed out-of-band and must be at least 16 bytes; each attempt derives its own nonce from it
+		SessionID []byte
+		// Timeout is how long an attempt may wait without receiving a message (default 10 minutes)
+		Timeout      time.Duration
+		NewTransport tss.TransportFactory
+		// PreParams are optional; they are generated once and reused by every attempt when nil
+		PreParams *LocalPreParams
+		// Logger is optional; it is set on the parameters of every attempt and logs the attempts that failed
+		Logger tss.Logger
+
+		runAttempt func(attempt *tss.Attempt) (*LocalPartySaveData, *tss.Error)
+	}
+
+	// Report describes every attempt made by a Coordinator and who was excluded along the way.
+	Report struct {
+		Attempts int
+		Parties  tss.SortedPartyIDs // the parties of the last attempt
+		Excluded []tss.Exclusion
+		Err      *tss.Error // the error of the last attempt, if it failed
+	}
+)
+
+// Run performs keygen until an attempt succeeds, MaxAttempts is reached, an attempt fails without excluding a party
+// that was not excluded before, or too many parties have been excluded.
+// The returned Report is always non-nil.
+func (c *Coordinator) Run() (*LocalPartySaveData, *Report, error) {
+	report := &Report{Parties: c.Parties}
+	if len(c.SessionID) < 16 {
+		return nil, report, errors.New("keygen coordinator: session ID must be at least 16 bytes")
+	}
+	if c.Parties.FindByKey(c.PartyID.KeyInt()) == nil {
+		return nil, report, errors.New("keygen coordinator: the local party is not one of Parties")
+	}
+	if c.runAttempt == nil {
+		c.runAttempt = c.runLocalParty
+	}
+	if c.PreParams == nil {
+		preParams, err := GeneratePreParams(defaultPreParamsTimeout)
+		if err != nil {
+			return nil, report, err
+		}
+		c.PreParams = preParams
+	}
+	maxExcluded := c.MaxExcluded
+	switch {
+	case maxExcluded == 0:
+		maxExcluded = len(c.Parties) - (c.Threshold + 1)
+	case maxExcluded < 0:
+		maxExcluded = 0
+	}
+	maxAttempts := c.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+	parties := c.Parties
+	for number := 1; number <= maxAttempts; number++ {
+		attempt := tss.NewAttempt(c.SessionID, number, parties)
+		report.Attempts, report.Parties = number, attempt.Parties
+		save, err := c.runAttempt(attempt)
+		if err == nil {
+			report.Err = nil
+			return save, report, nil
+		}
+		report.Err = err
+		c.logger().Warn("keygen attempt failed", "attempt", number, "err", err.Error())
+		culprits := err.Culprits()
+		if len(culprits) == 0 {
+			return nil, report, fmt.Errorf("keygen attempt %d failed without culprits: %w", number, err)
+		}
+		excluded := len(report.Excluded)
+		for _, culprit := range culprits {
+			if culprit.KeyInt().Cmp(c.PartyID.KeyInt()) == 0 {
+				return nil, report, fmt.Errorf("keygen attempt %d blamed the local party: %w", number, err)
+			}
+			original := c.Parties.FindByKey(culprit.KeyInt())
+			if original == nil || parties.FindByKey(culprit.KeyInt()) == nil {
+				continue
+			}
+			report.Excluded = append(report.Excluded, tss.Exclusion{
+				Party:   original,
+				Attempt: number,
+				Round:   err.Round(),
+				Reason:  err.Cause().Error(),
+			})
+			parties = parties.Exclude(original)
+		}
+		// the next attempt would run over the same parties and most likely fail the same way
+		if len(report.Excluded) == excluded {
+			return nil, report, fmt.Errorf("keygen attempt %d failed without blaming a remaining party: %w", number, err)
+		}
+		if maxExcluded < len(report.Excluded) {
+			return nil, report, fmt.Errorf("keygen gave up after excluding %d parties (max %d): %w", len(report.Excluded), maxExcluded, err)
+		}
+		if len(parties) <= c.Threshold {
+			return nil, report, fmt.Errorf("keygen gave up: %d parties remain for threshold %d: %w", len(parties), c.Threshold, err)
+		}
+	}
+	return nil, report, fmt.Errorf("keygen gave up after %d attempts: %w", maxAttempts, report.Err)
+}
+
+func (c *Coordinator) runLocalParty(attempt *tss.Attempt) (*LocalPartySaveData, *tss.Error) {
+	self := attempt.Parties.FindByKey(c.PartyID.KeyInt())
+	params := tss.NewParameters(c.EC, tss.NewPeerContext(attempt.Parties), self, len(attempt.Parties), c.Threshold)
+	params.SetSessionNonce(attempt.SessionNonce)
+	if c.Logger != nil {
+		params.SetLogger(c.Logger)
+	}
+
+	transport, err := c.NewTransport(attempt)
+	if err != nil {
+		return nil, tss.NewError(err, TaskName, 0, self)
+	}
+	defer transport.Close()
+
+	out := make(chan tss.Message, len(attempt.Parties))
+	end := make(chan LocalPartySaveData, 1)
+	party := NewLocalParty(params, out, end, *c.PreParams)
+
+	var save LocalPartySaveData
+	done := make(chan struct{})
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case save = <-end:
+			close(done)
+		case <-stop:
+		}
+	}()
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = defaultAttemptTimeout
+	}
+	if err := tss.RunParty(party, out, done, transport, timeout); err != nil {
+		return nil, err
+	}
+	return &save, nil
+}
+
+// logger returns the Logger, or tss.DefaultLogger, with the party and task fields
+func (c *Coordinator) logger() tss.Logger {
+	logger := c.Logger
+	if logger == nil {
+		logger = tss.DefaultLogger
+	}
+	return logger.With(tss.LogKeyParty, c.PartyID.String(), tss.LogKeyTask, TaskName)
+}
@@ -0,0 +1,233 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/test"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+var testCoordinatorSessionID = []byte("keygen-coordinator-test-session")
+
+func TestCoordinatorExcludesCulpritsAndTimeouts(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(5)
+	nonces := make(map[string]struct{})
+	c := &Coordinator{
+		Parties:     pIDs,
+		PartyID:     pIDs[0],
+		Threshold:   2,
+		MaxExcluded: 2,
+		SessionID:   testCoordinatorSessionID,
+		PreParams:   &LocalPreParams{},
+		runAttempt: func(attempt *tss.Attempt) (*LocalPartySaveData, *tss.Error) {
+			nonces[attempt.SessionNonce.String()] = struct{}{}
+			self := attempt.Parties.FindByKey(pIDs[0].KeyInt())
+			switch attempt.Number {
+			case 1:
+				assert.Len(t, attempt.Parties, 5)
+				return nil, tss.NewError(errors.New("vss verify failed"), TaskName, 5, self, attempt.Parties.FindByKey(pIDs[3].KeyInt()))
+			case 2:
+				assert.Len(t, attempt.Parties, 4)
+				assert.Nil(t, attempt.Parties.FindByKey(pIDs[3].KeyInt()))
+				return nil, tss.NewError(tss.ErrAttemptTimeout, TaskName, 1, self, attempt.Parties.FindByKey(pIDs[1].KeyInt()))
+			default:
+				assert.Len(t, attempt.Parties, 3)
+				return &LocalPartySaveData{}, nil
+			}
+		},
+	}
+	save, report, err := c.Run()
+	assert.NoError(t, err)
+	assert.NotNil(t, save)
+	assert.Equal(t, 3, report.Attempts)
+	assert.Len(t, nonces, 3, "every attempt must use a fresh session nonce")
+	if assert.Len(t, report.Excluded, 2) {
+		assert.Equal(t, pIDs[3], report.Excluded[0].Party)
+		assert.Equal(t, 1, report.Excluded[0].Attempt)
+		assert.Equal(t, 5, report.Excluded[0].Round)
+		assert.Equal(t, pIDs[1], report.Excluded[1].Party)
+		assert.Equal(t, tss.ErrAttemptTimeout.Error(), report.Excluded[1].Reason)
+	}
+	assert.Equal(t, 0, pIDs[0].Index, "the caller's party IDs must not be re-indexed")
+	assert.Equal(t, 4, pIDs[4].Index, "the caller's party IDs must not be re-indexed")
+}
+
+func TestCoordinatorGivesUp(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(4)
+	blame := func(culprits ...int) func(*tss.Attempt) (*LocalPartySaveData, *tss.Error) {
+		return func(attempt *tss.Attempt) (*LocalPartySaveData, *tss.Error) {
+			ids := make([]*tss.PartyID, 0, len(culprits))
+			for _, j := range culprits {
+				ids = append(ids, attempt.Parties.FindByKey(pIDs[j].KeyInt()))
+			}
+			return nil, tss.NewError(errors.New("bad"), TaskName, 2, attempt.Parties.FindByKey(pIDs[0].KeyInt()), ids...)
+		}
+	}
+	newCoordinator := func(maxExcluded int, run func(*tss.Attempt) (*LocalPartySaveData, *tss.Error)) *Coordinator {
+		return &Coordinator{
+			Parties:     pIDs,
+			PartyID:     pIDs[0],
+			Threshold:   2,
+			MaxExcluded: maxExcluded,
+			SessionID:   testCoordinatorSessionID,
+			PreParams:   &LocalPreParams{},
+			runAttempt:  run,
+		}
+	}
+
+	// too many culprits for MaxExcluded
+	_, report, err := newCoordinator(1, blame(1, 2)).Run()
+	assert.Error(t, err)
+	assert.Equal(t, 1, report.Attempts)
+	assert.Len(t, report.Excluded, 2)
+
+	// not enough parties left for the threshold
+	_, report, err = newCoordinator(3, blame(1, 2)).Run()
+	assert.Error(t, err)
+	assert.Len(t, report.Parties, 4)
+
+	// the local party was blamed
+	_, _, err = newCoordinator(3, blame(0)).Run()
+	assert.Error(t, err)
+
+	// an error without culprits cannot be retried
+	_, report, err = newCoordinator(3, blame()).Run()
+	assert.Error(t, err)
+	assert.Empty(t, report.Excluded)
+
+	// a negative MaxExcluded allows no exclusion
+	_, report, err = newCoordinator(-1, blame(1)).Run()
+	assert.Error(t, err)
+	assert.Equal(t, 1, report.Attempts)
+
+	// culprits that are not among the parties of the attempt leave nothing to exclude, so nothing to retry
+	outsider := tss.NewPartyID("outsider", "outsider", big.NewInt(1))
+	_, report, err = newCoordinator(3, func(attempt *tss.Attempt) (*LocalPartySaveData, *tss.Error) {
+		return nil, tss.NewError(errors.New("bad"), TaskName, 2, attempt.Parties.FindByKey(pIDs[0].KeyInt()), outsider)
+	}).Run()
+	assert.Error(t, err)
+	assert.Equal(t, 1, report.Attempts)
+	assert.Empty(t, report.Excluded)
+}
+
+func TestCoordinatorMaxAttempts(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(8)
+	newCoordinator := func(maxAttempts int) *Coordinator {
+		return &Coordinator{
+			Parties:     pIDs,
+			PartyID:     pIDs[0],
+			Threshold:   1,
+			MaxAttempts: maxAttempts,
+			SessionID:   testCoordinatorSessionID,
+			PreParams:   &LocalPreParams{},
+			runAttempt: func(attempt *tss.Attempt) (*LocalPartySaveData, *tss.Error) {
+				// each attempt blames a new party, which MaxExcluded alone would allow 6 times
+				self := attempt.Parties.FindByKey(pIDs[0].KeyInt())
+				return nil, tss.NewError(errors.New("bad"), TaskName, 2, self, attempt.Parties[len(attempt.Parties)-1])
+			},
+		}
+	}
+
+	_, report, err := newCoordinator(2).Run()
+	assert.Error(t, err)
+	assert.Equal(t, 2, report.Attempts)
+	assert.Len(t, report.Excluded, 2)
+	assert.NotNil(t, report.Err)
+
+	// the zero value allows 5 attempts
+	_, report, err = newCoordinator(0).Run()
+	assert.Error(t, err)
+	assert.Equal(t, 5, report.Attempts)
+	assert.Len(t, report.Excluded, 5)
+}
+
+func TestCoordinatorDefaultMaxExcluded(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(5)
+	c := &Coordinator{
+		Parties:   pIDs,
+		PartyID:   pIDs[0],
+		Threshold: 2,
+		SessionID: testCoordinatorSessionID,
+		PreParams: &LocalPreParams{},
+		runAttempt: func(attempt *tss.Attempt) (*LocalPartySaveData, *tss.Error) {
+			// each attempt blames the last party but one, until only t+1 parties remain
+			if len(attempt.Parties) == 3 {
+				return &LocalPartySaveData{}, nil
+			}
+			self := attempt.Parties.FindByKey(pIDs[0].KeyInt())
+			return nil, tss.NewError(errors.New("bad"), TaskName, 2, self, attempt.Parties[len(attempt.Parties)-2])
+		},
+	}
+	// the zero value allows n-(t+1) exclusions
+	save, report, err := c.Run()
+	assert.NoError(t, err)
+	assert.NotNil(t, save)
+	assert.Equal(t, 3, report.Attempts)
+	assert.Len(t, report.Excluded, 2)
+}
+
+// TestE2ECoordinatorExcludesOfflineParty runs three coordinators while a fourth party never comes online.
+// The first attempt times out blaming the silent party and the second attempt succeeds without it.
+func TestE2ECoordinatorExcludesOfflineParty(t *testing.T) {
+	setUp("info")
+
+	fixtures, pIDs, err := LoadKeygenTestFixtures(4)
+	if err != nil {
+		t.Skip("test fixtures required (LocalPreParams) to run a quick keygen")
+	}
+	offline := pIDs[3]
+	hub := test.NewHub()
+
+	type result struct {
+		save   *LocalPartySaveData
+		report *Report
+		err    error
+	}
+	results := make(chan result, 3)
+	for i := 0; i < 3; i++ {
+		c := &Coordinator{
+			EC:           tss.S256(),
+			Parties:      pIDs,
+			PartyID:      pIDs[i],
+			Threshold:    1,
+			MaxExcluded:  1,
+			SessionID:    testCoordinatorSessionID,
+			Timeout:      30 * time.Second,
+			NewTransport: hub.Transport(pIDs[i]),
+			PreParams:    &fixtures[i].LocalPreParams,
+		}
+		go func() {
+			save, report, err := c.Run()
+			results <- result{save, report, err}
+		}()
+	}
+	var pub *crypto.ECPoint
+	for i := 0; i < 3; i++ {
+		res := <-results
+		if !assert.NoError(t, res.err) {
+			continue
+		}
+		assert.Equal(t, 2, res.report.Attempts)
+		assert.Len(t, res.report.Parties, 3)
+		if assert.Len(t, res.report.Excluded, 1) {
+			assert.Equal(t, offline, res.report.Excluded[0].Party)
+		}
+		assert.Len(t, res.save.Ks, 3)
+		if pub == nil {
+			pub = res.save.ECDSAPub
+		}
+		assert.True(t, pub.Equals(res.save.ECDSAPub), "everyone should have the same public key")
+	}
+}
@@ -147,6 +147,8 @@ type KGRound2Message1 struct {
 	Share         []byte                        `protobuf:"bytes,1,opt,name=share,proto3" json:"share,omitempty"`
 	Facproof      *KGRound2Message1_FactorProof `protobuf:"bytes,2,opt,name=facproof,proto3" json:"facproof,omitempty"`
 	FacproofTilde *KGRound2Message1_FactorProof `protobuf:"bytes,3,opt,name=facproof_tilde,json=facproofTilde,proto3" json:"facproof_tilde,omitempty"`
+	// with weighted sharing, the shares for the recipient's further evaluation points
+	SubShares [][]byte `protobuf:"bytes,4,rep,name=sub_shares,json=subShares,proto3" json:"sub_shares,omitempty"`
 }
 
 func (x *KGRound2Message1) Reset() {
@@ -202,6 +204,13 @@ func (x *KGRound2Message1) GetFacproofTilde() *KGRound2Message1_FactorProof {
 	return nil
 }
 
+func (x *KGRound2Message1) GetSubShares() [][]byte {
+	if x != nil {
+		return x.SubShares
+	}
+	return nil
+}
+
 // Represents a BROADCAST message sent to each party during Round 2 of the ECDSA TSS keygen protocol.
 type KGRound2Message2 struct {
 	state         protoimpl.MessageState
@@ -251,12 +260,13 @@ func (x *KGRound2Message2) GetDeCommitment() [][]byte {
 }
 
 // Represents a BROADCAST message sent to each party during Round 3 of the ECDSA TSS keygen protocol.
+// Lists the indexes of the dealers whose Round 2 share failed VSS verification; it is empty on the honest path.
 type KGRound3Message struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	PaillierProof [][]byte `protobuf:"bytes,1,rep,name=paillier_proof,json=paillierProof,proto3" json:"paillier_proof,omitempty"`
+	Accused []uint32 `protobuf:"varint,1,rep,packed,name=accused,proto3" json:"accused,omitempty"`
 }
 
 func (x *KGRound3Message) Reset() {
@@ -291,13 +301,170 @@ func (*KGRound3Message) Descriptor() ([]byte, []int) {
 	return file_protob_ecdsa_keygen_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *KGRound3Message) GetPaillierProof() [][]byte {
+func (x *KGRound3Message) GetAccused() []uint32 {
+	if x != nil {
+		return x.Accused
+	}
+	return nil
+}
+
+// Represents a BROADCAST message sent during Round 4 of the ECDSA TSS keygen protocol.
+// Sent only by an accused dealer; publicly reveals the disputed share for each complainer.
+// With weighted sharing, it reveals all of a complainer's shares, one after the other.
+type KGRound4Message struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Complainers []uint32 `protobuf:"varint,1,rep,packed,name=complainers,proto3" json:"complainers,omitempty"`
+	Shares      [][]byte `protobuf:"bytes,2,rep,name=shares,proto3" json:"shares,omitempty"`
+}
+
+func (x *KGRound4Message) Reset() {
+	*x = KGRound4Message{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protob_ecdsa_keygen_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KGRound4Message) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KGRound4Message) ProtoMessage() {}
+
+func (x *KGRound4Message) ProtoReflect() protoreflect.Message {
+	mi := &file_protob_ecdsa_keygen_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KGRound4Message.ProtoReflect.Descriptor instead.
+func (*KGRound4Message) Descriptor() ([]byte, []int) {
+	return file_protob_ecdsa_keygen_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *KGRound4Message) GetComplainers() []uint32 {
+	if x != nil {
+		return x.Complainers
+	}
+	return nil
+}
+
+func (x *KGRound4Message) GetShares() [][]byte {
+	if x != nil {
+		return x.Shares
+	}
+	return nil
+}
+
+// Represents a BROADCAST message sent to each party during Round 5 of the ECDSA TSS keygen protocol.
+type KGRound5Message struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PaillierProof [][]byte `protobuf:"bytes,1,rep,name=paillier_proof,json=paillierProof,proto3" json:"paillier_proof,omitempty"`
+}
+
+func (x *KGRound5Message) Reset() {
+	*x = KGRound5Message{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protob_ecdsa_keygen_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KGRound5Message) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KGRound5Message) ProtoMessage() {}
+
+func (x *KGRound5Message) ProtoReflect() protoreflect.Message {
+	mi := &file_protob_ecdsa_keygen_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KGRound5Message.ProtoReflect.Descriptor instead.
+func (*KGRound5Message) Descriptor() ([]byte, []int) {
+	return file_protob_ecdsa_keygen_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *KGRound5Message) GetPaillierProof() [][]byte {
 	if x != nil {
 		return x.PaillierProof
 	}
 	return nil
 }
 
+// Represents a P2P or BROADCAST message of a batch keygen ceremony. The first item is the wire encoding of the
+// round message for the first key; the items for the further keys omit the Paillier and NTilde proofs, which
+// are the same for every key. An empty item carries nothing for its key, e.g. in the complaint phase of a key
+// in which the sender was not accused.
+type KGBatchMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Items [][]byte `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+}
+
+func (x *KGBatchMessage) Reset() {
+	*x = KGBatchMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protob_ecdsa_keygen_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KGBatchMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KGBatchMessage) ProtoMessage() {}
+
+func (x *KGBatchMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_protob_ecdsa_keygen_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KGBatchMessage.ProtoReflect.Descriptor instead.
+func (*KGBatchMessage) Descriptor() ([]byte, []int) {
+	return file_protob_ecdsa_keygen_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *KGBatchMessage) GetItems() [][]byte {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
 type KGRound1Message_DLNProof struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -310,7 +477,7 @@ type KGRound1Message_DLNProof struct {
 func (x *KGRound1Message_DLNProof) Reset() {
 	*x = KGRound1Message_DLNProof{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protob_ecdsa_keygen_proto_msgTypes[4]
+		mi := &file_protob_ecdsa_keygen_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -323,7 +490,7 @@ func (x *KGRound1Message_DLNProof) String() string {
 func (*KGRound1Message_DLNProof) ProtoMessage() {}
 
 func (x *KGRound1Message_DLNProof) ProtoReflect() protoreflect.Message {
-	mi := &file_protob_ecdsa_keygen_proto_msgTypes[4]
+	mi := &file_protob_ecdsa_keygen_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -368,7 +535,7 @@ type KGRound1Message_ModProof struct {
 func (x *KGRound1Message_ModProof) Reset() {
 	*x = KGRound1Message_ModProof{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protob_ecdsa_keygen_proto_msgTypes[5]
+		mi := &file_protob_ecdsa_keygen_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -381,7 +548,7 @@ func (x *KGRound1Message_ModProof) String() string {
 func (*KGRound1Message_ModProof) ProtoMessage() {}
 
 func (x *KGRound1Message_ModProof) ProtoReflect() protoreflect.Message {
-	mi := &file_protob_ecdsa_keygen_proto_msgTypes[5]
+	mi := &file_protob_ecdsa_keygen_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -453,7 +620,7 @@ type KGRound2Message1_FactorProof struct {
 func (x *KGRound2Message1_FactorProof) Reset() {
 	*x = KGRound2Message1_FactorProof{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protob_ecdsa_keygen_proto_msgTypes[6]
+		mi := &file_protob_ecdsa_keygen_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -466,7 +633,7 @@ func (x *KGRound2Message1_FactorProof) String() string {
 func (*KGRound2Message1_FactorProof) ProtoMessage() {}
 
 func (x *KGRound2Message1_FactorProof) ProtoReflect() protoreflect.Message {
-	mi := &file_protob_ecdsa_keygen_proto_msgTypes[6]
+	mi := &file_protob_ecdsa_keygen_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -605,7 +772,7 @@ var file_protob_ecdsa_keygen_proto_rawDesc = []byte{
 	0x20, 0x03, 0x28, 0x08, 0x52, 0x01, 0x61, 0x12, 0x0c, 0x0a, 0x01, 0x62, 0x18, 0x04, 0x20, 0x03,
 	0x28, 0x08, 0x52, 0x01, 0x62, 0x12, 0x0c, 0x0a, 0x01, 0x7a, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0c,
 	0x52, 0x01, 0x7a, 0x4a, 0x04, 0x08, 0x06, 0x10, 0x07, 0x4a, 0x04, 0x08, 0x07, 0x10, 0x08, 0x22,
-	0x9b, 0x03, 0x0a, 0x10, 0x4b, 0x47, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x32, 0x4d, 0x65, 0x73, 0x73,
+	0xba, 0x03, 0x0a, 0x10, 0x4b, 0x47, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x32, 0x4d, 0x65, 0x73, 0x73,
 	0x61, 0x67, 0x65, 0x31, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x68, 0x61, 0x72, 0x65, 0x18, 0x01, 0x20,
 	0x01, 0x28, 0x0c, 0x52, 0x05, 0x73, 0x68, 0x61, 0x72, 0x65, 0x12, 0x55, 0x0a, 0x08, 0x66, 0x61,
 	0x63, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x39, 0x2e, 0x62,
@@ -619,27 +786,39 @@ var file_protob_ecdsa_keygen_proto_rawDesc = []byte{
 	0x2e, 0x6b, 0x65, 0x79, 0x67, 0x65, 0x6e, 0x2e, 0x4b, 0x47, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x32,
 	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x31, 0x2e, 0x46, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x50,
 	0x72, 0x6f, 0x6f, 0x66, 0x52, 0x0d, 0x66, 0x61, 0x63, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x54, 0x69,
-	0x6c, 0x64, 0x65, 0x1a, 0xb7, 0x01, 0x0a, 0x0b, 0x46, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x50, 0x72,
-	0x6f, 0x6f, 0x66, 0x12, 0x0c, 0x0a, 0x01, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x01,
-	0x70, 0x12, 0x0c, 0x0a, 0x01, 0x71, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x01, 0x71, 0x12,
-	0x0c, 0x0a, 0x01, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x01, 0x61, 0x12, 0x0c, 0x0a,
-	0x01, 0x62, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x01, 0x62, 0x12, 0x0c, 0x0a, 0x01, 0x74,
-	0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x01, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x69, 0x67,
-	0x6d, 0x61, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x73, 0x69, 0x67, 0x6d, 0x61, 0x12,
-	0x0e, 0x0a, 0x02, 0x7a, 0x31, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x02, 0x7a, 0x31, 0x12,
-	0x0e, 0x0a, 0x02, 0x7a, 0x32, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x02, 0x7a, 0x32, 0x12,
-	0x0e, 0x0a, 0x02, 0x77, 0x31, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x02, 0x77, 0x31, 0x12,
-	0x0e, 0x0a, 0x02, 0x77, 0x32, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x02, 0x77, 0x32, 0x12,
-	0x0c, 0x0a, 0x01, 0x76, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x01, 0x76, 0x22, 0x37, 0x0a,
-	0x10, 0x4b, 0x47, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x32, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
-	0x32, 0x12, 0x23, 0x0a, 0x0d, 0x64, 0x65, 0x5f, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x6d, 0x65,
-	0x6e, 0x74, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0c, 0x64, 0x65, 0x43, 0x6f, 0x6d, 0x6d,
-	0x69, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x22, 0x38, 0x0a, 0x0f, 0x4b, 0x47, 0x52, 0x6f, 0x75, 0x6e,
-	0x64, 0x33, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x70, 0x61, 0x69,
-	0x6c, 0x6c, 0x69, 0x65, 0x72, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x01, 0x20, 0x03, 0x28,
-	0x0c, 0x52, 0x0d, 0x70, 0x61, 0x69, 0x6c, 0x6c, 0x69, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x6f, 0x66,
-	0x42, 0x0e, 0x5a, 0x0c, 0x65, 0x63, 0x64, 0x73, 0x61, 0x2f, 0x6b, 0x65, 0x79, 0x67, 0x65, 0x6e,
-	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x6c, 0x64, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x75, 0x62, 0x5f, 0x73, 0x68, 0x61, 0x72, 0x65,
+	0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x75, 0x62, 0x53, 0x68, 0x61, 0x72,
+	0x65, 0x73, 0x1a, 0xb7, 0x01, 0x0a, 0x0b, 0x46, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x50, 0x72, 0x6f,
+	0x6f, 0x66, 0x12, 0x0c, 0x0a, 0x01, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x01, 0x70,
+	0x12, 0x0c, 0x0a, 0x01, 0x71, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x01, 0x71, 0x12, 0x0c,
+	0x0a, 0x01, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x01, 0x61, 0x12, 0x0c, 0x0a, 0x01,
+	0x62, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x01, 0x62, 0x12, 0x0c, 0x0a, 0x01, 0x74, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x01, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x69, 0x67, 0x6d,
+	0x61, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x73, 0x69, 0x67, 0x6d, 0x61, 0x12, 0x0e,
+	0x0a, 0x02, 0x7a, 0x31, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x02, 0x7a, 0x31, 0x12, 0x0e,
+	0x0a, 0x02, 0x7a, 0x32, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x02, 0x7a, 0x32, 0x12, 0x0e,
+	0x0a, 0x02, 0x77, 0x31, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x02, 0x77, 0x31, 0x12, 0x0e,
+	0x0a, 0x02, 0x77, 0x32, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x02, 0x77, 0x32, 0x12, 0x0c,
+	0x0a, 0x01, 0x76, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x01, 0x76, 0x22, 0x37, 0x0a, 0x10,
+	0x4b, 0x47, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x32, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x32,
+	0x12, 0x23, 0x0a, 0x0d, 0x64, 0x65, 0x5f, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x6d, 0x65, 0x6e,
+	0x74, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0c, 0x64, 0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x69,
+	0x74, 0x6d, 0x65, 0x6e, 0x74, 0x22, 0x2b, 0x0a, 0x0f, 0x4b, 0x47, 0x52, 0x6f, 0x75, 0x6e, 0x64,
+	0x33, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x63, 0x63, 0x75,
+	0x73, 0x65, 0x64, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x07, 0x61, 0x63, 0x63, 0x75, 0x73,
+	0x65, 0x64, 0x22, 0x4b, 0x0a, 0x0f, 0x4b, 0x47, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x34, 0x4d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x61, 0x69,
+	0x6e, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x0b, 0x63, 0x6f, 0x6d, 0x70,
+	0x6c, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x68, 0x61, 0x72, 0x65,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x06, 0x73, 0x68, 0x61, 0x72, 0x65, 0x73, 0x22,
+	0x38, 0x0a, 0x0f, 0x4b, 0x47, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x35, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x70, 0x61, 0x69, 0x6c, 0x6c, 0x69, 0x65, 0x72, 0x5f, 0x70,
+	0x72, 0x6f, 0x6f, 0x66, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0d, 0x70, 0x61, 0x69, 0x6c,
+	0x6c, 0x69, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x22, 0x26, 0x0a, 0x0e, 0x4b, 0x47, 0x42,
+	0x61, 0x74, 0x63, 0x68, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x69,
+	0x74, 0x65, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d,
+	0x73, 0x42, 0x0e, 0x5a, 0x0c, 0x65, 0x63, 0x64, 0x73, 0x61, 0x2f, 0x6b, 0x65, 0x79, 0x67, 0x65,
+	0x6e, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -654,23 +833,26 @@ func file_protob_ecdsa_keygen_proto_rawDescGZIP() []byte {
 	return file_protob_ecdsa_keygen_proto_rawDescData
 }
 
-var file_protob_ecdsa_keygen_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_protob_ecdsa_keygen_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
 var file_protob_ecdsa_keygen_proto_goTypes = []interface{}{
 	(*KGRound1Message)(nil),              // 0: binance.tsslib.ecdsa.keygen.KGRound1Message
 	(*KGRound2Message1)(nil),             // 1: binance.tsslib.ecdsa.keygen.KGRound2Message1
 	(*KGRound2Message2)(nil),             // 2: binance.tsslib.ecdsa.keygen.KGRound2Message2
 	(*KGRound3Message)(nil),              // 3: binance.tsslib.ecdsa.keygen.KGRound3Message
-	(*KGRound1Message_DLNProof)(nil),     // 4: binance.tsslib.ecdsa.keygen.KGRound1Message.DLNProof
-	(*KGRound1Message_ModProof)(nil),     // 5: binance.tsslib.ecdsa.keygen.KGRound1Message.ModProof
-	(*KGRound2Message1_FactorProof)(nil), // 6: binance.tsslib.ecdsa.keygen.KGRound2Message1.FactorProof
+	(*KGRound4Message)(nil),              // 4: binance.tsslib.ecdsa.keygen.KGRound4Message
+	(*KGRound5Message)(nil),              // 5: binance.tsslib.ecdsa.keygen.KGRound5Message
+	(*KGBatchMessage)(nil),               // 6: binance.tsslib.ecdsa.keygen.KGBatchMessage
+	(*KGRound1Message_DLNProof)(nil),     // 7: binance.tsslib.ecdsa.keygen.KGRound1Message.DLNProof
+	(*KGRound1Message_ModProof)(nil),     // 8: binance.tsslib.ecdsa.keygen.KGRound1Message.ModProof
+	(*KGRound2Message1_FactorProof)(nil), // 9: binance.tsslib.ecdsa.keygen.KGRound2Message1.FactorProof
 }
 var file_protob_ecdsa_keygen_proto_depIdxs = []int32{
-	4, // 0: binance.tsslib.ecdsa.keygen.KGRound1Message.dlnproof_1:type_name -> binance.tsslib.ecdsa.keygen.KGRound1Message.DLNProof
-	4, // 1: binance.tsslib.ecdsa.keygen.KGRound1Message.dlnproof_2:type_name -> binance.tsslib.ecdsa.keygen.KGRound1Message.DLNProof
-	5, // 2: binance.tsslib.ecdsa.keygen.KGRound1Message.modproof:type_name -> binance.tsslib.ecdsa.keygen.KGRound1Message.ModProof
-	5, // 3: binance.tsslib.ecdsa.keygen.KGRound1Message.modproof_tilde:type_name -> binance.tsslib.ecdsa.keygen.KGRound1Message.ModProof
-	6, // 4: binance.tsslib.ecdsa.keygen.KGRound2Message1.facproof:type_name -> binance.tsslib.ecdsa.keygen.KGRound2Message1.FactorProof
-	6, // 5: binance.tsslib.ecdsa.keygen.KGRound2Message1.facproof_tilde:type_name -> binance.tsslib.ecdsa.keygen.KGRound2Message1.FactorProof
+	7, // 0: binance.tsslib.ecdsa.keygen.KGRound1Message.dlnproof_1:type_name -> binance.tsslib.ecdsa.keygen.KGRound1Message.DLNProof
+	7, // 1: binance.tsslib.ecdsa.keygen.KGRound1Message.dlnproof_2:type_name -> binance.tsslib.ecdsa.keygen.KGRound1Message.DLNProof
+	8, // 2: binance.tsslib.ecdsa.keygen.KGRound1Message.modproof:type_name -> binance.tsslib.ecdsa.keygen.KGRound1Message.ModProof
+	8, // 3: binance.tsslib.ecdsa.keygen.KGRound1Message.modproof_tilde:type_name -> binance.tsslib.ecdsa.keygen.KGRound1Message.ModProof
+	9, // 4: binance.tsslib.ecdsa.keygen.KGRound2Message1.facproof:type_name -> binance.tsslib.ecdsa.keygen.KGRound2Message1.FactorProof
+	9, // 5: binance.tsslib.ecdsa.keygen.KGRound2Message1.facproof_tilde:type_name -> binance.tsslib.ecdsa.keygen.KGRound2Message1.FactorProof
 	6, // [6:6] is the sub-list for method output_type
 	6, // [6:6] is the sub-list for method input_type
 	6, // [6:6] is the sub-list for extension type_name
@@ -733,7 +915,7 @@ func file_protob_ecdsa_keygen_proto_init() {
 			}
 		}
 		file_protob_ecdsa_keygen_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*KGRound1Message_DLNProof); i {
+			switch v := v.(*KGRound4Message); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -745,7 +927,7 @@ func file_protob_ecdsa_keygen_proto_init() {
 			}
 		}
 		file_protob_ecdsa_keygen_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*KGRound1Message_ModProof); i {
+			switch v := v.(*KGRound5Message); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -757,6 +939,42 @@ func file_protob_ecdsa_keygen_proto_init() {
 			}
 		}
 		file_protob_ecdsa_keygen_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KGBatchMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protob_ecdsa_keygen_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KGRound1Message_DLNProof); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protob_ecdsa_keygen_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KGRound1Message_ModProof); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protob_ecdsa_keygen_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*KGRound2Message1_FactorProof); i {
 			case 0:
 				return &v.state
@@ -775,7 +993,7 @@ func file_protob_ecdsa_keygen_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_protob_ecdsa_keygen_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   7,
+			NumMessages:   10,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
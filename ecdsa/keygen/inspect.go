@@ -0,0 +1,250 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"crypto/elliptic"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/crypto/vss"
+	"github.com/bnb-chain/tss-lib/ecdsa/encoding"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+// fingerprintLen is the number of bytes of a Paillier or NTilde fingerprint
+const fingerprintLen = 8
+
+type (
+	// PublicInfo holds the public fields of a LocalPartySaveData. Apart from ShareID, they are the same in the save
+	// data of every party of a key. It never holds Xi, the Paillier secret key or the NTilde primes.
+	PublicInfo struct {
+		Curve      tss.CurveName
+		PartyCount int
+		// Threshold is derived from BigXj: the lowest degree of a polynomial the public shares agree with.
+		// It is -1 if they agree with none, i.e. BigXj does not match ECDSAPub.
+		Threshold int
+		ShareID   *big.Int
+		// the SEC1 encodings of ECDSAPub, in hex
+		PublicKeyCompressed, PublicKeyUncompressed string
+		ChainCode                                  string `json:",omitempty"`
+		Ks                                         []*big.Int
+		// BigXj in the SEC1 compressed encoding, in hex
+		BigXj []string
+		// PaillierFingerprints[j] identifies the Paillier modulus of Pj, NTildeFingerprints[j] its NTilde, h1 and h2
+		PaillierFingerprints, NTildeFingerprints []string
+		Weights                                  []int `json:",omitempty"`
+		Ranks                                    []int `json:",omitempty"`
+	}
+
+	// FieldDiff is a public field that disagrees between two save data; A or B is empty if only one of them has it.
+	FieldDiff struct {
+		Field, A, B string
+	}
+)
+
+// Inspect returns the public fields of the save data.
+func (save LocalPartySaveData) Inspect() (*PublicInfo, error) {
+	if save.ECDSAPub == nil {
+		return nil, errors.New("the save data holds no public key")
+	}
+	n := len(save.Ks)
+	if len(save.BigXj) != n || len(save.PaillierPKs) != n || len(save.NTildej) != n ||
+		len(save.H1j) != n || len(save.H2j) != n {
+		return nil, fmt.Errorf("the save data of %d parties holds %d BigXj, %d Paillier keys and %d NTilde",
+			n, len(save.BigXj), len(save.PaillierPKs), len(save.NTildej))
+	}
+	curve, ok := tss.GetCurveName(save.ECDSAPub.Curve())
+	if !ok {
+		return nil, errors.New("the curve of the save data is not registered")
+	}
+	info := &PublicInfo{
+		Curve:                 curve,
+		PartyCount:            n,
+		Threshold:             save.threshold(),
+		ShareID:               save.ShareID,
+		PublicKeyCompressed:   hex.EncodeToString(encoding.SerializeCompressed(save.ECDSAPub)),
+		PublicKeyUncompressed: hex.EncodeToString(encoding.SerializeUncompressed(save.ECDSAPub)),
+		ChainCode:             hex.EncodeToString(save.ChainCode),
+		Ks:                    save.Ks,
+		BigXj:                 make([]string, n),
+		PaillierFingerprints:  make([]string, n),
+		NTildeFingerprints:    make([]string, n),
+		Weights:               save.Weights(),
+		Ranks:                 save.Ranks,
+	}
+	for j := 0; j < n; j++ {
+		if save.BigXj[j] != nil {
+			info.BigXj[j] = hex.EncodeToString(encoding.SerializeCompressed(save.BigXj[j]))
+		}
+		if save.PaillierPKs[j] != nil {
+			info.PaillierFingerprints[j] = fingerprint(save.PaillierPKs[j].N)
+		}
+		info.NTildeFingerprints[j] = fingerprint(save.NTildej[j], save.H1j[j], save.H2j[j])
+	}
+	return info, nil
+}
+
+// DiffSaveData returns the public fields that disagree between the save data of two parties of the same key, in the
+// order of PublicInfo. ShareID differs between parties and is left out.
+func DiffSaveData(a, b LocalPartySaveData) ([]FieldDiff, error) {
+	infoA, err := a.Inspect()
+	if err != nil {
+		return nil, err
+	}
+	infoB, err := b.Inspect()
+	if err != nil {
+		return nil, err
+	}
+	return infoA.Diff(infoB), nil
+}
+
+// Diff returns the fields of `info` and `other` that disagree, except ShareID.
+func (info *PublicInfo) Diff(other *PublicInfo) []FieldDiff {
+	fieldsA, fieldsB := info.fields(), other.fields()
+	valuesB := make(map[string]string, len(fieldsB))
+	for _, f := range fieldsB {
+		valuesB[f[0]] = f[1]
+	}
+	var diffs []FieldDiff
+	seen := make(map[string]bool, len(fieldsA))
+	for _, f := range fieldsA {
+		seen[f[0]] = true
+		if vb, ok := valuesB[f[0]]; !ok || vb != f[1] {
+			diffs = append(diffs, FieldDiff{Field: f[0], A: f[1], B: vb})
+		}
+	}
+	for _, f := range fieldsB {
+		if !seen[f[0]] {
+			diffs = append(diffs, FieldDiff{Field: f[0], B: f[1]})
+		}
+	}
+	return diffs
+}
+
+// ----- //
+
+// fields flattens the info into name, value pairs, one per list element, for Diff
+func (info *PublicInfo) fields() [][2]string {
+	fields := [][2]string{
+		{"Curve", string(info.Curve)},
+		{"PartyCount", fmt.Sprint(info.PartyCount)},
+		{"Threshold", fmt.Sprint(info.Threshold)},
+		{"PublicKeyCompressed", info.PublicKeyCompressed},
+		{"PublicKeyUncompressed", info.PublicKeyUncompressed},
+		{"ChainCode", info.ChainCode},
+	}
+	add := func(name string, n int, value func(j int) string) {
+		for j := 0; j < n; j++ {
+			fields = append(fields, [2]string{fmt.Sprintf("%s[%d]", name, j), value(j)})
+		}
+	}
+	add("Ks", len(info.Ks), func(j int) string { return fmt.Sprint(info.Ks[j]) })
+	add("BigXj", len(info.BigXj), func(j int) string { return info.BigXj[j] })
+	add("PaillierFingerprints", len(info.PaillierFingerprints), func(j int) string { return info.PaillierFingerprints[j] })
+	add("NTildeFingerprints", len(info.NTildeFingerprints), func(j int) string { return info.NTildeFingerprints[j] })
+	add("Weights", len(info.Weights), func(j int) string { return fmt.Sprint(info.Weights[j]) })
+	add("Ranks", len(info.Ranks), func(j int) string { return fmt.Sprint(info.Ranks[j]) })
+	return fields
+}
+
+// threshold returns the lowest t such that t+1 of the public shares interpolate to ECDSAPub, or -1 if there is none.
+// The shares of the lowest ranks are taken first, as a hierarchical key needs them to be authorized.
+func (save LocalPartySaveData) threshold() int {
+	var (
+		ks    []*big.Int
+		bigXs []*crypto.ECPoint
+		ranks []int
+	)
+	if save.IsWeighted() {
+		if len(save.WeightedBigXj) != len(save.WeightedKs) {
+			return -1
+		}
+		for j := range save.WeightedKs {
+			if len(save.WeightedKs[j]) != len(save.WeightedBigXj[j]) {
+				return -1
+			}
+			ks = append(ks, save.WeightedKs[j]...)
+			bigXs = append(bigXs, save.WeightedBigXj[j]...)
+		}
+		ranks = make([]int, len(ks))
+	} else {
+		ks, bigXs = save.Ks, save.BigXj
+		ranks = save.Ranks
+		if ranks == nil {
+			ranks = make([]int, len(ks))
+		}
+		if len(ranks) != len(ks) {
+			return -1
+		}
+	}
+	order := make([]int, len(ks))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return ranks[order[a]] < ranks[order[b]] })
+
+	for _, idx := range order {
+		if ks[idx] == nil || bigXs[idx] == nil {
+			return -1
+		}
+	}
+	ec := save.ECDSAPub.Curve()
+	for size := 1; size <= len(order); size++ {
+		// a set of a hierarchical key may interpolate with a zero coefficient, which vss refuses; try the next
+		for start := 0; start < len(order); start++ {
+			subset := make([]int, size)
+			for i := range subset {
+				subset[i] = order[(start+i)%len(order)]
+			}
+			y, err := interpolate(ec, size-1, subset, ks, bigXs, ranks)
+			if err != nil {
+				continue
+			}
+			if y.Equals(save.ECDSAPub) {
+				return size - 1
+			}
+			break
+		}
+	}
+	return -1
+}
+
+// interpolate returns the secret of degree `threshold` in the exponent from the public shares at `subset`
+func interpolate(ec elliptic.Curve, threshold int, subset []int, ks []*big.Int, bigXs []*crypto.ECPoint, ranks []int) (*crypto.ECPoint, error) {
+	subKs, subRanks := make([]*big.Int, len(subset)), make([]int, len(subset))
+	for i, idx := range subset {
+		subKs[i], subRanks[i] = ks[idx], ranks[idx]
+	}
+	coefs, err := vss.BirkhoffCoefficients(ec, threshold, subKs, subRanks)
+	if err != nil {
+		return nil, err
+	}
+	y := bigXs[subset[0]].ScalarMult(coefs[0])
+	for i, idx := range subset[1:] {
+		if y, err = y.Add(bigXs[idx].ScalarMult(coefs[i+1])); err != nil {
+			return nil, err
+		}
+	}
+	return y, nil
+}
+
+// fingerprint returns the first bytes of the hash of `values` in hex, or "" if one of them is missing
+func fingerprint(values ...*big.Int) string {
+	for _, v := range values {
+		if v == nil {
+			return ""
+		}
+	}
+	h := common.SHA512_256i(values...)
+	return fmt.Sprintf("%064x", h)[:2*fingerprintLen]
+}
@@ -0,0 +1,92 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/crypto/paillier"
+	"github.com/bnb-chain/tss-lib/crypto/vss"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+func TestInspectAndDiff(t *testing.T) {
+	keys, _, err := LoadKeygenTestFixtures(TestParticipants)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	info, err := keys[0].Inspect()
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, tss.Secp256k1, info.Curve)
+	assert.Equal(t, TestParticipants, info.PartyCount)
+	assert.Equal(t, TestThreshold, info.Threshold, "the threshold is derived from BigXj")
+	assert.Len(t, info.PublicKeyCompressed, 66)
+	assert.Len(t, info.PublicKeyUncompressed, 130)
+	assert.Len(t, info.PaillierFingerprints[1], 2*fingerprintLen)
+	assert.NotEqual(t, info.PaillierFingerprints[1], info.PaillierFingerprints[2])
+
+	bz, err := json.Marshal(info)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(bz), keys[0].Xi.String(), "the share must never be printed")
+
+	diffs, err := DiffSaveData(keys[0], keys[1])
+	assert.NoError(t, err)
+	assert.Empty(t, diffs, "the save data of two parties of a key agree on every public field")
+
+	// party 1 saved another NTilde for P2 and a truncated key list
+	tampered := keys[1]
+	tampered.NTildej = append([]*big.Int{}, tampered.NTildej...)
+	tampered.NTildej[2] = new(big.Int).Add(tampered.NTildej[2], big.NewInt(2))
+	tampered.PaillierPKs = append([]*paillier.PublicKey{}, tampered.PaillierPKs...)
+	tampered.PaillierPKs[3] = &paillier.PublicKey{N: big.NewInt(77)}
+	diffs, err = DiffSaveData(keys[0], tampered)
+	assert.NoError(t, err)
+	var fields []string
+	for _, d := range diffs {
+		fields = append(fields, d.Field)
+	}
+	assert.Equal(t, []string{"PaillierFingerprints[3]", "NTildeFingerprints[2]"}, fields)
+
+	other := keys[2]
+	other.Ks, other.BigXj = other.Ks[:3], other.BigXj[:3]
+	other.PaillierPKs, other.NTildej, other.H1j, other.H2j = other.PaillierPKs[:3], other.NTildej[:3], other.H1j[:3], other.H2j[:3]
+	diffs, err = DiffSaveData(keys[0], other)
+	assert.NoError(t, err)
+	assert.Equal(t, FieldDiff{Field: "PartyCount", A: "20", B: "3"}, diffs[0])
+	assert.Equal(t, FieldDiff{Field: "Threshold", A: "10", B: "-1"}, diffs[1])
+	assert.Equal(t, "Ks[3]", diffs[2].Field)
+	assert.Empty(t, diffs[2].B)
+}
+
+func TestInspectThresholdHierarchical(t *testing.T) {
+	ec := tss.S256()
+	ids := []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3), big.NewInt(4)}
+	ranks := []int{1, 0, 0, 1}
+	secret := common.GetRandomPositiveInt(ec.Params().N)
+	vs, shares, err := vss.CreateHierarchical(ec, 2, secret, ids, ranks)
+	if !assert.NoError(t, err) {
+		return
+	}
+	save := NewLocalPartySaveData(len(ids))
+	save.Ks, save.Ranks = ids, ranks
+	for j, share := range shares {
+		save.BigXj[j] = crypto.ScalarBaseMult(ec, share.Share)
+	}
+	save.ECDSAPub = vs[0]
+	assert.Equal(t, 2, save.threshold())
+
+	save.ECDSAPub = crypto.ScalarBaseMult(ec, big.NewInt(1))
+	assert.Equal(t, -1, save.threshold())
+}
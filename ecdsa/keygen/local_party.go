@@ -11,7 +11,6 @@ import (
 	"fmt"
 	"math/big"
 
-	"github.com/bnb-chain/tss-lib/common"
 	cmt "github.com/bnb-chain/tss-lib/crypto/commitments"
 	"github.com/bnb-chain/tss-lib/crypto/paillier"
 	"github.com/bnb-chain/tss-lib/crypto/vss"
@@ -40,7 +39,9 @@ type (
 		kgRound1Messages,
 		kgRound2Message1s,
 		kgRound2Message2s,
-		kgRound3Messages []tss.ParsedMessage
+		kgRound3Messages,
+		kgRound4Messages,
+		kgRound5Messages []tss.ParsedMessage
 	}
 
 	localTempData struct {
@@ -51,10 +52,17 @@ type (
 		KGCs          []cmt.HashCommitment
 		vs            vss.Vs
 		shares        vss.Shares
+		shareIDs      [][]*big.Int // the evaluation points of each Pj; `shares` holds ours for all of them, in order
+		pjVs          []vss.Vs     // de-committed Vs of each Pj, kept for the complaint phase
+		complaints    [][]int      // complaints[j] lists the indexes of parties that accused Pj in round 3
 		deCommitPolyG cmt.HashDeCommitment
-		skTilde       *paillier.PrivateKey
-		ssid          []byte
-		ssidNonce     *big.Int
+		// chain code shares committed to with the VSS Vs when chain code generation is enabled
+		chainCodeShares []*big.Int
+		skTilde         *paillier.PrivateKey
+		// set on the followers of a BatchLocalParty, which reuse the leader's Paillier and NTilde proofs
+		leader    *localTempData
+		ssid      []byte
+		ssidNonce *big.Int
 	}
 )
 
@@ -90,8 +98,12 @@ func NewLocalParty(
 	p.temp.kgRound2Message1s = make([]tss.ParsedMessage, partyCount)
 	p.temp.kgRound2Message2s = make([]tss.ParsedMessage, partyCount)
 	p.temp.kgRound3Messages = make([]tss.ParsedMessage, partyCount)
+	p.temp.kgRound4Messages = make([]tss.ParsedMessage, partyCount)
+	p.temp.kgRound5Messages = make([]tss.ParsedMessage, partyCount)
 	// temp data init
 	p.temp.KGCs = make([]cmt.HashCommitment, partyCount)
+	p.temp.pjVs = make([]vss.Vs, partyCount)
+	p.temp.chainCodeShares = make([]*big.Int, partyCount)
 	return p
 }
 
@@ -164,8 +176,18 @@ func (p *LocalParty) StoreMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
 			return dupErr()
 		}
 		p.temp.kgRound3Messages[fromPIdx] = msg
+	case *KGRound4Message:
+		if isDup && p.temp.kgRound4Messages[fromPIdx] != nil && !tss.IsSameMessage(p.temp.kgRound4Messages[fromPIdx], msg) {
+			return dupErr()
+		}
+		p.temp.kgRound4Messages[fromPIdx] = msg
+	case *KGRound5Message:
+		if isDup && p.temp.kgRound5Messages[fromPIdx] != nil && !tss.IsSameMessage(p.temp.kgRound5Messages[fromPIdx], msg) {
+			return dupErr()
+		}
+		p.temp.kgRound5Messages[fromPIdx] = msg
 	default: // unrecognised message, just ignore!
-		common.Logger.Warningf("unrecognised message ignored: %v", msg)
+		p.params.Logger().With(tss.LogKeyTask, TaskName).Warn("unrecognised message ignored", "msg", msg.String())
 		return false, nil
 	}
 	return true, nil
@@ -16,17 +16,23 @@ import (
 	"os"
 	"runtime"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/ipfs/go-log"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/bnb-chain/tss-lib/common"
 	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/crypto/ckd"
 	"github.com/bnb-chain/tss-lib/crypto/dlnproof"
 	"github.com/bnb-chain/tss-lib/crypto/paillier"
 	"github.com/bnb-chain/tss-lib/crypto/vss"
+	"github.com/bnb-chain/tss-lib/metrics"
 	"github.com/bnb-chain/tss-lib/test"
 	"github.com/bnb-chain/tss-lib/tss"
 )
@@ -461,3 +467,421 @@ func tryWriteTestFixtureFile(t *testing.T, index int, data LocalPartySaveData) {
 	}
 	//
 }
+
+// TestE2EComplaintDismissed has P[1] complain about P[0] twice over: once because P[0] sends it a bad share in
+// round 2 and reveals the real one in round 4, as a dealer framing an honest party would, and once as a false
+// accusation. Either way the complaint is dismissed, P[1] uses the revealed share, keygen completes and nobody is blamed.
+func TestE2EComplaintDismissed(t *testing.T) {
+	setUp("info")
+
+	cases := []struct {
+		name   string
+		tamper func(tss.ParsedMessage) tss.ParsedMessage
+	}{{
+		name: "bad share in transit",
+		tamper: func(msg tss.ParsedMessage) tss.ParsedMessage {
+			if r2msg1, ok := msg.Content().(*KGRound2Message1); ok && msg.GetFrom().Index == 0 && msg.GetTo()[0].Index == 1 {
+				bad := proto.Clone(r2msg1).(*KGRound2Message1)
+				bad.Share = new(big.Int).Add(r2msg1.UnmarshalShare(), big.NewInt(1)).Bytes()
+				return rewrapTestMessage(msg, bad)
+			}
+			return msg
+		},
+	}, {
+		name: "false accusation",
+		tamper: func(msg tss.ParsedMessage) tss.ParsedMessage {
+			if _, ok := msg.Content().(*KGRound3Message); ok && msg.GetFrom().Index == 1 {
+				return rewrapTestMessage(msg, &KGRound3Message{Accused: []uint32{0}})
+			}
+			return msg
+		},
+	}}
+	for _, tc := range cases {
+		registry := metrics.NewRegistry()
+		saves, errs := runKeygenWithTamper(t, 3, 1, 3, tc.tamper, func(_ int, params *tss.Parameters) {
+			params.SetObserver(registry)
+		})
+		if !assert.Empty(t, errs, tc.name) || !assert.Len(t, saves, 3, tc.name) {
+			continue
+		}
+		assert.Zero(t, registry.Counter(metrics.CulpritsDetected, TaskName, "5"), "nobody should be blamed: %s", tc.name)
+		for _, save := range saves {
+			assert.True(t, save.ECDSAPub.Equals(saves[0].ECDSAPub), "everyone should have the same public key")
+			index, err := save.OriginalIndex()
+			assert.NoError(t, err)
+			gXi := crypto.ScalarBaseMult(tss.EC(), save.Xi)
+			assert.True(t, saves[0].BigXj[index].Equals(gXi), "ensure BigX_j == g^x_j")
+		}
+	}
+}
+
+// TestE2EComplaintProvesDealerFaulty additionally corrupts P[0]'s round 4 justification,
+// so every other party must blame P[0] and only P[0]. P[0] itself never sees the corruption, so it is left waiting.
+func TestE2EComplaintProvesDealerFaulty(t *testing.T) {
+	setUp("info")
+
+	registry := metrics.NewRegistry()
+	saves, errs := runKeygenWithTamper(t, 3, 1, 2, func(msg tss.ParsedMessage) tss.ParsedMessage {
+		if msg.GetFrom().Index != 0 {
+			return msg
+		}
+		switch content := msg.Content().(type) {
+		case *KGRound2Message1:
+			if msg.GetTo()[0].Index == 1 {
+				bad := proto.Clone(content).(*KGRound2Message1)
+				bad.Share = new(big.Int).Add(content.UnmarshalShare(), big.NewInt(1)).Bytes()
+				return rewrapTestMessage(msg, bad)
+			}
+		case *KGRound4Message:
+			bad := proto.Clone(content).(*KGRound4Message)
+			bad.Shares[0] = new(big.Int).Add(new(big.Int).SetBytes(content.Shares[0]), big.NewInt(1)).Bytes()
+			return rewrapTestMessage(msg, bad)
+		}
+		return msg
+	}, func(_ int, params *tss.Parameters) {
+		params.SetObserver(registry)
+	})
+	assert.Empty(t, saves)
+	if !assert.Len(t, errs, 2) {
+		return
+	}
+	for _, err := range errs {
+		assert.Equal(t, 5, err.Round())
+		if assert.Len(t, err.Culprits(), 1) {
+			assert.Equal(t, 0, err.Culprits()[0].Index)
+		}
+	}
+	assert.True(t, registry.Counter(metrics.CulpritsDetected, TaskName, "5") >= 2, "the observer should see the blame")
+	count, _ := registry.Histogram(metrics.ProofDuration, TaskName, tss.ProofVSS, "false")
+	assert.True(t, count >= 2, "the shares revealed by P[0] should be invalid")
+}
+
+func TestE2EChainCodeGeneration(t *testing.T) {
+	setUp("info")
+	keep := func(msg tss.ParsedMessage) tss.ParsedMessage { return msg }
+	saves, errs := runKeygenWithTamper(t, 3, 1, 3, keep, func(_ int, params *tss.Parameters) {
+		params.SetChainCodeGeneration(true)
+	})
+	if !assert.Empty(t, errs) || !assert.Len(t, saves, 3) {
+		return
+	}
+	xpub, err := saves[0].ExtendedPublicKey()
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(xpub.String(), "xpub"))
+	for _, save := range saves {
+		assert.Len(t, save.ChainCode, 32)
+		assert.Equal(t, saves[0].ChainCode, save.ChainCode, "every party must end up with the same chain code")
+		other, err := save.ExtendedPublicKey()
+		assert.NoError(t, err)
+		assert.Equal(t, xpub.String(), other.String())
+	}
+	parsed, err := ckd.NewExtendedKeyFromString(xpub.String(), tss.S256())
+	assert.NoError(t, err)
+	assert.Equal(t, saves[0].ChainCode, parsed.ChainCode)
+	assert.Equal(t, 0, parsed.X.Cmp(saves[0].ECDSAPub.X()))
+	vpub, err := saves[0].ExtendedPublicKeyFor(&chaincfg.TestNet3Params, ckd.ScriptP2WPKH)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(vpub.String(), "vpub"))
+}
+
+// entryLogger records the entries of a structured logger with their fields
+type entryLogger struct {
+	mtx     *sync.Mutex
+	entries *[]map[string]interface{}
+	fields  []interface{}
+}
+
+func newEntryLogger() *entryLogger {
+	return &entryLogger{mtx: new(sync.Mutex), entries: new([]map[string]interface{})}
+}
+
+func (l *entryLogger) log(msg string, keysAndValues []interface{}) {
+	entry := map[string]interface{}{"msg": msg}
+	kvs := append(append([]interface{}{}, l.fields...), keysAndValues...)
+	for i := 0; i+1 < len(kvs); i += 2 {
+		entry[kvs[i].(string)] = kvs[i+1]
+	}
+	l.mtx.Lock()
+	*l.entries = append(*l.entries, entry)
+	l.mtx.Unlock()
+}
+
+func (l *entryLogger) Debug(msg string, keysAndValues ...interface{}) { l.log(msg, keysAndValues) }
+func (l *entryLogger) Info(msg string, keysAndValues ...interface{})  { l.log(msg, keysAndValues) }
+func (l *entryLogger) Warn(msg string, keysAndValues ...interface{})  { l.log(msg, keysAndValues) }
+func (l *entryLogger) Error(msg string, keysAndValues ...interface{}) { l.log(msg, keysAndValues) }
+func (l *entryLogger) With(keysAndValues ...interface{}) tss.Logger {
+	return &entryLogger{mtx: l.mtx, entries: l.entries, fields: append(append([]interface{}{}, l.fields...), keysAndValues...)}
+}
+
+func TestE2ELoggerCarriesFields(t *testing.T) {
+	setUp("info")
+	keep := func(msg tss.ParsedMessage) tss.ParsedMessage { return msg }
+	logger := newEntryLogger()
+	var party, session string
+	_, errs := runKeygenWithTamper(t, 3, 1, 3, keep, func(i int, params *tss.Parameters) {
+		if i == 0 {
+			params.SetLogger(logger)
+			party, session = params.PartyID().String(), params.SessionNonce().Text(16)
+		}
+	})
+	if !assert.Empty(t, errs) {
+		return
+	}
+	rounds, finished := make(map[interface{}]bool), false
+	logger.mtx.Lock()
+	defer logger.mtx.Unlock()
+	for _, entry := range *logger.entries {
+		assert.Equal(t, party, entry[tss.LogKeyParty], "entry %v", entry)
+		assert.Equal(t, session, entry[tss.LogKeySession], "entry %v", entry)
+		assert.Equal(t, TaskName, entry[tss.LogKeyTask], "entry %v", entry)
+		if round, ok := entry[tss.LogKeyRound]; ok {
+			rounds[round] = true
+		} else {
+			finished = true
+		}
+	}
+	assert.Equal(t, map[interface{}]bool{1: true, 2: true, 3: true, 4: true, 5: true, 6: true}, rounds)
+	assert.True(t, finished, "the party logs to the same logger once it has no round left")
+}
+
+// roundObserver records the rounds started and finished by a party
+type roundObserver struct {
+	tss.NoopObserver
+	mtx               sync.Mutex
+	started, finished []int
+	last              int
+}
+
+func (o *roundObserver) RoundStarted(e tss.RoundEvent) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	o.started = append(o.started, e.Round)
+}
+
+func (o *roundObserver) RoundFinished(e tss.RoundEvent) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	o.finished = append(o.finished, e.Round)
+	if e.Last {
+		o.last = e.Round
+	}
+}
+
+func TestE2EObserver(t *testing.T) {
+	setUp("info")
+	const n = 3
+	keep := func(msg tss.ParsedMessage) tss.ParsedMessage { return msg }
+	registry := metrics.NewRegistry()
+	rounds := new(roundObserver)
+	_, errs := runKeygenWithTamper(t, n, 1, n, keep, func(i int, params *tss.Parameters) {
+		if i == 0 {
+			params.SetObserver(tss.MultiObserver{registry, rounds})
+			return
+		}
+		params.SetObserver(registry)
+	})
+	if !assert.Empty(t, errs) {
+		return
+	}
+	rounds.mtx.Lock()
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6}, rounds.started)
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6}, rounds.finished)
+	assert.Equal(t, 6, rounds.last)
+	rounds.mtx.Unlock()
+
+	for round := 1; round <= 6; round++ {
+		assert.Equal(t, float64(n), registry.Counter(metrics.RoundsStarted, TaskName, fmt.Sprint(round)), "round %d", round)
+		count, _ := registry.Histogram(metrics.RoundDuration, TaskName, fmt.Sprint(round))
+		assert.Equal(t, uint64(n), count, "round %d", round)
+	}
+	// a message may arrive while its receiver is still in an earlier round
+	received, bytes := float64(0), float64(0)
+	r1Type := string(proto.MessageName(new(KGRound1Message)))
+	for round := 1; round <= 6; round++ {
+		received += registry.Counter(metrics.MessagesReceived, TaskName, fmt.Sprint(round), r1Type)
+		bytes += registry.Counter(metrics.MessageBytes, TaskName, fmt.Sprint(round), r1Type)
+	}
+	assert.Equal(t, float64(n*(n-1)), received)
+	assert.True(t, bytes > 0)
+
+	// round 2 checks the DLN and mod proofs of every round 1 message, the party's own included
+	verified := map[string]uint64{
+		tss.ProofDLN:      2 * n * n,
+		tss.ProofMod:      2 * n * n,
+		tss.ProofFactor:   2 * n * (n - 1),
+		tss.ProofVSS:      n * (n - 1),
+		tss.ProofPaillier: n * (n - 1),
+	}
+	for proof, count := range verified {
+		valid, _ := registry.Histogram(metrics.ProofDuration, TaskName, proof, "true")
+		invalid, _ := registry.Histogram(metrics.ProofDuration, TaskName, proof, "false")
+		assert.Equal(t, count, valid, proof)
+		assert.Zero(t, invalid, proof)
+	}
+	assert.Zero(t, registry.Counter(metrics.CulpritsDetected, TaskName, "2"))
+}
+
+// TestObserverPreParamsGeneration has a party without pre-params give up on generating them at once; the observer
+// still sees the attempt and the time it took
+func TestObserverPreParamsGeneration(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(2)
+	params := tss.NewParameters(tss.S256(), tss.NewPeerContext(pIDs), pIDs[0], len(pIDs), 1)
+	params.SetSessionNonce(big.NewInt(1))
+	params.SetSafePrimeGenTimeout(time.Millisecond)
+	registry := metrics.NewRegistry()
+	params.SetObserver(registry)
+
+	P := NewLocalParty(params, make(chan tss.Message, len(pIDs)), make(chan LocalPartySaveData, 1))
+	err := P.Start()
+	if assert.NotNil(t, err) {
+		assert.Equal(t, 1, err.Round())
+	}
+	count, sum := registry.Histogram(metrics.PreParamsDuration, TaskName, "false")
+	assert.Equal(t, uint64(1), count)
+	assert.True(t, 0 < sum)
+	count, _ = registry.Histogram(metrics.PreParamsDuration, TaskName, "true")
+	assert.Zero(t, count)
+}
+
+func TestE2EChainCodeGenerationMismatchIsBlamed(t *testing.T) {
+	setUp("info")
+	keep := func(msg tss.ParsedMessage) tss.ParsedMessage { return msg }
+	const odd = 2
+	_, errs := runKeygenWithTamper(t, 3, 1, 3, keep, func(i int, params *tss.Parameters) {
+		params.SetChainCodeGeneration(i != odd)
+	})
+	if !assert.Len(t, errs, 3) {
+		return
+	}
+	for _, err := range errs {
+		assert.Equal(t, 3, err.Round())
+		if err.Victim().Index == odd {
+			continue
+		}
+		if assert.Len(t, err.Culprits(), 1) {
+			assert.Equal(t, odd, err.Culprits()[0].Index)
+		}
+	}
+}
+
+// countingProofCache counts the cache hits of a party
+type countingProofCache struct {
+	*tss.MemoryProofCache
+	hits int32
+}
+
+func (c *countingProofCache) Contains(key []byte) bool {
+	ok := c.MemoryProofCache.Contains(key)
+	if ok {
+		atomic.AddInt32(&c.hits, 1)
+	}
+	return ok
+}
+
+func TestE2EProofCacheSkipsVerifiedPreParams(t *testing.T) {
+	setUp("info")
+	const n = 3
+	keep := func(msg tss.ParsedMessage) tss.ParsedMessage { return msg }
+	caches := make([]*countingProofCache, n)
+	for i := range caches {
+		caches[i] = &countingProofCache{MemoryProofCache: tss.NewMemoryProofCache(0)}
+	}
+	withCache := func(i int, params *tss.Parameters) {
+		params.SetProofCache(caches[i])
+	}
+
+	_, errs := runKeygenWithTamper(t, n, 1, n, keep, withCache)
+	if !assert.Empty(t, errs) {
+		return
+	}
+	for _, c := range caches {
+		assert.Equal(t, n, c.Len(), "every party's pre-params are cached once verified")
+		assert.Zero(t, atomic.LoadInt32(&c.hits))
+	}
+
+	saves, errs := runKeygenWithTamper(t, n, 1, n, keep, withCache)
+	if !assert.Empty(t, errs) || !assert.Len(t, saves, n) {
+		return
+	}
+	for _, c := range caches {
+		assert.Equal(t, int32(n), atomic.LoadInt32(&c.hits), "a ceremony reusing pre-params skips their proofs")
+	}
+}
+
+func TestProofCacheKeyBindsProverIdentity(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(2)
+	N, NTilde, h1, h2 := big.NewInt(11), big.NewInt(13), big.NewInt(2), big.NewInt(3)
+	key := proofCacheKey(pIDs[0], N, NTilde, h1, h2)
+	assert.Equal(t, key, proofCacheKey(pIDs[0], N, NTilde, h1, h2))
+	assert.NotEqual(t, key, proofCacheKey(pIDs[1], N, NTilde, h1, h2), "another party presenting the same parameters must prove them")
+	assert.NotEqual(t, key, proofCacheKey(pIDs[0], N, NTilde, h2, h1))
+}
+
+// runKeygenWithTamper runs keygen over `n` fixture parties and passes every message through `tamper` before delivery.
+// It returns once `settled` parties have either finished or failed.
+func runKeygenWithTamper(t *testing.T, n, threshold, settled int, tamper func(tss.ParsedMessage) tss.ParsedMessage, configure ...func(i int, params *tss.Parameters)) ([]LocalPartySaveData, []*tss.Error) {
+	fixtures, pIDs, err := LoadKeygenTestFixtures(n)
+	if err != nil {
+		t.Skip("test fixtures required (LocalPreParams) to run a quick keygen")
+	}
+	p2pCtx := tss.NewPeerContext(pIDs)
+	parties := make([]*LocalParty, 0, len(pIDs))
+
+	errCh := make(chan *tss.Error, len(pIDs)*len(pIDs))
+	outCh := make(chan tss.Message, len(pIDs)*len(pIDs))
+	endCh := make(chan LocalPartySaveData, len(pIDs))
+
+	for i := 0; i < len(pIDs); i++ {
+		params := tss.NewParameters(tss.S256(), p2pCtx, pIDs[i], len(pIDs), threshold)
+		params.SetSessionNonce(big.NewInt(5))
+		for _, c := range configure {
+			c(i, params)
+		}
+		P := NewLocalParty(params, outCh, endCh, fixtures[i].LocalPreParams).(*LocalParty)
+		parties = append(parties, P)
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	saves := make([]LocalPartySaveData, 0, len(pIDs))
+	failed := make(map[int]*tss.Error, len(pIDs))
+	for len(saves)+len(failed) < settled {
+		select {
+		case err := <-errCh:
+			failed[err.Victim().Index] = err
+		case msg := <-outCh:
+			msg = tamper(msg.(tss.ParsedMessage))
+			dest := msg.GetTo()
+			if dest == nil {
+				for _, P := range parties {
+					if P.PartyID().Index == msg.GetFrom().Index {
+						continue
+					}
+					go test.SharedPartyUpdater(P, msg, errCh)
+				}
+			} else {
+				go test.SharedPartyUpdater(parties[dest[0].Index], msg, errCh)
+			}
+		case save := <-endCh:
+			saves = append(saves, save)
+		}
+	}
+	errs := make([]*tss.Error, 0, len(failed))
+	for _, err := range failed {
+		errs = append(errs, err)
+	}
+	return saves, errs
+}
+
+func rewrapTestMessage(msg tss.ParsedMessage, content tss.MessageContent) tss.ParsedMessage {
+	meta := tss.MessageRouting{
+		From:        msg.GetFrom(),
+		To:          msg.GetTo(),
+		IsBroadcast: msg.IsBroadcast(),
+	}
+	return tss.NewMessage(meta, content, tss.NewMessageWrapper(meta, content))
+}
@@ -27,6 +27,9 @@ var (
 		(*KGRound2Message1)(nil),
 		(*KGRound2Message2)(nil),
 		(*KGRound3Message)(nil),
+		(*KGRound4Message)(nil),
+		(*KGRound5Message)(nil),
+		(*KGBatchMessage)(nil),
 	}
 )
 
@@ -153,10 +156,13 @@ func (p *KGRound1Message_ModProof) ValidateBasic() bool {
 
 // ----- //
 
+// NewKGRound2Message1 sends `share` to `to`; with weighted sharing, `subShares` are the shares for the
+// recipient's further evaluation points.
 func NewKGRound2Message1(
 	to, from *tss.PartyID,
 	share *vss.Share,
 	proof, proofTilde *paillier.FactorProof,
+	subShares ...*vss.Share,
 ) tss.ParsedMessage {
 	meta := tss.MessageRouting{
 		From:        from,
@@ -201,10 +207,18 @@ func NewKGRound2Message1(
 		// The proof is nil when creating the self-message in round 2.
 		facProofTilde = nil
 	}
+	var subShareBzs [][]byte
+	if 0 < len(subShares) {
+		subShareBzs = make([][]byte, len(subShares))
+		for k, subShare := range subShares {
+			subShareBzs[k] = subShare.Share.Bytes()
+		}
+	}
 	content := &KGRound2Message1{
 		Share:         share.Share.Bytes(),
 		Facproof:      facProof,
 		FacproofTilde: facProofTilde,
+		SubShares:     subShareBzs,
 	}
 	msg := tss.NewMessageWrapper(meta, content)
 	return tss.NewMessage(meta, content, msg)
@@ -213,6 +227,7 @@ func NewKGRound2Message1(
 func (m *KGRound2Message1) ValidateBasic() bool {
 	return m != nil &&
 		common.NonEmptyBytes(m.GetShare()) &&
+		(len(m.GetSubShares()) == 0 || common.NonEmptyMultiBytes(m.GetSubShares())) &&
 		m.GetFacproof().ValidateBasic() &&
 		m.GetFacproofTilde().ValidateBasic()
 }
@@ -221,6 +236,11 @@ func (m *KGRound2Message1) UnmarshalShare() *big.Int {
 	return new(big.Int).SetBytes(m.Share)
 }
 
+// UnmarshalShares returns the share followed by the sub-shares
+func (m *KGRound2Message1) UnmarshalShares() []*big.Int {
+	return append([]*big.Int{m.UnmarshalShare()}, common.MultiBytesToBigInts(m.GetSubShares())...)
+}
+
 func (m *KGRound2Message1) UnmarshalFactorProof() *paillier.FactorProof {
 	proof := m.GetFacproof()
 	return &paillier.FactorProof{
@@ -301,6 +321,82 @@ func (m *KGRound2Message2) UnmarshalDeCommitment() []*big.Int {
 // ----- //
 
 func NewKGRound3Message(
+	from *tss.PartyID,
+	accused []int,
+) tss.ParsedMessage {
+	meta := tss.MessageRouting{
+		From:        from,
+		IsBroadcast: true,
+	}
+	content := &KGRound3Message{
+		Accused: intsToUint32s(accused),
+	}
+	msg := tss.NewMessageWrapper(meta, content)
+	return tss.NewMessage(meta, content, msg)
+}
+
+// ValidateBasic accepts an empty accusation list; indexes are range-checked in round 4
+func (m *KGRound3Message) ValidateBasic() bool {
+	return m != nil
+}
+
+func (m *KGRound3Message) UnmarshalAccused() []int {
+	return uint32sToInts(m.GetAccused())
+}
+
+// ----- //
+
+func NewKGRound4Message(
+	from *tss.PartyID,
+	complainers []int,
+	shares []*big.Int,
+) tss.ParsedMessage {
+	meta := tss.MessageRouting{
+		From:        from,
+		IsBroadcast: true,
+	}
+	content := &KGRound4Message{
+		Complainers: intsToUint32s(complainers),
+		Shares:      common.BigIntsToBytes(shares),
+	}
+	msg := tss.NewMessageWrapper(meta, content)
+	return tss.NewMessage(meta, content, msg)
+}
+
+func (m *KGRound4Message) ValidateBasic() bool {
+	return m != nil &&
+		0 < len(m.GetComplainers()) &&
+		len(m.GetComplainers()) <= len(m.GetShares()) && // more with weighted sharing
+		common.NonEmptyMultiBytes(m.GetShares())
+}
+
+func (m *KGRound4Message) UnmarshalComplainers() []int {
+	return uint32sToInts(m.GetComplainers())
+}
+
+func (m *KGRound4Message) UnmarshalShares() []*big.Int {
+	return common.MultiBytesToBigInts(m.GetShares())
+}
+
+func intsToUint32s(in []int) []uint32 {
+	out := make([]uint32, len(in))
+	for i, v := range in {
+		out[i] = uint32(v)
+	}
+	return out
+}
+
+func uint32sToInts(in []uint32) []int {
+	out := make([]int, len(in))
+	for i, v := range in {
+		out[i] = int(v)
+	}
+	return out
+}
+
+// ----- //
+
+func NewKGRound5Message(
 	from *tss.PartyID,
 	proof paillier.Proof,
 ) tss.ParsedMessage {
@@ -315,19 +411,19 @@ func NewKGRound3Message(
 		}
 		pfBzs[i] = proof[i].Bytes()
 	}
-	content := &KGRound3Message{
+	content := &KGRound5Message{
 		PaillierProof: pfBzs,
 	}
 	msg := tss.NewMessageWrapper(meta, content)
 	return tss.NewMessage(meta, content, msg)
 }
 
-func (m *KGRound3Message) ValidateBasic() bool {
+func (m *KGRound5Message) ValidateBasic() bool {
 	return m != nil &&
 		common.NonEmptyMultiBytes(m.GetPaillierProof(), paillier.ProofIters)
 }
 
-func (m *KGRound3Message) UnmarshalProofInts() paillier.Proof {
+func (m *KGRound5Message) UnmarshalProofInts() paillier.Proof {
 	var pf paillier.Proof
 	proofBzs := m.GetPaillierProof()
 	for i := range pf {
@@ -335,3 +431,36 @@ func (m *KGRound3Message) UnmarshalProofInts() paillier.Proof {
 	}
 	return pf
 }
+
+// ----- //
+
+// NewKGBatchMessage bundles the wire bytes of one round message per key. A nil `to` broadcasts it.
+func NewKGBatchMessage(
+	to []*tss.PartyID,
+	from *tss.PartyID,
+	items [][]byte,
+) tss.ParsedMessage {
+	meta := tss.MessageRouting{
+		From:        from,
+		To:          to,
+		IsBroadcast: to == nil,
+	}
+	content := &KGBatchMessage{
+		Items: items,
+	}
+	msg := tss.NewMessageWrapper(meta, content)
+	return tss.NewMessage(meta, content, msg)
+}
+
+// ValidateBasic accepts empty items, for the keys with nothing to send, as long as one key has something
+func (m *KGBatchMessage) ValidateBasic() bool {
+	if m == nil {
+		return false
+	}
+	for _, item := range m.GetItems() {
+		if common.NonEmptyBytes(item) {
+			return true
+		}
+	}
+	return false
+}
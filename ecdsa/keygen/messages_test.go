@@ -104,3 +104,25 @@ func validModProofForValidation() *KGRound1Message_ModProof {
 		Z: z,
 	}
 }
+
+func TestKGRound4MessageValidateBasicRequiresOneSharePerComplainer(t *testing.T) {
+	msg := &KGRound4Message{Complainers: []uint32{1, 2}, Shares: [][]byte{{1}, {2}}}
+	if !msg.ValidateBasic() {
+		t.Fatal("expected baseline message to validate")
+	}
+
+	msg = &KGRound4Message{Complainers: []uint32{1, 2}, Shares: [][]byte{{1}}}
+	if msg.ValidateBasic() {
+		t.Fatal("expected a missing share to fail validation")
+	}
+
+	msg = &KGRound4Message{Complainers: []uint32{1}, Shares: [][]byte{{}}}
+	if msg.ValidateBasic() {
+		t.Fatal("expected an empty share to fail validation")
+	}
+
+	msg = &KGRound4Message{}
+	if msg.ValidateBasic() {
+		t.Fatal("expected a justification without complainers to fail validation")
+	}
+}
@@ -8,13 +8,16 @@ package keygen
 
 import (
 	"context"
+	"crypto/rand"
 	"errors"
+	"io"
 	"math/big"
 	"runtime"
 	"time"
 
 	"github.com/bnb-chain/tss-lib/common"
 	"github.com/bnb-chain/tss-lib/crypto/paillier"
+	"github.com/bnb-chain/tss-lib/tss"
 )
 
 const (
@@ -40,8 +43,16 @@ func GeneratePreParams(timeout time.Duration, optionalConcurrency ...int) (*Loca
 // This can be a time consuming process so it is recommended to do it out-of-band.
 // If not specified, a concurrency value equal to the number of available CPU cores will be used.
 // If pre-parameters could not be generated before the context is done, an error is returned.
+// The progress is logged to the logger of tss.ContextWithLogger, if ctx carries one.
 func GeneratePreParamsWithContext(ctx context.Context, optionalConcurrency ...int) (*LocalPreParams, error) {
-	var concurrency int
+	return GeneratePreParamsWithContextAndRandom(ctx, rand.Reader, optionalConcurrency...)
+}
+
+// GeneratePreParamsWithContextAndRandom is GeneratePreParamsWithContext drawing the primes and generators from
+// `rand`. The pre-parameters only depend on `rand` with a concurrency of 1, which searches for each set of primes in a
+// single process.
+func GeneratePreParamsWithContextAndRandom(ctx context.Context, rand io.Reader, optionalConcurrency ...int) (*LocalPreParams, error) {
+	var concurrency, paiConcurrency int
 	if 0 < len(optionalConcurrency) {
 		if 1 < len(optionalConcurrency) {
 			panic(errors.New("GeneratePreParams: expected 0 or 1 item in `optionalConcurrency`"))
@@ -50,39 +61,47 @@ func GeneratePreParamsWithContext(ctx context.Context, optionalConcurrency ...in
 	} else {
 		concurrency = runtime.NumCPU()
 	}
-	if concurrency /= 3; concurrency < 1 {
-		concurrency = 1
+	// more concurrency weight is assigned to the Paillier modulus because its primes have a requirement of having
+	// "large" P-Q
+	if concurrency == 1 {
+		paiConcurrency = 1
+	} else if concurrency /= 3; concurrency < 1 {
+		concurrency, paiConcurrency = 1, 2
+	} else {
+		paiConcurrency = concurrency * 2
 	}
 
-	// prepare for concurrent Paillier and safe prime generation
+	logger := tss.LoggerFromContext(ctx)
+
+	// prepare for concurrent Paillier and safe prime generation, each with a stream of its own
+	paiRand, sgpRand := common.ForkRand(rand), common.ForkRand(rand)
 	paiCh := make(chan *paillier.PrivateKey, 1)
 	sgpCh := make(chan []*common.GermainSafePrime, 1)
 
 	// 4. generate Paillier public key E_i, private key and proof
 	go func(ch chan<- *paillier.PrivateKey) {
-		common.Logger.Info("generating the Paillier modulus, please wait...")
+		logger.Info("generating the Paillier modulus, please wait...")
 		start := time.Now()
-		// more concurrency weight is assigned here because the paillier primes have a requirement of having "large" P-Q
-		PiPaillierSk, _, err := paillier.GenerateKeyPair(ctx, paillierModulusLen, concurrency*2)
+		PiPaillierSk, _, err := paillier.GenerateKeyPairFrom(ctx, paiRand, paillierModulusLen, paiConcurrency)
 		if err != nil {
 			ch <- nil
 			return
 		}
-		common.Logger.Infof("paillier modulus generated. took %s\n", time.Since(start))
+		logger.Info("paillier modulus generated", "took", time.Since(start))
 		ch <- PiPaillierSk
 	}(paiCh)
 
 	// 5-7. generate safe primes for ZKPs used later on
 	go func(ch chan<- []*common.GermainSafePrime) {
 		var err error
-		common.Logger.Info("generating the safe primes for the signing proofs, please wait...")
+		logger.Info("generating the safe primes for the signing proofs, please wait...")
 		start := time.Now()
-		sgps, err := common.GetRandomSafePrimesConcurrent(ctx, safePrimeBitLen, 2, concurrency)
+		sgps, err := common.GetRandomSafePrimesConcurrentFrom(ctx, sgpRand, safePrimeBitLen, 2, concurrency)
 		if err != nil {
 			ch <- nil
 			return
 		}
-		common.Logger.Infof("safe primes generated. took %s\n", time.Since(start))
+		logger.Info("safe primes generated", "took", time.Since(start))
 		ch <- sgps
 	}(sgpCh)
 
@@ -96,7 +115,7 @@ consumer:
 	for {
 		select {
 		case <-logProgressTicker.C:
-			common.Logger.Info("still generating primes...")
+			logger.Info("still generating primes...")
 		case sgps = <-sgpCh:
 			if sgps == nil ||
 				sgps[0] == nil || sgps[1] == nil ||
@@ -124,8 +143,8 @@ consumer:
 
 	p, q := sgps[0].Prime(), sgps[1].Prime()
 	modPQ := common.ModInt(new(big.Int).Mul(p, q))
-	f1 := common.GetRandomPositiveRelativelyPrimeInt(NTildei)
-	alpha := common.GetRandomPositiveRelativelyPrimeInt(NTildei)
+	f1 := common.GetRandomPositiveRelativelyPrimeIntFrom(rand, NTildei)
+	alpha := common.GetRandomPositiveRelativelyPrimeIntFrom(rand, NTildei)
 	beta := modPQ.ModInverse(alpha)
 	h1i := modNTildeI.Mul(f1, f1)
 	h2i := modNTildeI.Exp(h1i, alpha)
@@ -7,8 +7,12 @@
 package keygen
 
 import (
+	"context"
 	"errors"
 	"math/big"
+	"time"
+
+	"google.golang.org/protobuf/proto"
 
 	"github.com/bnb-chain/tss-lib/common"
 	"github.com/bnb-chain/tss-lib/crypto"
@@ -19,8 +23,11 @@ import (
 	"github.com/bnb-chain/tss-lib/tss"
 )
 
+const chainCodeBits = 256
+
 var (
-	zero = big.NewInt(0)
+	zero         = big.NewInt(0)
+	chainCodeTag = []byte("tss-lib/ecdsa/keygen/chain-code")
 )
 
 // round 1 represents round 1 of the keygen part of the GG18 ECDSA TSS spec (Gennaro, Goldfeder; 2018)
@@ -39,19 +46,33 @@ func (round *round1) Start() *tss.Error {
 
 	Pi := round.PartyID()
 	i := Pi.Index
+	rand := round.Params().Rand()
 
 	// 1. calculate "partial" key share ui
-	ui := common.GetRandomPositiveInt(round.Params().EC().Params().N)
+	ui := common.GetRandomPositiveIntFrom(rand, round.Params().EC().Params().N)
 
 	round.temp.ui = ui
 
-	// 2. compute the vss shares
+	// 2. compute the vss shares; with weighted sharing a party gets a share for each of its evaluation points,
+	// and with a hierarchical access structure the derivative of the polynomial of its rank
 	ids := round.Parties().IDs().Keys()
-	vs, shares, err := vss.Create(round.Params().EC(), round.Threshold(), ui, ids)
+	shareIDs := shareIDsOf(round.Params())
+	var vs vss.Vs
+	var shares vss.Shares
+	var err error
+	if ranks := round.Params().Ranks(); ranks != nil {
+		vs, shares, err = vss.CreateHierarchicalFrom(rand, round.Params().EC(), round.Threshold(), ui, ids, ranks)
+		round.save.Ranks = append([]int(nil), ranks...)
+	} else {
+		vs, shares, err = vss.CreateFrom(rand, round.Params().EC(), round.Threshold(), ui, flattenShareIDs(shareIDs))
+	}
 	if err != nil {
 		return round.WrapError(err, Pi)
 	}
 	round.save.Ks = ids
+	if round.Params().Weights() != nil {
+		round.save.WeightedKs = shareIDs
+	}
 
 	// security: the original u_i may be discarded
 	ui = zero // clears the secret data from memory
@@ -62,7 +83,13 @@ func (round *round1) Start() *tss.Error {
 	if err != nil {
 		return round.WrapError(err, Pi)
 	}
-	cmt := cmts.NewHashCommitment(pGFlat...)
+	if round.Params().ChainCodeGeneration() {
+		// commit to our chain code share together with the Vs; it is revealed with them in round 2
+		chainCodeShare := common.MustGetRandomIntFrom(rand, chainCodeBits)
+		round.temp.chainCodeShares[i] = chainCodeShare
+		pGFlat = append(pGFlat, chainCodeShare)
+	}
+	cmt := cmts.NewHashCommitmentFrom(rand, pGFlat...)
 
 	// 4. generate Paillier public key E_i, private key and proof
 	// 5-7. generate safe primes for ZKPs used later on
@@ -75,7 +102,11 @@ func (round *round1) Start() *tss.Error {
 	} else if round.save.LocalPreParams.ValidateWithProof() {
 		preParams = &round.save.LocalPreParams
 	} else {
-		preParams, err = GeneratePreParams(round.SafePrimeGenTimeout(), round.Concurrency())
+		ctx, cancel := context.WithTimeout(tss.ContextWithLogger(context.Background(), round.logger()), round.SafePrimeGenTimeout())
+		start := time.Now()
+		preParams, err = GeneratePreParamsWithContextAndRandom(ctx, rand, round.Concurrency())
+		cancel()
+		tss.ObservePreParams(round.Parameters, TaskName, round.number, time.Since(start), err)
 		if err != nil {
 			return round.WrapError(errors.New("pre-params generation failed"), Pi)
 		}
@@ -97,6 +128,31 @@ func (round *round1) Start() *tss.Error {
 	round.temp.ssid = round.getSSID()
 	contextI := common.AppendUint64ToBytesSlice(round.temp.ssid, uint64(i))
 
+	// for this P: SAVE
+	// - shareID
+	// and keep in temporary storage:
+	// - VSS Vs
+	// - our set of Shamir shares
+	round.save.ShareID = ids[i]
+	round.temp.vs = vs
+	round.temp.shares = shares
+	round.temp.shareIDs = shareIDs
+
+	// for this P: SAVE de-commitments, paillier keys for round 2
+	round.save.PaillierSK = preParams.PaillierSK
+	round.save.PaillierPKs[i] = &preParams.PaillierSK.PublicKey
+	round.temp.deCommitPolyG = cmt.D
+
+	if round.temp.leader != nil {
+		// a follower of a BatchLocalParty shares the leader's Paillier and NTilde keys, which the leader proves once
+		content := proto.Clone(round.temp.leader.kgRound1Messages[i].Content()).(*KGRound1Message)
+		content.Commitment = cmt.C.Bytes()
+		msg := newFollowerMessage(round.PartyID(), nil, content)
+		round.temp.kgRound1Messages[i] = msg
+		round.out <- msg
+		return nil
+	}
+
 	// generate the dlnproofs for keygen
 	h1i, h2i, alpha, beta, p, q, NTildei :=
 		preParams.H1i,
@@ -106,10 +162,10 @@ func (round *round1) Start() *tss.Error {
 		preParams.P,
 		preParams.Q,
 		preParams.NTildei
-	dlnProof1 := dlnproof.NewDLNProof(h1i, h2i, alpha, p, q, NTildei, round.temp.ssid)
-	dlnProof2 := dlnproof.NewDLNProof(h2i, h1i, beta, p, q, NTildei, round.temp.ssid)
+	dlnProof1 := dlnproof.NewDLNProofFrom(rand, h1i, h2i, alpha, p, q, NTildei, round.temp.ssid)
+	dlnProof2 := dlnproof.NewDLNProofFrom(rand, h2i, h1i, beta, p, q, NTildei, round.temp.ssid)
 
-	modProof := preParams.PaillierSK.ModProof(contextI)
+	modProof := preParams.PaillierSK.ModProofFrom(rand, contextI)
 
 	// NTildei = (2p+1) * (2q+1)
 	// phi(NTildei) = ((2p+1) - 1) * ((2q+1) - 1) = 2p * 2q
@@ -122,21 +178,7 @@ func (round *round1) Start() *tss.Error {
 	pkTilde := &paillier.PublicKey{N: NTildei}
 	skTilde := &paillier.PrivateKey{PublicKey: *pkTilde, LambdaN: lambdaNTilde, PhiN: phiNTilde}
 
-	modProofTilde := skTilde.ModProof(contextI)
-
-	// for this P: SAVE
-	// - shareID
-	// and keep in temporary storage:
-	// - VSS Vs
-	// - our set of Shamir shares
-	round.save.ShareID = ids[i]
-	round.temp.vs = vs
-	round.temp.shares = shares
-
-	// for this P: SAVE de-commitments, paillier keys for round 2
-	round.save.PaillierSK = preParams.PaillierSK
-	round.save.PaillierPKs[i] = &preParams.PaillierSK.PublicKey
-	round.temp.deCommitPolyG = cmt.D
+	modProofTilde := skTilde.ModProofFrom(rand, contextI)
 
 	round.temp.skTilde = skTilde
 
@@ -9,9 +9,12 @@ package keygen
 import (
 	"encoding/hex"
 	"errors"
+	"math/big"
 	"sync"
+	"time"
 
 	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/crypto/paillier"
 	"github.com/bnb-chain/tss-lib/tss"
 )
 
@@ -19,6 +22,8 @@ const (
 	paillierBitsLen = 2048
 )
 
+var proofCacheTag = []byte("tss-lib/ecdsa/keygen/proof-cache")
+
 func (round *round2) Start() *tss.Error {
 	if round.started {
 		return round.WrapError(errors.New("round already started"))
@@ -27,11 +32,7 @@ func (round *round2) Start() *tss.Error {
 	round.started = true
 	round.resetOK()
 
-	common.Logger.Debugf(
-		"%s Setting up DLN verification with concurrency level of %d",
-		round.PartyID(),
-		round.Concurrency(),
-	)
+	round.logger().Debug("setting up DLN verification", "concurrency", round.Concurrency())
 	verifier := NewProofVerifier(round.Concurrency())
 
 	i := round.PartyID().Index
@@ -42,6 +43,8 @@ func (round *round2) Start() *tss.Error {
 	dlnProof2FailCulprits := make([]*tss.PartyID, len(round.temp.kgRound1Messages))
 	modProofFailCulprits := make([]*tss.PartyID, len(round.temp.kgRound1Messages))
 	modProofTildeFailCulprits := make([]*tss.PartyID, len(round.temp.kgRound1Messages))
+	cache := round.Params().ProofCache()
+	cacheKeys := make([][]byte, len(round.temp.kgRound1Messages))
 	wg := new(sync.WaitGroup)
 	for j, msg := range round.temp.kgRound1Messages {
 		r1msg := msg.Content().(*KGRound1Message)
@@ -68,30 +71,45 @@ func (round *round2) Start() *tss.Error {
 		}
 		h1H2Map[h1JHex], h1H2Map[h2JHex] = struct{}{}, struct{}{}
 
+		if round.temp.leader != nil {
+			// the batch leader verified the same proofs over the same keys
+			continue
+		}
+		if cache != nil {
+			cacheKeys[j] = proofCacheKey(msg.GetFrom(), paillierPKj.N, NTildej, H1j, H2j)
+			if cache.Contains(cacheKeys[j]) {
+				round.logger().Debug("skipping the proofs verified in an earlier ceremony", "prover", msg.GetFrom().String())
+				continue
+			}
+		}
 		wg.Add(4)
 		_j := j
 		_msg := msg
 		contextJ := common.AppendUint64ToBytesSlice(round.temp.ssid, uint64(j))
 
-		verifier.VerifyDLNProof1(r1msg, H1j, H2j, NTildej, func(isValid bool) {
+		verifier.VerifyDLNProof1Timed(r1msg, H1j, H2j, NTildej, func(isValid bool, took time.Duration) {
+			round.observeProof(tss.ProofDLN, _msg.GetFrom(), took, isValid)
 			if !isValid {
 				dlnProof1FailCulprits[_j] = _msg.GetFrom()
 			}
 			wg.Done()
 		}, round.temp.ssid)
-		verifier.VerifyDLNProof2(r1msg, H2j, H1j, NTildej, func(isValid bool) {
+		verifier.VerifyDLNProof2Timed(r1msg, H2j, H1j, NTildej, func(isValid bool, took time.Duration) {
+			round.observeProof(tss.ProofDLN, _msg.GetFrom(), took, isValid)
 			if !isValid {
 				dlnProof2FailCulprits[_j] = _msg.GetFrom()
 			}
 			wg.Done()
 		}, round.temp.ssid)
-		verifier.VerifyModProof(r1msg, paillierPKj.N, func(isValid bool) {
+		verifier.VerifyModProofTimed(r1msg, paillierPKj.N, func(isValid bool, took time.Duration) {
+			round.observeProof(tss.ProofMod, _msg.GetFrom(), took, isValid)
 			if !isValid {
 				modProofFailCulprits[_j] = _msg.GetFrom()
 			}
 			wg.Done()
 		}, contextJ)
-		verifier.VerifyModProofTilde(r1msg, NTildej, func(isValid bool) {
+		verifier.VerifyModProofTildeTimed(r1msg, NTildej, func(isValid bool, took time.Duration) {
+			round.observeProof(tss.ProofMod, _msg.GetFrom(), took, isValid)
 			if !isValid {
 				modProofTildeFailCulprits[_j] = _msg.GetFrom()
 			}
@@ -109,6 +127,13 @@ func (round *round2) Start() *tss.Error {
 			return round.WrapError(errors.New("mod proof verification failed"), culprit)
 		}
 	}
+	if cache != nil {
+		for _, key := range cacheKeys {
+			if key != nil {
+				cache.Add(key)
+			}
+		}
+	}
 	// save NTilde_j, h1_j, h2_j, ...
 	for j, msg := range round.temp.kgRound1Messages {
 		if j == i {
@@ -121,26 +146,30 @@ func (round *round2) Start() *tss.Error {
 			r1msg.UnmarshalH2(),
 			r1msg.UnmarshalNTilde(),
 			r1msg.UnmarshalCommitment()
-		round.save.PaillierPKs[j] = paillierPK // used in round 4
+		round.save.PaillierPKs[j] = paillierPK // used in round 6
 		round.save.NTildej[j] = NTildej
 		round.save.H1j[j], round.save.H2j[j] = H1j, H2j
 		round.temp.KGCs[j] = KGC
 	}
 
 	// 5. p2p send share ij to Pj
-	shares := round.temp.shares
 	contextI := common.AppendUint64ToBytesSlice(round.temp.ssid, uint64(i))
 	for j, Pj := range round.Parties().IDs() {
 		// do not send to this Pj, but store for round 3
+		sharesj := round.sharesOf(j)
 		if j == i {
-			round.temp.kgRound2Message1s[j] = NewKGRound2Message1(Pj, round.PartyID(), shares[j], nil, nil)
+			round.temp.kgRound2Message1s[j] = NewKGRound2Message1(Pj, round.PartyID(), sharesj[0], nil, nil, sharesj[1:]...)
 			continue
 		}
-		H1j, H2j, NTildej := round.save.H1j[j], round.save.H2j[j], round.save.NTildej[j]
-		facProof := round.save.LocalPreParams.PaillierSK.FactorProof(NTildej, H1j, H2j, contextI)
-		facProofTilde := round.temp.skTilde.FactorProof(NTildej, H1j, H2j, contextI)
+		var facProof, facProofTilde *paillier.FactorProof
+		if round.temp.leader == nil {
+			H1j, H2j, NTildej := round.save.H1j[j], round.save.H2j[j], round.save.NTildej[j]
+			facProof = round.save.LocalPreParams.PaillierSK.FactorProofFrom(round.Params().Rand(), NTildej, H1j, H2j, contextI)
+			facProofTilde = round.temp.skTilde.FactorProofFrom(round.Params().Rand(), NTildej, H1j, H2j, contextI)
+		}
+		// a batch follower sends its share alone; the BatchLocalParty attaches the leader's factor proofs
 
-		r2msg1 := NewKGRound2Message1(Pj, round.PartyID(), shares[j], facProof, facProofTilde)
+		r2msg1 := NewKGRound2Message1(Pj, round.PartyID(), sharesj[0], facProof, facProofTilde, sharesj[1:]...)
 		round.out <- r2msg1
 	}
 
@@ -187,3 +216,10 @@ func (round *round2) NextRound() tss.Round {
 	round.started = false
 	return &round3{round}
 }
+
+// proofCacheKey binds a party's Paillier and NTilde parameters to its long-term identity. Its proofs were bound
+// to the identity too, through the SSID, when they were first verified, so another party presenting the same
+// parameters misses the cache and has to prove them, which it cannot do without their factorizations.
+func proofCacheKey(prover *tss.PartyID, N, NTilde, h1, h2 *big.Int) []byte {
+	return common.SHA512_256i_TAGGED(proofCacheTag, prover.KeyInt(), N, NTilde, h1, h2).Bytes()
+}
@@ -8,10 +8,9 @@ package keygen
 
 import (
 	"errors"
-	"math/big"
+	"time"
 
 	"github.com/hashicorp/go-multierror"
-	errors2 "github.com/pkg/errors"
 
 	"github.com/bnb-chain/tss-lib/common"
 	"github.com/bnb-chain/tss-lib/crypto"
@@ -31,28 +30,13 @@ func (round *round3) Start() *tss.Error {
 	Ps := round.Parties().IDs()
 	PIdx := round.PartyID().Index
 
-	// 1,9. calculate xi
-	xi := new(big.Int).Set(round.temp.shares[PIdx].Share)
-	for j := range Ps {
-		if j == PIdx {
-			continue
-		}
-		r2msg1 := round.temp.kgRound2Message1s[j].Content().(*KGRound2Message1)
-		share := r2msg1.UnmarshalShare()
-		xi = new(big.Int).Add(xi, share)
-	}
-	round.save.Xi = new(big.Int).Mod(xi, round.Params().EC().Params().N)
-
-	// 2-3.
-	Vc := make(vss.Vs, round.Threshold()+1)
-	for c := range Vc {
-		Vc[c] = round.temp.vs[c] // ours
-	}
+	round.temp.pjVs[PIdx] = round.temp.vs // ours
 
 	// 4-11.
 	type vssOut struct {
 		unWrappedErr error
 		pjVs         vss.Vs
+		badShare     bool
 	}
 	chs := make([]chan vssOut, len(Ps))
 	for i := range chs {
@@ -75,50 +59,80 @@ func (round *round3) Start() *tss.Error {
 			cmtDeCmt := commitments.HashCommitDecommit{C: KGCj, D: KGDj}
 			ok, flatPolyGs := cmtDeCmt.DeCommit()
 			if !ok || flatPolyGs == nil {
-				ch <- vssOut{errors.New("de-commitment verify failed"), nil}
+				ch <- vssOut{errors.New("de-commitment verify failed"), nil, false}
 				return
 			}
+			if round.Params().ChainCodeGeneration() {
+				if len(flatPolyGs) != 2*(round.Threshold()+1)+1 {
+					ch <- vssOut{errors.New("de-commitment does not hold a chain code share"), nil, false}
+					return
+				}
+				round.temp.chainCodeShares[j] = flatPolyGs[len(flatPolyGs)-1]
+				flatPolyGs = flatPolyGs[:len(flatPolyGs)-1]
+			}
 			PjVs, err := crypto.UnFlattenECPoints(round.Params().EC(), flatPolyGs)
 			if err != nil {
-				ch <- vssOut{err, nil}
+				ch <- vssOut{err, nil, false}
+				return
+			}
+			if len(PjVs) != round.Threshold()+1 {
+				ch <- vssOut{errors.New("de-committed vss Vs has the wrong length"), nil, false}
 				return
 			}
 			r2msg1 := round.temp.kgRound2Message1s[j].Content().(*KGRound2Message1)
-			PjShare := vss.Share{
-				Threshold: round.Threshold(),
-				ID:        round.PartyID().KeyInt(),
-				Share:     r2msg1.UnmarshalShare(),
+			// a bad share is not fatal here: we complain about Pj in this round and Pj must justify it in round 4
+			badShare := false
+			start := time.Now()
+			PjShares := r2msg1.UnmarshalShares()
+			if len(PjShares) != len(round.temp.shareIDs[PIdx]) {
+				ch <- vssOut{errors.New("got the wrong number of shares for our evaluation points"), nil, false}
+				return
 			}
-			if ok = PjShare.Verify(round.Params().EC(), round.Threshold(), PjVs); !ok {
-				ch <- vssOut{errors.New("vss verify failed"), nil}
+			for m, id := range round.temp.shareIDs[PIdx] {
+				PjShare := vss.Share{
+					Threshold: round.Threshold(),
+					ID:        id,
+					Share:     PjShares[m],
+					Rank:      round.Params().Rank(PIdx),
+				}
+				badShare = badShare || !PjShare.Verify(round.Params().EC(), round.Threshold(), PjVs)
+			}
+			round.observeProof(tss.ProofVSS, Ps[j], time.Since(start), !badShare)
+			if round.temp.leader != nil {
+				// the factor proofs are the batch leader's, verified by it
+				ch <- vssOut{nil, PjVs, badShare}
 				return
 			}
 			FacProof := r2msg1.UnmarshalFactorProof()
 			pkN := round.save.PaillierPKs[j].N
 			NTilde := round.save.LocalPreParams.NTildei
 			H1i, H2i := round.save.LocalPreParams.H1i, round.save.LocalPreParams.H2i
+			start = time.Now()
 			ok, err = FacProof.FactorVerify(pkN, NTilde, H1i, H2i, contextJ)
+			round.observeProof(tss.ProofFactor, Ps[j], time.Since(start), err == nil && ok)
 			if err != nil {
-				ch <- vssOut{err, nil}
+				ch <- vssOut{err, nil, false}
 				return
 			}
 			if !ok {
-				ch <- vssOut{errors.New("factor proof verify failed"), nil}
+				ch <- vssOut{errors.New("factor proof verify failed"), nil, false}
 				return
 			}
 			FacProofTilde := r2msg1.UnmarshalFactorProofTilde()
 			NTildej := round.save.NTildej[j]
+			start = time.Now()
 			ok, err = FacProofTilde.FactorVerify(NTildej, NTilde, H1i, H2i, contextJ)
+			round.observeProof(tss.ProofFactor, Ps[j], time.Since(start), err == nil && ok)
 			if err != nil {
-				ch <- vssOut{err, nil}
+				ch <- vssOut{err, nil, false}
 				return
 			}
 			if !ok {
-				ch <- vssOut{errors.New("factor proof verify failed"), nil}
+				ch <- vssOut{errors.New("factor proof verify failed"), nil, false}
 				return
 			}
 			// (9) handled above
-			ch <- vssOut{nil, PjVs}
+			ch <- vssOut{nil, PjVs, badShare}
 		}(j, chs[j])
 	}
 
@@ -147,67 +161,20 @@ func (round *round3) Start() *tss.Error {
 			return round.WrapError(multiErr, culprits...)
 		}
 	}
-	{
-		var err error
-		culprits := make([]*tss.PartyID, 0, len(Ps)) // who caused the error(s)
-		for j, Pj := range Ps {
-			if j == PIdx {
-				continue
-			}
-			// 10-11.
-			PjVs := vssResults[j].pjVs
-			for c := 0; c <= round.Threshold(); c++ {
-				Vc[c], err = Vc[c].Add(PjVs[c])
-				if err != nil {
-					culprits = append(culprits, Pj)
-				}
-			}
-		}
-		if len(culprits) > 0 {
-			return round.WrapError(errors.New("adding PjVs[c] to Vc[c] resulted in a point not on the curve"), culprits...)
-		}
-	}
 
-	// 12-16. compute Xj for each Pj
-	{
-		var err error
-		modQ := common.ModInt(round.Params().EC().Params().N)
-		culprits := make([]*tss.PartyID, 0, len(Ps)) // who caused the error(s)
-		bigXj := round.save.BigXj
-		for j := 0; j < round.PartyCount(); j++ {
-			Pj := round.Parties().IDs()[j]
-			kj := Pj.KeyInt()
-			BigXj := Vc[0]
-			z := new(big.Int).SetInt64(int64(1))
-			for c := 1; c <= round.Threshold(); c++ {
-				z = modQ.Mul(z, kj)
-				BigXj, err = BigXj.Add(Vc[c].ScalarMult(z))
-				if err != nil {
-					culprits = append(culprits, Pj)
-				}
-			}
-			bigXj[j] = BigXj
+	// BROADCAST complaints against every Pj whose share failed to verify (usually none)
+	accused := make([]int, 0, len(Ps))
+	for j, Pj := range Ps {
+		if j == PIdx {
+			continue
 		}
-		if len(culprits) > 0 {
-			return round.WrapError(errors.New("adding Vc[c].ScalarMult(z) to BigXj resulted in a point not on the curve"), culprits...)
+		round.temp.pjVs[j] = vssResults[j].pjVs
+		if vssResults[j].badShare {
+			round.logger().Warn("vss verify failed for the share; broadcasting a complaint", "dealer", Pj.String())
+			accused = append(accused, j)
 		}
-		round.save.BigXj = bigXj
 	}
-
-	// 17. compute and SAVE the ECDSA public key `y`
-	ecdsaPubKey, err := crypto.NewECPoint(round.Params().EC(), Vc[0].X(), Vc[0].Y())
-	if err != nil {
-		return round.WrapError(errors2.Wrapf(err, "public key is not on the curve"))
-	}
-	round.save.ECDSAPub = ecdsaPubKey
-
-	// PRINT public key & private share
-	common.Logger.Debugf("%s public key: %x", round.PartyID(), ecdsaPubKey)
-
-	// BROADCAST paillier proof for Pi
-	ki := round.PartyID().KeyInt()
-	proof := round.save.PaillierSK.Proof(ki, ecdsaPubKey)
-	r3msg := NewKGRound3Message(round.PartyID(), proof)
+	r3msg := NewKGRound3Message(round.PartyID(), accused)
 	round.temp.kgRound3Messages[PIdx] = r3msg
 	round.out <- r3msg
 	return nil
@@ -230,7 +197,7 @@ func (round *round3) Update() (bool, *tss.Error) {
 			ret = false
 			continue
 		}
-		// proof check is in round 4
+		// complaints are checked in round 4
 		round.ok[j] = true
 	}
 	return ret, nil
@@ -8,12 +8,14 @@ package keygen
 
 import (
 	"errors"
+	"fmt"
+	"math/big"
 
-	"github.com/bnb-chain/tss-lib/common"
-	"github.com/bnb-chain/tss-lib/crypto/paillier"
 	"github.com/bnb-chain/tss-lib/tss"
 )
 
+// round 4 is the justification phase of the Feldman VSS complaint protocol (as in GJKR/Pedersen DKG):
+// every dealer accused in round 3 must publicly reveal the share it sent to each complainer.
 func (round *round4) Start() *tss.Error {
 	if round.started {
 		return round.WrapError(errors.New("round already started"))
@@ -22,72 +24,72 @@ func (round *round4) Start() *tss.Error {
 	round.started = true
 	round.resetOK()
 
-	i := round.PartyID().Index
 	Ps := round.Parties().IDs()
-	PIDs := Ps.Keys()
-	ecdsaPub := round.save.ECDSAPub
+	PIdx := round.PartyID().Index
 
-	// 1-3. (concurrent)
-	// r3 messages are assumed to be available and != nil in this function
-	r3msgs := round.temp.kgRound3Messages
-	chs := make([]chan bool, len(r3msgs))
-	for i := range chs {
-		chs[i] = make(chan bool)
-	}
-	for j, msg := range round.temp.kgRound3Messages {
-		if j == i {
-			continue
-		}
+	// collect the complaints against each Pj; a malformed complaint is the complainer's fault
+	complaints := make([][]int, len(Ps))
+	for i, msg := range round.temp.kgRound3Messages {
 		r3msg := msg.Content().(*KGRound3Message)
-		go func(prf paillier.Proof, j int, ch chan<- bool) {
-			ppk := round.save.PaillierPKs[j]
-			ok, err := prf.Verify(ppk.N, PIDs[j], ecdsaPub)
-			if err != nil {
-				common.Logger.Error(round.WrapError(err, Ps[j]).Error())
-				ch <- false
-				return
+		seen := make(map[int]struct{}, len(r3msg.GetAccused()))
+		for _, j := range r3msg.UnmarshalAccused() {
+			if j < 0 || len(Ps) <= j || j == i {
+				return round.WrapError(fmt.Errorf("complaint against an invalid party index %d", j), Ps[i])
 			}
-			ch <- ok
-		}(r3msg.UnmarshalProofInts(), j, chs[j])
-	}
-
-	// consume unbuffered channels (end the goroutines)
-	for j, ch := range chs {
-		if j == i {
-			round.ok[j] = true
-			continue
+			if _, dup := seen[j]; dup {
+				return round.WrapError(fmt.Errorf("duplicate complaint against party index %d", j), Ps[i])
+			}
+			seen[j] = struct{}{}
+			complaints[j] = append(complaints[j], i)
 		}
-		round.ok[j] = <-ch
 	}
-	culprits := make([]*tss.PartyID, 0, len(Ps)) // who caused the error(s)
-	for j, ok := range round.ok {
-		if !ok {
-			culprits = append(culprits, Ps[j])
-			common.Logger.Warningf("paillier verify failed for party %s", Ps[j])
-			continue
-		}
-		common.Logger.Debugf("paillier verify passed for party %s", Ps[j])
+	round.temp.complaints = complaints
 
+	// BROADCAST the disputed shares if we were accused
+	if complainers := complaints[PIdx]; 0 < len(complainers) {
+		round.logger().Warn("accused; revealing the disputed shares", "complainers", len(complainers))
+		shares := make([]*big.Int, 0, len(complainers))
+		for _, c := range complainers {
+			for _, share := range round.sharesOf(c) {
+				shares = append(shares, share.Share)
+			}
+		}
+		r4msg := NewKGRound4Message(round.PartyID(), complainers, shares)
+		round.temp.kgRound4Messages[PIdx] = r4msg
+		round.out <- r4msg
 	}
-	if len(culprits) > 0 {
-		return round.WrapError(errors.New("paillier verify failed"), culprits...)
-	}
-
-	round.end <- *round.save
-
 	return nil
 }
 
 func (round *round4) CanAccept(msg tss.ParsedMessage) bool {
-	// not expecting any incoming messages in this round
+	if _, ok := msg.Content().(*KGRound4Message); ok {
+		return msg.IsBroadcast()
+	}
 	return false
 }
 
 func (round *round4) Update() (bool, *tss.Error) {
-	// not expecting any incoming messages in this round
-	return false, nil
+	ret := true
+	for j, msg := range round.temp.kgRound4Messages {
+		if round.ok[j] {
+			continue
+		}
+		// only the accused parties have something to send in this round
+		if len(round.temp.complaints[j]) == 0 {
+			round.ok[j] = true
+			continue
+		}
+		if msg == nil || !round.CanAccept(msg) {
+			ret = false
+			continue
+		}
+		// justification check is in round 5
+		round.ok[j] = true
+	}
+	return ret, nil
 }
 
 func (round *round4) NextRound() tss.Round {
-	return nil // finished!
+	round.started = false
+	return &round5{round}
 }
@@ -0,0 +1,252 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"errors"
+	"math/big"
+	"time"
+
+	errors2 "github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/crypto/vss"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+func (round *round5) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+	round.number = 5
+	round.started = true
+	round.resetOK()
+
+	Ps := round.Parties().IDs()
+	PIdx := round.PartyID().Index
+
+	// resolve the complaints: every party checks each revealed share against the accused Pj's de-committed Vs.
+	// an accused Pj that fails to justify any complaint is proven faulty; a justified complaint is dismissed and
+	// the complainer uses the revealed share instead of the one it was sent in round 2.
+	justified, culprits := round.resolveComplaints()
+	if len(culprits) > 0 {
+		return round.WrapError(errors.New("vss verify failed for a share revealed in the complaint phase"), culprits...)
+	}
+
+	// 1,9. calculate xi, at each of our evaluation points with weighted sharing
+	modQ := common.ModInt(round.Params().EC().Params().N)
+	ourShares := round.sharesOf(PIdx)
+	xis := make([]*big.Int, len(ourShares))
+	for m, share := range ourShares {
+		xis[m] = new(big.Int).Set(share.Share)
+	}
+	for j := range Ps {
+		if j == PIdx {
+			continue
+		}
+		shares := justified[j]
+		if shares == nil {
+			r2msg1 := round.temp.kgRound2Message1s[j].Content().(*KGRound2Message1)
+			shares = r2msg1.UnmarshalShares()
+		}
+		for m := range xis {
+			xis[m] = new(big.Int).Add(xis[m], shares[m])
+		}
+	}
+	for m := range xis {
+		xis[m].Mod(xis[m], round.Params().EC().Params().N)
+	}
+	round.save.Xi = xis[0]
+	if round.Params().Weights() != nil {
+		round.save.WeightedXi = xis
+	}
+
+	// 2-3.
+	Vc := make(vss.Vs, round.Threshold()+1)
+	for c := range Vc {
+		Vc[c] = round.temp.vs[c] // ours
+	}
+	{
+		var err error
+		culprits := make([]*tss.PartyID, 0, len(Ps)) // who caused the error(s)
+		for j, Pj := range Ps {
+			if j == PIdx {
+				continue
+			}
+			// 10-11.
+			PjVs := round.temp.pjVs[j]
+			for c := 0; c <= round.Threshold(); c++ {
+				Vc[c], err = Vc[c].Add(PjVs[c])
+				if err != nil {
+					culprits = append(culprits, Pj)
+				}
+			}
+		}
+		if len(culprits) > 0 {
+			return round.WrapError(errors.New("adding PjVs[c] to Vc[c] resulted in a point not on the curve"), culprits...)
+		}
+	}
+
+	// 12-16. compute Xj for each Pj, at each of its evaluation points with weighted sharing, or for the derivative
+	// of its rank with a hierarchical access structure
+	{
+		var err error
+		culprits := make([]*tss.PartyID, 0, len(Ps)) // who caused the error(s)
+		bigXj := round.save.BigXj
+		weightedBigXj := make([][]*crypto.ECPoint, len(Ps))
+		for j, Pj := range Ps {
+			weightedBigXj[j] = make([]*crypto.ECPoint, len(round.temp.shareIDs[j]))
+			if rank := round.Params().Rank(j); rank != 0 {
+				if weightedBigXj[j][0], err = Vc.EvaluateDerivative(round.Params().EC(), round.temp.shareIDs[j][0], rank); err != nil {
+					culprits = append(culprits, Pj)
+				}
+				bigXj[j] = weightedBigXj[j][0]
+				continue
+			}
+			for m, kj := range round.temp.shareIDs[j] {
+				BigXj := Vc[0]
+				z := new(big.Int).SetInt64(int64(1))
+				for c := 1; c <= round.Threshold(); c++ {
+					z = modQ.Mul(z, kj)
+					BigXj, err = BigXj.Add(Vc[c].ScalarMult(z))
+					if err != nil {
+						culprits = append(culprits, Pj)
+					}
+				}
+				weightedBigXj[j][m] = BigXj
+			}
+			bigXj[j] = weightedBigXj[j][0]
+		}
+		if len(culprits) > 0 {
+			return round.WrapError(errors.New("adding Vc[c].ScalarMult(z) to BigXj resulted in a point not on the curve"), culprits...)
+		}
+		round.save.BigXj = bigXj
+		if round.Params().Weights() != nil {
+			round.save.WeightedBigXj = weightedBigXj
+		}
+	}
+
+	// 17. compute and SAVE the ECDSA public key `y`
+	ecdsaPubKey, err := crypto.NewECPoint(round.Params().EC(), Vc[0].X(), Vc[0].Y())
+	if err != nil {
+		return round.WrapError(errors2.Wrapf(err, "public key is not on the curve"))
+	}
+	round.save.ECDSAPub = ecdsaPubKey
+
+	// PRINT public key & private share
+	round.logger().Debug("public key computed", "x", ecdsaPubKey.X(), "y", ecdsaPubKey.Y())
+
+	// every share was committed to before any was revealed, so one honest party makes the chain code uniform
+	if round.Params().ChainCodeGeneration() {
+		chainCode := common.SHA512_256i_TAGGED(chainCodeTag, round.temp.chainCodeShares...)
+		round.save.ChainCode = chainCode.FillBytes(make([]byte, chainCodeBits/8))
+	}
+
+	// BROADCAST paillier proof for Pi; a batch follower repeats the leader's proof of the shared key
+	var r5msg tss.ParsedMessage
+	if round.temp.leader != nil {
+		content := proto.Clone(round.temp.leader.kgRound5Messages[PIdx].Content()).(*KGRound5Message)
+		r5msg = newFollowerMessage(round.PartyID(), nil, content)
+	} else {
+		ki := round.PartyID().KeyInt()
+		proof := round.save.PaillierSK.Proof(ki, ecdsaPubKey)
+		r5msg = NewKGRound5Message(round.PartyID(), proof)
+	}
+	round.temp.kgRound5Messages[PIdx] = r5msg
+	round.out <- r5msg
+	return nil
+}
+
+// resolveComplaints checks the round 4 justifications against the round 3 complaints.
+// It returns the shares revealed to this party, indexed by dealer, and the dealers proven faulty.
+// With weighted sharing a dealer reveals a complainer's shares at all of its evaluation points.
+func (round *round5) resolveComplaints() (justified [][]*big.Int, culprits []*tss.PartyID) {
+	Ps := round.Parties().IDs()
+	PIdx := round.PartyID().Index
+	justified = make([][]*big.Int, len(Ps))
+	for j, complainers := range round.temp.complaints {
+		if len(complainers) == 0 {
+			continue
+		}
+		r4msg := round.temp.kgRound4Messages[j].Content().(*KGRound4Message)
+		revealed := make(map[int][]*big.Int, len(complainers))
+		shares := r4msg.UnmarshalShares()
+		faulty := false
+		for _, c := range r4msg.UnmarshalComplainers() {
+			if c < 0 || len(Ps) <= c || len(shares) < len(round.temp.shareIDs[c]) {
+				faulty = true
+				break
+			}
+			revealed[c], shares = shares[:len(round.temp.shareIDs[c])], shares[len(round.temp.shareIDs[c]):]
+		}
+		faulty = faulty || len(shares) != 0 || len(revealed) != len(complainers)
+		start := time.Now()
+		for _, c := range complainers {
+			if faulty {
+				break
+			}
+			cShares, ok := revealed[c]
+			if !ok {
+				faulty = true
+				break
+			}
+			for m, id := range round.temp.shareIDs[c] {
+				PjShare := vss.Share{
+					Threshold: round.Threshold(),
+					ID:        id,
+					Share:     cShares[m],
+					Rank:      round.Params().Rank(c),
+				}
+				if !PjShare.Verify(round.Params().EC(), round.Threshold(), round.temp.pjVs[j]) {
+					faulty = true
+					break
+				}
+			}
+		}
+		round.observeProof(tss.ProofVSS, Ps[j], time.Since(start), !faulty)
+		if faulty {
+			round.logger().Warn("the dealer failed to justify its shares in the complaint phase", "dealer", Ps[j].String())
+			culprits = append(culprits, Ps[j])
+			continue
+		}
+		round.logger().Warn("dismissed the complaints against the dealer", "dealer", Ps[j].String(), "complainers", len(complainers))
+		if j != PIdx {
+			justified[j] = revealed[PIdx]
+		}
+	}
+	return
+}
+
+func (round *round5) CanAccept(msg tss.ParsedMessage) bool {
+	if _, ok := msg.Content().(*KGRound5Message); ok {
+		return msg.IsBroadcast()
+	}
+	return false
+}
+
+func (round *round5) Update() (bool, *tss.Error) {
+	ret := true
+	for j, msg := range round.temp.kgRound5Messages {
+		if round.ok[j] {
+			continue
+		}
+		if msg == nil || !round.CanAccept(msg) {
+			ret = false
+			continue
+		}
+		// proof check is in round 6
+		round.ok[j] = true
+	}
+	return ret, nil
+}
+
+func (round *round5) NextRound() tss.Round {
+	round.started = false
+	return &round6{round}
+}
@@ -0,0 +1,104 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"errors"
+	"time"
+
+	"github.com/bnb-chain/tss-lib/crypto/paillier"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+func (round *round6) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+	round.number = 6
+	round.started = true
+	round.resetOK()
+
+	i := round.PartyID().Index
+	Ps := round.Parties().IDs()
+	PIDs := Ps.Keys()
+	ecdsaPub := round.save.ECDSAPub
+
+	if round.temp.leader != nil {
+		// the Paillier keys are the batch leader's, verified by it
+		for j := range round.ok {
+			round.ok[j] = true
+		}
+		round.end <- *round.save
+		return nil
+	}
+
+	// 1-3. (concurrent)
+	// r5 messages are assumed to be available and != nil in this function
+	r5msgs := round.temp.kgRound5Messages
+	chs := make([]chan bool, len(r5msgs))
+	for i := range chs {
+		chs[i] = make(chan bool)
+	}
+	for j, msg := range round.temp.kgRound5Messages {
+		if j == i {
+			continue
+		}
+		r5msg := msg.Content().(*KGRound5Message)
+		go func(prf paillier.Proof, j int, ch chan<- bool) {
+			ppk := round.save.PaillierPKs[j]
+			start := time.Now()
+			ok, err := prf.Verify(ppk.N, PIDs[j], ecdsaPub)
+			round.observeProof(tss.ProofPaillier, Ps[j], time.Since(start), err == nil && ok)
+			if err != nil {
+				round.logger().Error("paillier proof verification failed", "prover", Ps[j].String(), "err", err)
+				ch <- false
+				return
+			}
+			ch <- ok
+		}(r5msg.UnmarshalProofInts(), j, chs[j])
+	}
+
+	// consume unbuffered channels (end the goroutines)
+	for j, ch := range chs {
+		if j == i {
+			round.ok[j] = true
+			continue
+		}
+		round.ok[j] = <-ch
+	}
+	culprits := make([]*tss.PartyID, 0, len(Ps)) // who caused the error(s)
+	for j, ok := range round.ok {
+		if !ok {
+			culprits = append(culprits, Ps[j])
+			round.logger().Warn("paillier verify failed", "prover", Ps[j].String())
+			continue
+		}
+		round.logger().Debug("paillier verify passed", "prover", Ps[j].String())
+
+	}
+	if len(culprits) > 0 {
+		return round.WrapError(errors.New("paillier verify failed"), culprits...)
+	}
+
+	round.end <- *round.save
+
+	return nil
+}
+
+func (round *round6) CanAccept(msg tss.ParsedMessage) bool {
+	// not expecting any incoming messages in this round
+	return false
+}
+
+func (round *round6) Update() (bool, *tss.Error) {
+	// not expecting any incoming messages in this round
+	return false, nil
+}
+
+func (round *round6) NextRound() tss.Round {
+	return nil // finished!
+}
@@ -8,6 +8,7 @@ package keygen
 
 import (
 	"math/big"
+	"time"
 
 	"github.com/bnb-chain/tss-lib/common"
 	"github.com/bnb-chain/tss-lib/tss"
@@ -40,6 +41,12 @@ type (
 	round4 struct {
 		*round3
 	}
+	round5 struct {
+		*round4
+	}
+	round6 struct {
+		*round5
+	}
 )
 
 var (
@@ -47,6 +54,8 @@ var (
 	_ tss.Round = (*round2)(nil)
 	_ tss.Round = (*round3)(nil)
 	_ tss.Round = (*round4)(nil)
+	_ tss.Round = (*round5)(nil)
+	_ tss.Round = (*round6)(nil)
 )
 
 // ----- //
@@ -59,6 +68,16 @@ func (round *base) RoundNumber() int {
 	return round.number
 }
 
+// observeProof reports the verification of a proof by `prover` to the observer of the parameters
+func (round *base) observeProof(proof string, prover *tss.PartyID, took time.Duration, valid bool) {
+	tss.ObserveProof(round.Parameters, TaskName, round.number, proof, prover, took, valid)
+}
+
+// logger returns the logger of the parameters with the task and round fields
+func (round *base) logger() tss.Logger {
+	return round.Logger().With(tss.LogKeyTask, TaskName, tss.LogKeyRound, round.number)
+}
+
 // CanProceed is inherited by other rounds
 func (round *base) CanProceed() bool {
 	if !round.started {
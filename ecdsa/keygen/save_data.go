@@ -11,7 +11,10 @@ import (
 	"errors"
 	"math/big"
 
+	"github.com/btcsuite/btcd/chaincfg"
+
 	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/crypto/ckd"
 	"github.com/bnb-chain/tss-lib/crypto/paillier"
 	"github.com/bnb-chain/tss-lib/tss"
 )
@@ -28,6 +31,8 @@ type (
 	LocalSecrets struct {
 		// secret fields (not shared, but stored locally)
 		Xi, ShareID *big.Int // xi, kj
+		// with weighted sharing, the shares at each of our evaluation points; WeightedXi[0] == Xi
+		WeightedXi []*big.Int
 	}
 
 	// Everything in LocalPartySaveData is saved locally to user's HD when done
@@ -47,6 +52,18 @@ type (
 
 		// used for test assertions (may be discarded)
 		ECDSAPub *crypto.ECPoint // y
+
+		// BIP-32 chain code of ECDSAPub, set when keygen ran with chain code generation
+		ChainCode []byte
+
+		// with weighted sharing, the evaluation points of each Pj and the public shares at them;
+		// WeightedKs[j][0] == Ks[j] and WeightedBigXj[j][0] == BigXj[j]. nil for an unweighted key
+		WeightedKs    [][]*big.Int
+		WeightedBigXj [][]*crypto.ECPoint
+
+		// with a hierarchical access structure, the rank of each Pj: its share Xj and BigXj are of the
+		// Ranks[j]-th derivative of the polynomial. nil for a flat threshold key
+		Ranks []int
 	}
 )
 
@@ -84,6 +101,14 @@ func BuildLocalSaveDataSubset(sourceData LocalPartySaveData, sortedIDs tss.Sorte
 	newData.LocalPreParams = sourceData.LocalPreParams
 	newData.LocalSecrets = sourceData.LocalSecrets
 	newData.ECDSAPub = sourceData.ECDSAPub
+	newData.ChainCode = sourceData.ChainCode
+	if sourceData.IsWeighted() {
+		newData.WeightedKs = make([][]*big.Int, sortedIDs.Len())
+		newData.WeightedBigXj = make([][]*crypto.ECPoint, sortedIDs.Len())
+	}
+	if sourceData.IsHierarchical() {
+		newData.Ranks = make([]int, sortedIDs.Len())
+	}
 	for j, id := range sortedIDs {
 		savedIdx, ok := keysToIndices[hex.EncodeToString(id.Key)]
 		if !ok {
@@ -95,6 +120,54 @@ func BuildLocalSaveDataSubset(sourceData LocalPartySaveData, sortedIDs tss.Sorte
 		newData.H2j[j] = sourceData.H2j[savedIdx]
 		newData.BigXj[j] = sourceData.BigXj[savedIdx]
 		newData.PaillierPKs[j] = sourceData.PaillierPKs[savedIdx]
+		if sourceData.IsWeighted() {
+			newData.WeightedKs[j] = sourceData.WeightedKs[savedIdx]
+			newData.WeightedBigXj[j] = sourceData.WeightedBigXj[savedIdx]
+		}
+		if sourceData.IsHierarchical() {
+			newData.Ranks[j] = sourceData.Ranks[savedIdx]
+		}
 	}
 	return newData
 }
+
+// IsWeighted reports whether the key was generated with weighted sharing, see tss.NewWeightedParameters.
+func (save LocalPartySaveData) IsWeighted() bool {
+	return save.WeightedKs != nil
+}
+
+// Weights returns the number of shares of each Pj: the weights to sign with. It is nil for an unweighted key.
+func (save LocalPartySaveData) Weights() []int {
+	if !save.IsWeighted() {
+		return nil
+	}
+	weights := make([]int, len(save.WeightedKs))
+	for j, ksj := range save.WeightedKs {
+		weights[j] = len(ksj)
+	}
+	return weights
+}
+
+// IsHierarchical reports whether the key was generated with a hierarchical access structure, see
+// tss.NewHierarchicalParameters. Its Ranks are the ranks to sign with.
+func (save LocalPartySaveData) IsHierarchical() bool {
+	return save.Ranks != nil
+}
+
+// ExtendedPublicKey returns the BIP-32 master extended public key of the keygen output; its String() is the xpub.
+// It requires the chain code produced by keygen with tss.Parameters.SetChainCodeGeneration.
+func (save LocalPartySaveData) ExtendedPublicKey() (*ckd.ExtendedKey, error) {
+	return save.ExtendedPublicKeyFor(&chaincfg.MainNetParams, ckd.ScriptP2PKH)
+}
+
+// ExtendedPublicKeyFor is ExtendedPublicKey with the version bytes of `net` and `script`, e.g. a tpub on testnet or
+// a zpub for native segwit wallets.
+func (save LocalPartySaveData) ExtendedPublicKeyFor(net *chaincfg.Params, script ckd.ScriptType) (*ckd.ExtendedKey, error) {
+	if save.ECDSAPub == nil {
+		return nil, errors.New("the save data holds no public key")
+	}
+	if len(save.ChainCode) != chainCodeBits/8 {
+		return nil, errors.New("the save data holds no chain code; run keygen with chain code generation")
+	}
+	return ckd.NewMasterPublicKey(save.ECDSAPub.ToECDSAPubKey(), save.ChainCode, net, script)
+}
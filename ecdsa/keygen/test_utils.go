@@ -25,6 +25,8 @@ import (
 const (
 	// To change these parameters, you must first delete the text fixture files in test/_fixtures/ and then run the keygen test alone.
 	// Then the signing tests will work with the new n, t configuration using the newly written fixture files.
+	// Fixtures for other n, t or curves can instead be written to another directory with cmd/tss-fixtures and
+	// loaded with LoadKeygenTestFixturesFromDir.
 	TestParticipants = test.TestParticipants
 	TestThreshold    = test.TestParticipants / 2
 )
@@ -34,13 +36,29 @@ const (
 )
 
 func LoadKeygenTestFixtures(qty int, optionalStart ...int) ([]LocalPartySaveData, tss.SortedPartyIDs, error) {
+	keys, sortedPIDs, err := LoadKeygenTestFixturesFromDir(testFixtureDir(), qty, optionalStart...)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, key := range keys {
+		for _, kbxj := range key.BigXj {
+			kbxj.SetCurve(tss.S256())
+		}
+		key.ECDSAPub.SetCurve(tss.S256())
+	}
+	return keys, sortedPIDs, nil
+}
+
+// LoadKeygenTestFixturesFromDir loads the fixtures of parties `optionalStart` to `qty`-1 from `dir`, e.g. those
+// written by the test/fixtures generator. The points keep the curve named in the files, which must be registered.
+func LoadKeygenTestFixturesFromDir(dir string, qty int, optionalStart ...int) ([]LocalPartySaveData, tss.SortedPartyIDs, error) {
 	keys := make([]LocalPartySaveData, 0, qty)
 	start := 0
 	if 0 < len(optionalStart) {
 		start = optionalStart[0]
 	}
 	for i := start; i < qty; i++ {
-		fixtureFilePath := makeTestFixtureFilePath(i)
+		fixtureFilePath := TestFixtureFilePath(dir, i)
 		bz, err := ioutil.ReadFile(fixtureFilePath)
 		if err != nil {
 			return nil, nil, errors.Wrapf(err,
@@ -53,10 +71,6 @@ func LoadKeygenTestFixtures(qty int, optionalStart ...int) ([]LocalPartySaveData
 				"could not unmarshal fixture data for party %d located at: %s",
 				i, fixtureFilePath)
 		}
-		for _, kbxj := range key.BigXj {
-			kbxj.SetCurve(tss.S256())
-		}
-		key.ECDSAPub.SetCurve(tss.S256())
 		keys = append(keys, key)
 	}
 	partyIDs := make(tss.UnSortedPartyIDs, len(keys))
@@ -120,9 +134,17 @@ func LoadNTildeH1H2FromTestFixture(idx int) (NTildei, h1i, h2i *big.Int, err err
 	return
 }
 
+// TestFixtureFilePath returns the path of the fixture of the party at `partyIndex` in `dir`.
+func TestFixtureFilePath(dir string, partyIndex int) string {
+	return filepath.Join(dir, fmt.Sprintf(testFixtureFileFormat, partyIndex))
+}
+
 func makeTestFixtureFilePath(partyIndex int) string {
+	return TestFixtureFilePath(testFixtureDir(), partyIndex)
+}
+
+func testFixtureDir() string {
 	_, callerFileName, _, _ := runtime.Caller(0)
 	srcDirName := filepath.Dir(callerFileName)
-	fixtureDirName := fmt.Sprintf(testFixtureDirFormat, srcDirName)
-	return fmt.Sprintf("%s/"+testFixtureFileFormat, fixtureDirName, partyIndex)
+	return fmt.Sprintf(testFixtureDirFormat, srcDirName)
 }
@@ -9,11 +9,14 @@ package keygen
 import (
 	"errors"
 	"math/big"
+	"time"
 
 	"github.com/bnb-chain/tss-lib/crypto/dlnproof"
 	"github.com/bnb-chain/tss-lib/crypto/paillier"
 )
 
+// ProofVerifier runs proof verifications on a bounded number of goroutines. The onDone of the ...Timed methods also
+// receives the time the verification took, excluding the wait for a free goroutine.
 type ProofVerifier struct {
 	semaphore chan interface{}
 }
@@ -45,87 +48,133 @@ func (pv *ProofVerifier) VerifyDLNProof1(
 	h1, h2, n *big.Int,
 	onDone func(bool),
 	session ...[]byte,
+) {
+	pv.VerifyDLNProof1Timed(m, h1, h2, n, untimed(onDone), session...)
+}
+
+func (pv *ProofVerifier) VerifyDLNProof2(
+	m dlnMessage,
+	h1, h2, n *big.Int,
+	onDone func(bool),
+	session ...[]byte,
+) {
+	pv.VerifyDLNProof2Timed(m, h1, h2, n, untimed(onDone), session...)
+}
+
+func (pv *ProofVerifier) VerifyModProof(
+	m modMessage,
+	N *big.Int,
+	onDone func(bool),
+	session ...[]byte,
+) {
+	pv.VerifyModProofTimed(m, N, untimed(onDone), session...)
+}
+
+func (pv *ProofVerifier) VerifyModProofTilde(
+	m modMessage,
+	N *big.Int,
+	onDone func(bool),
+	session ...[]byte,
+) {
+	pv.VerifyModProofTildeTimed(m, N, untimed(onDone), session...)
+}
+
+func (pv *ProofVerifier) VerifyDLNProof1Timed(
+	m dlnMessage,
+	h1, h2, n *big.Int,
+	onDone func(isValid bool, took time.Duration),
+	session ...[]byte,
 ) {
 	pv.semaphore <- struct{}{}
 	go func() {
 		defer func() { <-pv.semaphore }()
+		start := time.Now()
 
 		dlnProof, err := m.UnmarshalDLNProof1()
 		if err != nil {
-			onDone(false)
+			onDone(false, time.Since(start))
 			return
 		}
 
-		onDone(dlnProof.Verify(h1, h2, n, session...))
+		onDone(dlnProof.Verify(h1, h2, n, session...), time.Since(start))
 	}()
 }
 
-func (pv *ProofVerifier) VerifyDLNProof2(
+func (pv *ProofVerifier) VerifyDLNProof2Timed(
 	m dlnMessage,
 	h1, h2, n *big.Int,
-	onDone func(bool),
+	onDone func(isValid bool, took time.Duration),
 	session ...[]byte,
 ) {
 	pv.semaphore <- struct{}{}
 	go func() {
 		defer func() { <-pv.semaphore }()
+		start := time.Now()
 
 		dlnProof, err := m.UnmarshalDLNProof2()
 		if err != nil {
-			onDone(false)
+			onDone(false, time.Since(start))
 			return
 		}
 
-		onDone(dlnProof.Verify(h1, h2, n, session...))
+		onDone(dlnProof.Verify(h1, h2, n, session...), time.Since(start))
 	}()
 }
 
-func (pv *ProofVerifier) VerifyModProof(
+func (pv *ProofVerifier) VerifyModProofTimed(
 	m modMessage,
 	N *big.Int,
-	onDone func(bool),
+	onDone func(isValid bool, took time.Duration),
 	session ...[]byte,
 ) {
 	pv.semaphore <- struct{}{}
 	go func() {
 		defer func() { <-pv.semaphore }()
+		start := time.Now()
 
 		modProof, err := m.UnmarshalModProof()
 		if err != nil {
-			onDone(false)
+			onDone(false, time.Since(start))
 			return
 		}
 
 		ok, err2 := modProof.ModVerify(N, session...)
 		if err2 != nil {
-			onDone(false)
+			onDone(false, time.Since(start))
 			return
 		}
-		onDone(ok)
+		onDone(ok, time.Since(start))
 	}()
 }
 
-func (pv *ProofVerifier) VerifyModProofTilde(
+func (pv *ProofVerifier) VerifyModProofTildeTimed(
 	m modMessage,
 	N *big.Int,
-	onDone func(bool),
+	onDone func(isValid bool, took time.Duration),
 	session ...[]byte,
 ) {
 	pv.semaphore <- struct{}{}
 	go func() {
 		defer func() { <-pv.semaphore }()
+		start := time.Now()
 
 		modProof, err := m.UnmarshalModProofTilde()
 		if err != nil {
-			onDone(false)
+			onDone(false, time.Since(start))
 			return
 		}
 
 		ok, err2 := modProof.ModVerify(N, session...)
 		if err2 != nil {
-			onDone(false)
+			onDone(false, time.Since(start))
 			return
 		}
-		onDone(ok)
+		onDone(ok, time.Since(start))
 	}()
 }
+
+func untimed(onDone func(bool)) func(bool, time.Duration) {
+	return func(isValid bool, _ time.Duration) {
+		onDone(isValid)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"math/big"
 	"runtime"
 	"testing"
+	"time"
 
 	"github.com/bnb-chain/tss-lib/common"
 	"github.com/bnb-chain/tss-lib/crypto/dlnproof"
@@ -132,6 +133,34 @@ func TestVerifyDLNProof1_Success(t *testing.T) {
 	}
 }
 
+func TestVerifyDLNProof1Timed_Success(t *testing.T) {
+	preParams, alpha, tt := prepareProofT(t)
+	message := &KGRound1Message{
+		Dlnproof_1: &KGRound1Message_DLNProof{
+			Alpha: alpha,
+			T:     tt,
+		},
+	}
+
+	verifier := NewProofVerifier(runtime.GOMAXPROCS(0))
+
+	resultChan := make(chan bool)
+	tookChan := make(chan time.Duration, 1)
+
+	verifier.VerifyDLNProof1Timed(message, preParams.H1i, preParams.H2i, preParams.NTildei, func(result bool, took time.Duration) {
+		tookChan <- took
+		resultChan <- result
+	})
+
+	success := <-resultChan
+	if !success {
+		t.Fatal("expected positive verification")
+	}
+	if took := <-tookChan; took <= 0 {
+		t.Fatalf("expected the verification time, got %s", took)
+	}
+}
+
 func TestVerifyDLNProof1_MalformedMessage1(t *testing.T) {
 	preParams, alpha, tt := prepareProofT(t)
 	message := &KGRound1Message{
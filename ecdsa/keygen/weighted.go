@@ -0,0 +1,50 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/crypto/vss"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+var weightedShareIDTag = []byte("tss-lib/ecdsa/keygen/weighted-share-id")
+
+// shareIDsOf returns the VSS evaluation points of every party, by index. A party's first point is its key, so
+// an unweighted keygen shares at the keys as before; a party of weight w gets w-1 further points derived from it.
+func shareIDsOf(params *tss.Parameters) [][]*big.Int {
+	q := params.EC().Params().N
+	ids := make([][]*big.Int, params.PartyCount())
+	for j, Pj := range params.Parties().IDs() {
+		ids[j] = make([]*big.Int, params.Weight(j))
+		ids[j][0] = Pj.KeyInt()
+		for m := 1; m < len(ids[j]); m++ {
+			id := common.SHA512_256i_TAGGED(weightedShareIDTag, Pj.KeyInt(), big.NewInt(int64(m)))
+			ids[j][m] = id.Mod(id, q)
+		}
+	}
+	return ids
+}
+
+func flattenShareIDs(ids [][]*big.Int) []*big.Int {
+	flat := make([]*big.Int, 0, len(ids))
+	for _, idsj := range ids {
+		flat = append(flat, idsj...)
+	}
+	return flat
+}
+
+// sharesOf returns our shares for the evaluation points of Pj
+func (round *base) sharesOf(j int) vss.Shares {
+	offset := 0
+	for _, idsj := range round.temp.shareIDs[:j] {
+		offset += len(idsj)
+	}
+	return round.temp.shares[offset : offset+len(round.temp.shareIDs[j])]
+}
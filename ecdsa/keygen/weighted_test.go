@@ -0,0 +1,55 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/crypto/vss"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+func TestShareIDsOf(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(3)
+	ctx := tss.NewPeerContext(pIDs)
+
+	unweighted := shareIDsOf(tss.NewParameters(tss.S256(), ctx, pIDs[0], len(pIDs), 1))
+	assert.Equal(t, [][]*big.Int{{pIDs[0].KeyInt()}, {pIDs[1].KeyInt()}, {pIDs[2].KeyInt()}}, unweighted)
+
+	ids := shareIDsOf(tss.NewWeightedParameters(tss.S256(), ctx, pIDs[0], []int{3, 1, 2}, 3))
+	for j, w := range []int{3, 1, 2} {
+		if assert.Len(t, ids[j], w) {
+			assert.Equal(t, pIDs[j].KeyInt(), ids[j][0], "a party's first evaluation point is its key")
+		}
+	}
+	_, err := vss.CheckIndexes(tss.S256(), flattenShareIDs(ids))
+	assert.NoError(t, err, "the evaluation points must be distinct and non-zero")
+}
+
+func TestBuildLocalSaveDataSubsetWeighted(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(3)
+	source := NewLocalPartySaveData(len(pIDs))
+	source.WeightedKs = make([][]*big.Int, len(pIDs))
+	source.WeightedBigXj = make([][]*crypto.ECPoint, len(pIDs))
+	for j, id := range pIDs {
+		source.Ks[j] = id.KeyInt()
+		source.WeightedKs[j] = make([]*big.Int, j+1)
+		source.WeightedBigXj[j] = make([]*crypto.ECPoint, j+1)
+		for m := range source.WeightedKs[j] {
+			source.WeightedKs[j][m] = big.NewInt(int64(10*j + m + 1))
+			source.WeightedBigXj[j][m] = crypto.ScalarBaseMult(tss.S256(), source.WeightedKs[j][m])
+		}
+	}
+	subset := BuildLocalSaveDataSubset(source, tss.SortedPartyIDs{pIDs[0], pIDs[2]})
+	assert.Equal(t, []int{1, 3}, subset.Weights())
+	assert.Equal(t, source.WeightedKs[2], subset.WeightedKs[1])
+	assert.Equal(t, source.WeightedBigXj[2], subset.WeightedBigXj[1])
+}
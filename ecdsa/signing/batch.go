@@ -0,0 +1,344 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+var batchNonceTag = []byte("tss-lib/ecdsa/signing/batch")
+
+// Implements Party
+// Implements Stringer
+var _ tss.Party = (*BatchLocalParty)(nil)
+var _ fmt.Stringer = (*BatchLocalParty)(nil)
+var _ tss.Round = (*batchRound)(nil)
+
+type (
+	// BatchLocalParty signs a vector of messages in a single ceremony. Every message gets its own k, gamma and
+	// MtA instances, but the round messages of all of them travel together in one SignBatchMessage per round
+	// and recipient, so the ceremony costs the network round trips of a single signature. The signatures are
+	// delivered together, in message order, on `end`. It is a party type of its own rather than a mode of
+	// LocalParty, which keeps its constructors, messages and `end` channel for single signatures; each message of
+	// the batch is signed by an inner LocalParty.
+	BatchLocalParty struct {
+		*tss.BaseParty
+		params *tss.Parameters
+
+		items   []*LocalParty
+		itemOut []chan tss.Message
+		itemEnd []chan common.SignatureData
+
+		// outbound messaging
+		out chan<- tss.Message
+		end chan<- []*common.SignatureData
+	}
+
+	// batchRound runs the same round of every batched message in lockstep
+	batchRound struct {
+		party  *BatchLocalParty
+		rounds []tss.Round
+		number int
+	}
+
+	// BatchItemError is the cause of a *tss.Error returned by a BatchLocalParty. Index is the position of the
+	// message whose signing failed; the *tss.Error's culprits are the culprits for that message.
+	BatchItemError struct {
+		Index int
+		Err   error
+	}
+)
+
+func (e *BatchItemError) Error() string {
+	return fmt.Sprintf("batch message %d: %v", e.Index, e.Err)
+}
+
+func (e *BatchItemError) Unwrap() error {
+	return e.Err
+}
+
+// NewBatchLocalParty returns a party that signs every message in `msgs` with `key`. All signers must pass the
+// same messages in the same order and the same fullBytesLen, which applies to every message. Start fails when
+// `msgs` is empty.
+func NewBatchLocalParty(
+	msgs []*big.Int,
+	params *tss.Parameters,
+	key keygen.LocalPartySaveData,
+	out chan<- tss.Message,
+	end chan<- []*common.SignatureData,
+	fullBytesLen ...int,
+) tss.Party {
+	return NewBatchLocalPartyWithKDD(msgs, params, key, nil, out, end, fullBytesLen...)
+}
+
+// NewBatchLocalPartyWithKDD is NewBatchLocalParty with a key derivation delta for HD support, applied to every
+// message.
+func NewBatchLocalPartyWithKDD(
+	msgs []*big.Int,
+	params *tss.Parameters,
+	key keygen.LocalPartySaveData,
+	keyDerivationDelta *big.Int,
+	out chan<- tss.Message,
+	end chan<- []*common.SignatureData,
+	fullBytesLen ...int,
+) tss.Party {
+	partyCount := len(params.Parties().IDs())
+	p := &BatchLocalParty{
+		BaseParty: new(tss.BaseParty),
+		params:    params,
+		items:     make([]*LocalParty, len(msgs)),
+		itemOut:   make([]chan tss.Message, len(msgs)),
+		itemEnd:   make([]chan common.SignatureData, len(msgs)),
+		out:       out,
+		end:       end,
+	}
+	for i, msg := range msgs {
+		// each message runs under its own session nonce, derived in Start, so that its proofs cannot be
+		// replayed for another message of the batch
+		itemParams := *params
+		p.itemOut[i] = make(chan tss.Message, partyCount)
+		p.itemEnd[i] = make(chan common.SignatureData, 1)
+		p.items[i] = NewLocalPartyWithKDD(msg, &itemParams, key, keyDerivationDelta, p.itemOut[i], p.itemEnd[i], fullBytesLen...).(*LocalParty)
+	}
+	return p
+}
+
+func (p *BatchLocalParty) FirstRound() tss.Round {
+	rounds := make([]tss.Round, len(p.items))
+	for i, item := range p.items {
+		rounds[i] = item.FirstRound()
+	}
+	return &batchRound{party: p, rounds: rounds, number: 1}
+}
+
+func (p *BatchLocalParty) Start() *tss.Error {
+	return tss.BaseStart(p, TaskName, func(round tss.Round) *tss.Error {
+		batch, ok := round.(*batchRound)
+		if !ok {
+			return round.WrapError(errors.New("unable to Start(). party is in an unexpected round"))
+		}
+		if len(p.items) == 0 {
+			return round.WrapError(errors.New("batch signing requires at least one message"))
+		}
+		nonce := p.params.SessionNonce()
+		if nonce == nil || nonce.Sign() <= 0 {
+			return round.WrapError(errors.New("batch signing requires tss.Parameters.SetSessionNonce(<unique positive per-ceremony nonce>) before Start"))
+		}
+		for i, item := range p.items {
+			item.params.SetSessionNonce(common.SHA512_256i_TAGGED(batchNonceTag, nonce, big.NewInt(int64(i))))
+			round1, ok := batch.rounds[i].(*round1)
+			if !ok {
+				return round.WrapError(&BatchItemError{Index: i, Err: errors.New("unable to Start(). party is in an unexpected round")})
+			}
+			if err := round1.prepare(); err != nil {
+				return round.WrapError(&BatchItemError{Index: i, Err: err})
+			}
+		}
+		return nil
+	})
+}
+
+func (p *BatchLocalParty) Update(msg tss.ParsedMessage) (ok bool, err *tss.Error) {
+	return tss.BaseUpdate(p, msg, TaskName)
+}
+
+func (p *BatchLocalParty) UpdateFromBytes(wireBytes []byte, from *tss.PartyID, isBroadcast bool) (bool, *tss.Error) {
+	msg, err := tss.ParseWireMessage(wireBytes, from, isBroadcast)
+	if err != nil {
+		return false, p.WrapError(err)
+	}
+	return p.Update(msg)
+}
+
+func (p *BatchLocalParty) ValidateMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
+	if ok, err := p.BaseParty.ValidateMessage(msg); !ok || err != nil {
+		return ok, err
+	}
+	// check that the message's "from index" will fit into the array
+	if maxFromIdx := len(p.params.Parties().IDs()) - 1; maxFromIdx < msg.GetFrom().Index {
+		return false, p.WrapError(fmt.Errorf("received msg with a sender index too great (%d <= %d)",
+			maxFromIdx, msg.GetFrom().Index), msg.GetFrom())
+	}
+	return true, nil
+}
+
+// StoreMessage unpacks a SignBatchMessage and stores each item with the party signing the matching message
+func (p *BatchLocalParty) StoreMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
+	if ok, err := p.ValidateMessage(msg); !ok || err != nil {
+		return ok, err
+	}
+	batch, ok := msg.Content().(*SignBatchMessage)
+	if !ok { // unrecognised message, just ignore!
+		p.params.Logger().With(tss.LogKeyTask, TaskName).Warn("unrecognised message ignored", "msg", msg.String())
+		return false, nil
+	}
+	if len(batch.Items) != len(p.items) {
+		return false, p.WrapError(fmt.Errorf("batch message from party %d holds %d items, expected %d",
+			msg.GetFrom().Index, len(batch.Items), len(p.items)), msg.GetFrom())
+	}
+	for i, bz := range batch.Items {
+		itemMsg, err := tss.ParseWireMessage(bz, msg.GetFrom(), msg.IsBroadcast())
+		if err != nil {
+			return false, p.WrapError(&BatchItemError{Index: i, Err: err}, msg.GetFrom())
+		}
+		ok, tssErr := p.items[i].StoreMessage(itemMsg)
+		if tssErr != nil {
+			return false, p.WrapError(&BatchItemError{Index: i, Err: tssErr.Cause()}, tssErr.Culprits()...)
+		}
+		if !ok {
+			return false, p.WrapError(&BatchItemError{Index: i, Err: fmt.Errorf("unexpected %s", itemMsg.Type())}, msg.GetFrom())
+		}
+	}
+	return true, nil
+}
+
+func (p *BatchLocalParty) PartyID() *tss.PartyID {
+	return p.params.PartyID()
+}
+
+func (p *BatchLocalParty) String() string {
+	return fmt.Sprintf("id: %s, batch of %d, %s", p.PartyID(), len(p.items), p.BaseParty.String())
+}
+
+// ----- //
+
+func (round *batchRound) Params() *tss.Parameters {
+	return round.party.params
+}
+
+func (round *batchRound) RoundNumber() int {
+	return round.number
+}
+
+func (round *batchRound) Start() *tss.Error {
+	for i, r := range round.rounds {
+		if err := r.Start(); err != nil {
+			return round.wrapItemError(i, err)
+		}
+	}
+	if _, final := round.rounds[0].(*finalization); final {
+		results := make([]*common.SignatureData, len(round.party.items))
+		for i, item := range round.party.items {
+			<-round.party.itemEnd[i]
+			results[i] = &item.data
+		}
+		round.party.end <- results
+		return nil
+	}
+	return round.flush()
+}
+
+// flush bundles the messages the batched parties just sent, one SignBatchMessage per recipient
+func (round *batchRound) flush() *tss.Error {
+	pending := make([][]tss.Message, len(round.party.items))
+	for i, ch := range round.party.itemOut {
+	drain:
+		for {
+			select {
+			case msg := <-ch:
+				pending[i] = append(pending[i], msg)
+			default:
+				break drain
+			}
+		}
+	}
+	for pos, first := range pending[0] {
+		items := make([][]byte, len(pending))
+		for i := range pending {
+			if len(pending[i]) != len(pending[0]) || !sameRouting(first, pending[i][pos]) {
+				return round.WrapError(&BatchItemError{Index: i, Err: errors.New("batched parties sent diverging messages")})
+			}
+			bz, _, err := pending[i][pos].WireBytes()
+			if err != nil {
+				return round.WrapError(&BatchItemError{Index: i, Err: err})
+			}
+			items[i] = bz
+		}
+		round.party.out <- NewSignBatchMessage(first.GetTo(), round.party.PartyID(), items)
+	}
+	return nil
+}
+
+func (round *batchRound) Update() (bool, *tss.Error) {
+	ret := true
+	for i, r := range round.rounds {
+		ok, err := r.Update()
+		if err != nil {
+			return false, round.wrapItemError(i, err)
+		}
+		ret = ret && ok
+	}
+	return ret, nil
+}
+
+func (round *batchRound) CanAccept(msg tss.ParsedMessage) bool {
+	_, ok := msg.Content().(*SignBatchMessage)
+	return ok
+}
+
+func (round *batchRound) CanProceed() bool {
+	for _, r := range round.rounds {
+		if !r.CanProceed() {
+			return false
+		}
+	}
+	return true
+}
+
+func (round *batchRound) NextRound() tss.Round {
+	next := make([]tss.Round, len(round.rounds))
+	for i, r := range round.rounds {
+		next[i] = r.NextRound()
+	}
+	if next[0] == nil {
+		return nil // finished!
+	}
+	return &batchRound{party: round.party, rounds: next, number: round.number + 1}
+}
+
+// WaitingFor reports the parties that some batched message is still waiting for
+func (round *batchRound) WaitingFor() []*tss.PartyID {
+	waiting := make(map[int]bool)
+	for _, r := range round.rounds {
+		for _, Pj := range r.WaitingFor() {
+			waiting[Pj.Index] = true
+		}
+	}
+	ids := make([]*tss.PartyID, 0, len(waiting))
+	for _, Pj := range round.Params().Parties().IDs() {
+		if waiting[Pj.Index] {
+			ids = append(ids, Pj)
+		}
+	}
+	return ids
+}
+
+func (round *batchRound) WrapError(err error, culprits ...*tss.PartyID) *tss.Error {
+	return tss.NewError(err, TaskName, round.number, round.party.PartyID(), culprits...)
+}
+
+func (round *batchRound) wrapItemError(index int, err *tss.Error) *tss.Error {
+	return round.WrapError(&BatchItemError{Index: index, Err: err.Cause()}, err.Culprits()...)
+}
+
+func sameRouting(a, b tss.Message) bool {
+	if a.IsBroadcast() != b.IsBroadcast() || len(a.GetTo()) != len(b.GetTo()) {
+		return false
+	}
+	for j := range a.GetTo() {
+		if a.GetTo()[j].Index != b.GetTo()[j].Index {
+			return false
+		}
+	}
+	return true
+}
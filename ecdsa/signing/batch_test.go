@@ -0,0 +1,175 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/test"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+// runBatchSigning signs msgsFor(i) with every signer and returns the per-party results or errors
+func runBatchSigning(t *testing.T, batchSize int, msgsFor func(i int) []*big.Int) (map[int][]*common.SignatureData, map[int]*tss.Error, []keygen.LocalPartySaveData) {
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]*BatchLocalParty, 0, len(signPIDs))
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan []*common.SignatureData, len(signPIDs))
+	updater := test.SharedPartyUpdater
+
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(tss.S256(), p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+		params.SetSessionNonce(big.NewInt(7))
+		P := NewBatchLocalParty(msgsFor(i), params, keys[i], outCh, endCh, 32).(*BatchLocalParty)
+		parties = append(parties, P)
+		go func(P *BatchLocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	results := make(map[int][]*common.SignatureData, len(signPIDs))
+	failed := make(map[int]*tss.Error, len(signPIDs))
+	for len(results)+len(failed) < len(signPIDs) {
+		select {
+		case err := <-errCh:
+			if _, ok := failed[err.Victim().Index]; !ok {
+				failed[err.Victim().Index] = err
+			}
+		case msg := <-outCh:
+			batch, ok := msg.(tss.ParsedMessage).Content().(*SignBatchMessage)
+			if assert.True(t, ok, "a batch party must only send batch messages") {
+				assert.Len(t, batch.Items, batchSize)
+			}
+			dest := msg.GetTo()
+			if dest == nil {
+				for _, P := range parties {
+					if P.PartyID().Index == msg.GetFrom().Index {
+						continue
+					}
+					go updater(P, msg, errCh)
+				}
+			} else {
+				go updater(parties[dest[0].Index], msg, errCh)
+			}
+		case sigs := <-endCh:
+			// keyed by arrival order; every party must output the same signatures
+			results[len(results)] = sigs
+		}
+	}
+	return results, failed, keys
+}
+
+func TestE2EBatchSigning(t *testing.T) {
+	setUp("info")
+	msgs := []*big.Int{big.NewInt(42), new(big.Int).SetBytes(common.SHA512_256([]byte("sweep input 1")))}
+	results, failed, keys := runBatchSigning(t, len(msgs), func(int) []*big.Int { return msgs })
+	if !assert.Empty(t, failed) || !assert.Len(t, results, testThreshold+1) {
+		return
+	}
+
+	pk := ecdsa.PublicKey{
+		Curve: tss.EC(),
+		X:     keys[0].ECDSAPub.X(),
+		Y:     keys[0].ECDSAPub.Y(),
+	}
+	for _, sigs := range results {
+		if !assert.Len(t, sigs, len(msgs)) {
+			continue
+		}
+		for m, sig := range sigs {
+			assert.Equal(t, msgs[m].FillBytes(make([]byte, 32)), sig.M)
+			assert.True(t, ecdsa.Verify(&pk, sig.M, new(big.Int).SetBytes(sig.R), new(big.Int).SetBytes(sig.S)),
+				"ecdsa verify must pass for message %d", m)
+			assert.Equal(t, results[0][m].Signature, sig.Signature, "all parties must output the same signature")
+		}
+		// every message is signed with its own nonce
+		assert.NotEqual(t, sigs[0].R, sigs[1].R)
+	}
+}
+
+func TestE2EBatchSigningDisagreementNamesMessageAndParty(t *testing.T) {
+	setUp("info")
+	const odd, oddMsg = 3, 1
+	_, failed, _ := runBatchSigning(t, 2, func(i int) []*big.Int {
+		msgs := []*big.Int{big.NewInt(42), big.NewInt(43)}
+		if i == odd {
+			msgs[oddMsg] = big.NewInt(44)
+		}
+		return msgs
+	})
+	assert.Len(t, failed, testThreshold+1)
+	for i, err := range failed {
+		assert.Equal(t, 2, err.Round())
+		var itemErr *BatchItemError
+		if assert.True(t, errors.As(err, &itemErr)) {
+			assert.Equal(t, oddMsg, itemErr.Index)
+		}
+		if i == odd {
+			assert.Len(t, err.Culprits(), testThreshold)
+			continue
+		}
+		if assert.Len(t, err.Culprits(), 1) {
+			assert.Equal(t, odd, err.Culprits()[0].Index)
+		}
+	}
+}
+
+func TestBatchStoreMessageRejectsWrongItemCount(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(2)
+	params := tss.NewParameters(tss.S256(), tss.NewPeerContext(pIDs), pIDs[0], len(pIDs), 1)
+	keys := keygen.NewLocalPartySaveData(len(pIDs))
+	for i, id := range pIDs {
+		keys.Ks[i] = id.KeyInt()
+	}
+	P := NewBatchLocalParty([]*big.Int{big.NewInt(1), big.NewInt(2)}, params, keys, nil, nil, 32).(*BatchLocalParty)
+
+	item, _, err := NewSignRound3Message(pIDs[1], big.NewInt(1)).WireBytes()
+	assert.NoError(t, err)
+	ok, tssErr := P.StoreMessage(NewSignBatchMessage(nil, pIDs[1], [][]byte{item}))
+	assert.False(t, ok)
+	if assert.NotNil(t, tssErr) {
+		assert.Equal(t, []*tss.PartyID{pIDs[1]}, tssErr.Culprits())
+	}
+
+	ok, tssErr = P.StoreMessage(NewSignBatchMessage(nil, pIDs[1], [][]byte{item, item}))
+	assert.True(t, ok)
+	assert.Nil(t, tssErr)
+	for _, inner := range P.items {
+		assert.NotNil(t, inner.temp.signRound3Messages[1])
+	}
+}
+
+func TestBatchStartRejectsNoMessages(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(2)
+	params := tss.NewParameters(tss.S256(), tss.NewPeerContext(pIDs), pIDs[0], len(pIDs), 1)
+	params.SetSessionNonce(big.NewInt(1))
+	keys := keygen.NewLocalPartySaveData(len(pIDs))
+	for i, id := range pIDs {
+		keys.Ks[i] = id.KeyInt()
+	}
+	var P tss.Party
+	assert.NotPanics(t, func() {
+		P = NewBatchLocalParty(nil, params, keys, nil, nil, 32)
+	})
+	err := P.Start()
+	if assert.NotNil(t, err, "a batch without messages must not start") {
+		assert.Equal(t, 1, err.Round())
+	}
+}
@@ -0,0 +1,261 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+const (
+	defaultAttemptTimeout = 10 * time.Minute
+	defaultMaxAttempts    = 5
+)
+
+// ErrNotEnoughSigners is returned when fewer than t+1 live, non-excluded parties remain
+var ErrNotEnoughSigners = errors.New("not enough live signers for the threshold")
+
+type (
+	// Coordinator picks t+1 signers out of all n keygen parties, runs signing and, when an attempt fails with
+	// culprits or times out, retries with the culprits or unresponsive parties excluded and a fresh session nonce.
+	//
+	// Every party runs its own Coordinator with the same settings. With a shared Seed they all pick the same signers;
+	// a party that is not picked for an attempt learns its outcome through AwaitOutcome instead of signing.
+	// Without a Seed the signers are drawn at random, always including the local party; use this only when one
+	// party drives the selection and hands each Attempt to the others out-of-band (e.g. through NewTransport).
+	Coordinator struct {
+		EC           elliptic.Curve
+		Parties      tss.SortedPartyIDs // all n keygen parties
+		PartyID      *tss.PartyID       // the local party; must be one of Parties
+		Threshold    int
+		Key          keygen.LocalPartySaveData
+		Msg          *big.Int
+		FullBytesLen int
+		// KeyDerivationDelta is optional; see NewLocalPartyWithKDD
+		KeyDerivationDelta *big.Int
+		// IsLive is the liveness oracle consulted before every attempt; nil treats every party as live
+		IsLive func(pID *tss.PartyID) bool
+		// Seed makes the signer selection deterministic; see SelectSigners
+		Seed []byte
+		// SessionID is agreed out-of-band and must be at least 16 bytes; each attempt derives its own nonce from it
+		SessionID []byte
+		// Timeout is how long an attempt may wait without receiving a message (default 10 minutes)
+		Timeout time.Duration
+		// MaxAttempts bounds the number of attempts (default 5)
+		MaxAttempts  int
+		NewTransport tss.TransportFactory
+		// AwaitOutcome reports the signature or the culprits of an attempt the local party was not picked for
+		AwaitOutcome func(attempt *tss.Attempt) (*common.SignatureData, []*tss.PartyID, error)
+		// Logger is optional; it is set on the parameters of every attempt and logs the attempts that failed
+		Logger tss.Logger
+
+		runAttempt func(attempt *tss.Attempt) (*common.SignatureData, *tss.Error)
+	}
+
+	// AttemptLog describes one signing attempt made by a Coordinator
+	AttemptLog struct {
+		Number   int
+		Signers  tss.SortedPartyIDs
+		Excluded []tss.Exclusion // parties excluded because of this attempt
+		Err      error
+	}
+)
+
+// SelectSigners picks t+1 of the live `parties`. With a seed the choice is deterministic: the candidates are ranked
+// by SHA-512/256(seed || key), so every party given the same inputs picks the same signers. Without a seed they are
+// ranked at random. The result is a fresh sorted set; the caller's PartyIDs are not re-indexed.
+func SelectSigners(parties tss.SortedPartyIDs, threshold int, isLive func(pID *tss.PartyID) bool, seed []byte) (tss.SortedPartyIDs, error) {
+	return selectSigners(parties, threshold, isLive, seed, nil)
+}
+
+func selectSigners(parties tss.SortedPartyIDs, threshold int, isLive func(pID *tss.PartyID) bool, seed []byte, mustInclude *tss.PartyID) (tss.SortedPartyIDs, error) {
+	type candidate struct {
+		pID  *tss.PartyID
+		rank *big.Int
+	}
+	candidates := make([]candidate, 0, len(parties))
+	for _, pID := range parties {
+		if isLive != nil && !isLive(pID) {
+			continue
+		}
+		var rank *big.Int
+		switch {
+		case mustInclude != nil && pID.KeyInt().Cmp(mustInclude.KeyInt()) == 0:
+			rank = big.NewInt(-1)
+		case seed != nil:
+			rank = new(big.Int).SetBytes(common.SHA512_256(seed, pID.Key))
+		default:
+			rank = common.MustGetRandomInt(256)
+		}
+		candidates = append(candidates, candidate{pID, rank})
+	}
+	if len(candidates) < threshold+1 {
+		return nil, fmt.Errorf("%w: %d live of %d parties, need %d", ErrNotEnoughSigners, len(candidates), len(parties), threshold+1)
+	}
+	sort.Slice(candidates, func(a, b int) bool {
+		return candidates[a].rank.Cmp(candidates[b].rank) < 0
+	})
+	ids := make(tss.UnSortedPartyIDs, threshold+1)
+	for i := range ids {
+		pID := candidates[i].pID
+		ids[i] = tss.NewPartyID(pID.Id, pID.Moniker, pID.KeyInt())
+	}
+	return tss.SortPartyIDs(ids), nil
+}
+
+// Run signs Msg, retrying until an attempt succeeds, MaxAttempts is reached or too few signers remain.
+// The attempt log is returned in every case.
+func (c *Coordinator) Run() (*common.SignatureData, []AttemptLog, error) {
+	log := make([]AttemptLog, 0, 1)
+	if len(c.SessionID) < 16 {
+		return nil, log, errors.New("signing coordinator: session ID must be at least 16 bytes")
+	}
+	if c.Parties.FindByKey(c.PartyID.KeyInt()) == nil {
+		return nil, log, errors.New("signing coordinator: the local party is not one of Parties")
+	}
+	if c.runAttempt == nil {
+		c.runAttempt = c.runLocalParty
+	}
+	maxAttempts := c.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+
+	// only parties present in the save data can sign; BuildLocalSaveDataSubset would panic on any other
+	known := make(map[string]struct{}, len(c.Key.Ks))
+	for _, kj := range c.Key.Ks {
+		known[kj.String()] = struct{}{}
+	}
+	candidates := make(tss.SortedPartyIDs, 0, len(c.Parties))
+	for _, pID := range c.Parties {
+		if _, ok := known[pID.KeyInt().String()]; ok {
+			candidates = append(candidates, pID)
+		}
+	}
+	if _, ok := known[c.PartyID.KeyInt().String()]; !ok {
+		return nil, log, errors.New("signing coordinator: the local party is not in the key's save data")
+	}
+
+	var mustInclude *tss.PartyID
+	if c.Seed == nil {
+		mustInclude = c.PartyID
+	}
+	for number := 1; number <= maxAttempts; number++ {
+		signers, err := selectSigners(candidates, c.Threshold, c.IsLive, c.Seed, mustInclude)
+		if err != nil {
+			return nil, log, err
+		}
+		attempt := tss.NewAttempt(c.SessionID, number, signers)
+		entry := AttemptLog{Number: number, Signers: attempt.Parties}
+
+		var culprits []*tss.PartyID
+		var reason string
+		var round int
+		if attempt.Parties.FindByKey(c.PartyID.KeyInt()) == nil {
+			if c.AwaitOutcome == nil {
+				entry.Err = errors.New("signing coordinator: the local party was not picked and AwaitOutcome is nil")
+				return nil, append(log, entry), entry.Err
+			}
+			data, blamed, err := c.AwaitOutcome(attempt)
+			if err == nil && data != nil {
+				return data, append(log, entry), nil
+			}
+			culprits, reason = blamed, "reported by the signers of the attempt"
+			if err != nil {
+				reason = err.Error()
+			}
+			entry.Err = fmt.Errorf("attempt %d failed without the local party: %s", number, reason)
+		} else {
+			data, tssErr := c.runAttempt(attempt)
+			if tssErr == nil {
+				return data, append(log, entry), nil
+			}
+			entry.Err = tssErr
+			culprits, reason, round = tssErr.Culprits(), tssErr.Cause().Error(), tssErr.Round()
+		}
+		c.logger().Warn("signing attempt failed", "attempt", number, "err", entry.Err.Error())
+		if len(culprits) == 0 {
+			log = append(log, entry)
+			return nil, log, fmt.Errorf("signing attempt %d failed without culprits: %w", number, entry.Err)
+		}
+		for _, culprit := range culprits {
+			if culprit.KeyInt().Cmp(c.PartyID.KeyInt()) == 0 {
+				log = append(log, entry)
+				return nil, log, fmt.Errorf("signing attempt %d blamed the local party: %w", number, entry.Err)
+			}
+			original := candidates.FindByKey(culprit.KeyInt())
+			if original == nil {
+				continue
+			}
+			entry.Excluded = append(entry.Excluded, tss.Exclusion{
+				Party:   original,
+				Attempt: number,
+				Round:   round,
+				Reason:  reason,
+			})
+			candidates = candidates.Exclude(original)
+		}
+		log = append(log, entry)
+	}
+	return nil, log, fmt.Errorf("signing gave up after %d attempts", maxAttempts)
+}
+
+func (c *Coordinator) runLocalParty(attempt *tss.Attempt) (*common.SignatureData, *tss.Error) {
+	self := attempt.Parties.FindByKey(c.PartyID.KeyInt())
+	params := tss.NewParameters(c.EC, tss.NewPeerContext(attempt.Parties), self, len(attempt.Parties), c.Threshold)
+	params.SetSessionNonce(attempt.SessionNonce)
+	if c.Logger != nil {
+		params.SetLogger(c.Logger)
+	}
+
+	transport, err := c.NewTransport(attempt)
+	if err != nil {
+		return nil, tss.NewError(err, TaskName, 0, self)
+	}
+	defer transport.Close()
+
+	out := make(chan tss.Message, len(attempt.Parties))
+	end := make(chan common.SignatureData, 1)
+	party := NewLocalPartyWithKDD(c.Msg, params, c.Key, c.KeyDerivationDelta, out, end, c.FullBytesLen)
+
+	done := make(chan struct{})
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-end:
+			close(done)
+		case <-stop:
+		}
+	}()
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = defaultAttemptTimeout
+	}
+	if err := tss.RunParty(party, out, done, transport, timeout); err != nil {
+		return nil, err
+	}
+	// the finalized signature is also kept on the party; reading it there avoids copying the proto message
+	return &party.(*LocalParty).data, nil
+}
+
+// logger returns the Logger, or tss.DefaultLogger, with the party and task fields
+func (c *Coordinator) logger() tss.Logger {
+	logger := c.Logger
+	if logger == nil {
+		logger = tss.DefaultLogger
+	}
+	return logger.With(tss.LogKeyParty, c.PartyID.String(), tss.LogKeyTask, TaskName)
+}
@@ -0,0 +1,236 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/test"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+var testCoordinatorSessionID = []byte("signing-coordinator-test-session")
+
+// warnLogger records the warnings it is given, with their fields
+type warnLogger struct {
+	fields   []interface{}
+	warnings *[][]interface{}
+}
+
+func (l *warnLogger) Debug(string, ...interface{}) {}
+func (l *warnLogger) Info(string, ...interface{})  {}
+func (l *warnLogger) Error(string, ...interface{}) {}
+func (l *warnLogger) Warn(msg string, keysAndValues ...interface{}) {
+	*l.warnings = append(*l.warnings, append(append([]interface{}{msg}, l.fields...), keysAndValues...))
+}
+func (l *warnLogger) With(keysAndValues ...interface{}) tss.Logger {
+	return &warnLogger{fields: append(append([]interface{}{}, l.fields...), keysAndValues...), warnings: l.warnings}
+}
+
+func testCoordinatorKey(pIDs tss.SortedPartyIDs) keygen.LocalPartySaveData {
+	return keygen.LocalPartySaveData{Ks: pIDs.Keys()}
+}
+
+func TestSelectSignersIsDeterministicWithSeed(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(7)
+	seed := []byte("seed")
+	a, err := SelectSigners(pIDs, 3, nil, seed)
+	assert.NoError(t, err)
+	b, err := SelectSigners(pIDs, 3, nil, seed)
+	assert.NoError(t, err)
+	assert.Len(t, a, 4)
+	assert.Equal(t, a.Keys(), b.Keys())
+	for i, pID := range a {
+		assert.Equal(t, i, pID.Index)
+	}
+	for i, pID := range pIDs {
+		assert.Equal(t, i, pID.Index, "the caller's party IDs must not be re-indexed")
+	}
+}
+
+func TestSelectSignersSkipsDeadParties(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(5)
+	dead := pIDs[2].KeyInt()
+	isLive := func(pID *tss.PartyID) bool { return pID.KeyInt().Cmp(dead) != 0 }
+	for i := 0; i < 10; i++ {
+		signers, err := SelectSigners(pIDs, 3, isLive, nil)
+		assert.NoError(t, err)
+		assert.Len(t, signers, 4)
+		assert.Nil(t, signers.FindByKey(dead))
+	}
+	_, err := SelectSigners(pIDs, 4, isLive, nil)
+	assert.True(t, errors.Is(err, ErrNotEnoughSigners))
+}
+
+func TestSigningCoordinatorRetriesWithoutCulprits(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(6)
+	nonces := make(map[string]struct{})
+	var culprit, unresponsive *big.Int
+	logger := &warnLogger{warnings: new([][]interface{})}
+	c := &Coordinator{
+		Parties:   pIDs,
+		PartyID:   pIDs[0],
+		Threshold: 2,
+		Key:       testCoordinatorKey(pIDs),
+		SessionID: testCoordinatorSessionID,
+		Logger:    logger,
+		runAttempt: func(attempt *tss.Attempt) (*common.SignatureData, *tss.Error) {
+			nonces[attempt.SessionNonce.String()] = struct{}{}
+			assert.Len(t, attempt.Parties, 3)
+			assert.NotNil(t, attempt.Parties.FindByKey(pIDs[0].KeyInt()), "the local party is always picked without a seed")
+			self := attempt.Parties.FindByKey(pIDs[0].KeyInt())
+			others := attempt.Parties.Exclude(self)
+			switch attempt.Number {
+			case 1:
+				culprit = others[0].KeyInt()
+				return nil, tss.NewError(errors.New("bad proof"), TaskName, 3, self, others[0])
+			case 2:
+				assert.Nil(t, attempt.Parties.FindByKey(culprit))
+				unresponsive = others[1].KeyInt()
+				return nil, tss.NewError(tss.ErrAttemptTimeout, TaskName, 1, self, others[1])
+			default:
+				assert.Nil(t, attempt.Parties.FindByKey(culprit))
+				assert.Nil(t, attempt.Parties.FindByKey(unresponsive))
+				return &common.SignatureData{Signature: []byte{1}}, nil
+			}
+		},
+	}
+	data, log, err := c.Run()
+	assert.NoError(t, err)
+	assert.NotNil(t, data)
+	assert.Len(t, log, 3)
+	assert.Len(t, nonces, 3, "every attempt must use a fresh session nonce")
+	if assert.Len(t, log[0].Excluded, 1) {
+		assert.Equal(t, culprit, log[0].Excluded[0].Party.KeyInt())
+		assert.Equal(t, 3, log[0].Excluded[0].Round)
+	}
+	if assert.Len(t, log[1].Excluded, 1) {
+		assert.Equal(t, tss.ErrAttemptTimeout.Error(), log[1].Excluded[0].Reason)
+	}
+	assert.Nil(t, log[2].Err)
+	if assert.Len(t, *logger.warnings, 2, "the failed attempts should be logged") {
+		for i, warning := range *logger.warnings {
+			assert.Equal(t, []interface{}{"signing attempt failed", tss.LogKeyParty, pIDs[0].String(), tss.LogKeyTask, TaskName,
+				"attempt", i + 1, "err", log[i].Err.Error()}, warning)
+		}
+	}
+}
+
+func TestSigningCoordinatorSkipsPartiesMissingFromSaveData(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(4)
+	unknown := tss.GenerateTestPartyIDs(5)[4]
+	all := tss.SortPartyIDs(append(pIDs.ToUnSorted(), unknown), 0)
+	c := &Coordinator{
+		Parties:   all,
+		PartyID:   pIDs[0],
+		Threshold: 3,
+		Key:       testCoordinatorKey(pIDs),
+		Seed:      []byte("seed"),
+		SessionID: testCoordinatorSessionID,
+		runAttempt: func(attempt *tss.Attempt) (*common.SignatureData, *tss.Error) {
+			assert.Nil(t, attempt.Parties.FindByKey(unknown.KeyInt()))
+			return &common.SignatureData{}, nil
+		},
+	}
+	_, _, err := c.Run()
+	assert.NoError(t, err)
+}
+
+func TestSigningCoordinatorGivesUp(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(4)
+	newCoordinator := func(run func(*tss.Attempt) (*common.SignatureData, *tss.Error)) *Coordinator {
+		return &Coordinator{
+			Parties:    pIDs,
+			PartyID:    pIDs[0],
+			Threshold:  2,
+			Key:        testCoordinatorKey(pIDs),
+			SessionID:  testCoordinatorSessionID,
+			runAttempt: run,
+		}
+	}
+
+	// no culprits: retrying cannot help
+	_, log, err := newCoordinator(func(attempt *tss.Attempt) (*common.SignatureData, *tss.Error) {
+		return nil, tss.NewError(errors.New("bad"), TaskName, 2, attempt.Parties.FindByKey(pIDs[0].KeyInt()))
+	}).Run()
+	assert.Error(t, err)
+	assert.Len(t, log, 1)
+
+	// too few signers left after the exclusions
+	_, log, err = newCoordinator(func(attempt *tss.Attempt) (*common.SignatureData, *tss.Error) {
+		self := attempt.Parties.FindByKey(pIDs[0].KeyInt())
+		return nil, tss.NewError(errors.New("bad"), TaskName, 2, self, attempt.Parties.Exclude(self)[0])
+	}).Run()
+	assert.True(t, errors.Is(err, ErrNotEnoughSigners))
+	assert.Len(t, log, 2)
+
+	// the local party was blamed
+	_, _, err = newCoordinator(func(attempt *tss.Attempt) (*common.SignatureData, *tss.Error) {
+		self := attempt.Parties.FindByKey(pIDs[0].KeyInt())
+		return nil, tss.NewError(errors.New("bad"), TaskName, 2, self, self)
+	}).Run()
+	assert.Error(t, err)
+}
+
+// TestE2ESigningCoordinatorThresholdSigners signs with t+1 of the n fixture parties, each running a Coordinator
+// with the same seed; the parties that are not picked take no part.
+func TestE2ESigningCoordinatorThresholdSigners(t *testing.T) {
+	setUp("info")
+	keys, pIDs, err := keygen.LoadKeygenTestFixtures(testParticipants)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	seed := []byte("seed")
+	signers, err := SelectSigners(pIDs, testThreshold, nil, seed)
+	if !assert.NoError(t, err) || !assert.Len(t, signers, testThreshold+1) {
+		return
+	}
+	hub := test.NewHub()
+	msg := big.NewInt(42)
+	type result struct {
+		data *common.SignatureData
+		log  []AttemptLog
+		err  error
+	}
+	results := make(chan result, len(signers))
+	for _, signer := range signers {
+		i := pIDs.FindByKey(signer.KeyInt()).Index
+		c := &Coordinator{
+			EC:           tss.S256(),
+			Parties:      pIDs,
+			PartyID:      pIDs[i],
+			Threshold:    testThreshold,
+			Key:          keys[i],
+			Msg:          msg,
+			FullBytesLen: 32,
+			Seed:         seed,
+			SessionID:    testCoordinatorSessionID,
+			NewTransport: hub.Transport(pIDs[i]),
+		}
+		go func() {
+			data, log, err := c.Run()
+			results <- result{data, log, err}
+		}()
+	}
+	pk := ecdsa.PublicKey{Curve: tss.EC(), X: keys[0].ECDSAPub.X(), Y: keys[0].ECDSAPub.Y()}
+	for range signers {
+		res := <-results
+		if !assert.NoError(t, res.err) {
+			continue
+		}
+		assert.Len(t, res.log, 1)
+		r, s := new(big.Int).SetBytes(res.data.R), new(big.Int).SetBytes(res.data.S)
+		assert.True(t, ecdsa.Verify(&pk, msg.Bytes(), r, s), "ecdsa verify must pass")
+	}
+}
@@ -61,9 +61,15 @@ func (round *finalization) Start() *tss.Error {
 	round.data.S = padToLengthBytesInPlace(sumS.Bytes(), bitSizeInBytes)
 	round.data.Signature = append(round.data.R, round.data.S...)
 	round.data.SignatureRecovery = []byte{byte(recid)}
-	mBytes := make([]byte, round.temp.fullBytesLen)
-	round.temp.m.FillBytes(mBytes)
-	round.data.M = mBytes
+	if round.temp.hashID != common.MessageHashNone {
+		// crypto/ecdsa truncates the digest the same way digestToInt did
+		round.data.M = round.temp.digest
+		round.data.HashId = uint32(round.temp.hashID)
+	} else {
+		mBytes := make([]byte, round.temp.fullBytesLen)
+		round.temp.m.FillBytes(mBytes)
+		round.data.M = mBytes
+	}
 
 	pk := ecdsa.PublicKey{
 		Curve: round.Params().EC(),
@@ -0,0 +1,96 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/crypto/ckd"
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+const chainCodeLen = 32
+
+// ParseDerivationPath parses a BIP-32 path such as "m/0/5/12". Hardened components ("0'", "0h" or indices of 2^31
+// and above) are rejected: a threshold key has no single private key, so only public derivation is possible.
+func ParseDerivationPath(path string) ([]uint32, error) {
+	parts := strings.Split(strings.TrimSpace(path), "/")
+	if parts[0] != "m" {
+		return nil, fmt.Errorf("derivation path %q must start with \"m\"", path)
+	}
+	indices := make([]uint32, 0, len(parts)-1)
+	for pos, part := range parts[1:] {
+		if strings.HasSuffix(part, "'") || strings.HasSuffix(part, "h") || strings.HasSuffix(part, "H") {
+			return nil, fmt.Errorf("derivation path %q: hardened component %q at depth %d cannot be derived from a threshold key", path, part, pos+1)
+		}
+		index, err := strconv.ParseUint(part, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("derivation path %q: invalid component %q at depth %d", path, part, pos+1)
+		}
+		if index >= ckd.HardenedKeyStart {
+			return nil, fmt.Errorf("derivation path %q: component %d at depth %d is a hardened index and cannot be derived from a threshold key", path, index, pos+1)
+		}
+		indices = append(indices, uint32(index))
+	}
+	return indices, nil
+}
+
+// DeriveKeyForPath derives the child of `key`'s public key along `path` using `chainCode`. It returns a copy of
+// `key` adjusted to the child public key, the key derivation delta to sign with and the child's extended public key.
+// `key` itself, including its BigXj slice, is left untouched.
+func DeriveKeyForPath(key keygen.LocalPartySaveData, chainCode []byte, path string) (keygen.LocalPartySaveData, *big.Int, *ckd.ExtendedKey, error) {
+	indices, err := ParseDerivationPath(path)
+	if err != nil {
+		return keygen.LocalPartySaveData{}, nil, nil, err
+	}
+	if len(chainCode) != chainCodeLen {
+		return keygen.LocalPartySaveData{}, nil, nil, fmt.Errorf("chain code must be %d bytes, got %d", chainCodeLen, len(chainCode))
+	}
+	if key.ECDSAPub == nil {
+		return keygen.LocalPartySaveData{}, nil, nil, errors.New("the save data holds no public key")
+	}
+	ec := key.ECDSAPub.Curve()
+	delta, child, err := derivingPubkeyFromPath(key.ECDSAPub, chainCode, indices, ec)
+	if err != nil {
+		return keygen.LocalPartySaveData{}, nil, nil, err
+	}
+	derived := key
+	derived.BigXj = make([]*crypto.ECPoint, len(key.BigXj))
+	copy(derived.BigXj, key.BigXj)
+	keys := []keygen.LocalPartySaveData{derived}
+	if err = UpdatePublicKeyAndAdjustBigXj(delta, keys, &child.PublicKey, ec); err != nil {
+		return keygen.LocalPartySaveData{}, nil, nil, err
+	}
+	return keys[0], delta, child, nil
+}
+
+// NewLocalPartyWithPath returns a party that signs `msg` with the child key at the BIP-32 `path` (e.g. "m/0/5/12")
+// of `key`, derived with `chainCode`. The caller's save data is not modified. The child's extended public key is
+// returned so the derived key can be audited; start the party as usual.
+func NewLocalPartyWithPath(
+	msg *big.Int,
+	params *tss.Parameters,
+	key keygen.LocalPartySaveData,
+	chainCode []byte,
+	path string,
+	out chan<- tss.Message,
+	end chan<- common.SignatureData,
+	fullBytesLen ...int,
+) (tss.Party, *ckd.ExtendedKey, error) {
+	derived, delta, child, err := DeriveKeyForPath(key, chainCode, path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return NewLocalPartyWithKDD(msg, params, derived, delta, out, end, fullBytesLen...), child, nil
+}
@@ -0,0 +1,84 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+func TestParseDerivationPath(t *testing.T) {
+	indices, err := ParseDerivationPath("m/0/5/12")
+	assert.NoError(t, err)
+	assert.Equal(t, []uint32{0, 5, 12}, indices)
+
+	indices, err = ParseDerivationPath("m")
+	assert.NoError(t, err)
+	assert.Empty(t, indices)
+
+	for _, path := range []string{"m/44'/0", "m/0h", "m/2147483648", "0/5", "m/x", "m//1", "m/-1"} {
+		_, err := ParseDerivationPath(path)
+		assert.Error(t, err, path)
+	}
+	_, err = ParseDerivationPath("m/1/44'")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "hardened")
+	}
+}
+
+func TestDeriveKeyForPathLeavesSaveDataUntouched(t *testing.T) {
+	keys, pIDs, err := keygen.LoadKeygenTestFixtures(testThreshold + 1)
+	assert.NoError(t, err, "should load keygen fixtures")
+	chainCode := make([]byte, 32)
+	chainCode[0] = 1
+
+	key := keys[0]
+	origPub := key.ECDSAPub
+	origBigXj := make([]*crypto.ECPoint, len(key.BigXj))
+	copy(origBigXj, key.BigXj)
+
+	derived, delta, child, err := DeriveKeyForPath(key, chainCode, "m/0/5/12")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(child.String(), "xpub"))
+	assert.Equal(t, uint8(3), child.Depth)
+	assert.Equal(t, 0, derived.ECDSAPub.X().Cmp(child.X))
+	assert.Equal(t, 0, derived.ECDSAPub.Y().Cmp(child.Y))
+
+	assert.True(t, key.ECDSAPub.Equals(origPub))
+	gDelta := crypto.ScalarBaseMult(tss.S256(), delta)
+	for j := range origBigXj {
+		assert.True(t, key.BigXj[j].Equals(origBigXj[j]), "the caller's BigXj must not change")
+		want, err := origBigXj[j].Add(gDelta)
+		assert.NoError(t, err)
+		assert.True(t, derived.BigXj[j].Equals(want))
+	}
+
+	// the child public key is the parent shifted by the delta
+	want, err := origPub.Add(gDelta)
+	assert.NoError(t, err)
+	assert.True(t, derived.ECDSAPub.Equals(want))
+
+	params := tss.NewParameters(tss.S256(), tss.NewPeerContext(pIDs), pIDs[0], len(pIDs), testThreshold)
+	party, xpub, err := NewLocalPartyWithPath(big.NewInt(42), params, key, chainCode, "m/0/5/12", nil, make(chan common.SignatureData, 1), 32)
+	assert.NoError(t, err)
+	assert.Equal(t, child.String(), xpub.String())
+	assert.True(t, party.(*LocalParty).keys.ECDSAPub.Equals(derived.ECDSAPub))
+	assert.Equal(t, 0, party.(*LocalParty).temp.keyDerivationDelta.Cmp(delta))
+
+	_, _, _, err = DeriveKeyForPath(key, chainCode[:16], "m/0")
+	assert.Error(t, err)
+	_, _, err = NewLocalPartyWithPath(big.NewInt(42), params, key, chainCode, "m/0'", nil, nil, 32)
+	assert.Error(t, err)
+}
@@ -0,0 +1,134 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/crypto/vss"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+func TestPrepareForHierarchicalSigning(t *testing.T) {
+	ec := tss.S256()
+	q := ec.Params().N
+	const threshold = 2
+	ks := []*big.Int{big.NewInt(11), big.NewInt(21), big.NewInt(31), big.NewInt(41)}
+	ranks := []int{0, 1, 1, 1}
+	secret := common.GetRandomPositiveInt(q)
+	_, shares, err := vss.CreateHierarchical(ec, threshold, secret, ks, ranks)
+	assert.NoError(t, err)
+	bigXs := make([]*crypto.ECPoint, len(ks))
+	for j, share := range shares {
+		bigXs[j] = crypto.ScalarBaseMult(ec, share.Share)
+	}
+
+	// P0 is of rank 0, so it can sign with any two others
+	signers := []int{0, 2, 3}
+	sum := big.NewInt(0)
+	for i, j := range signers {
+		wi, bigWs, err := PrepareForHierarchicalSigning(ec, i, threshold, shares[j].Share,
+			[]*big.Int{ks[0], ks[2], ks[3]}, []int{ranks[0], ranks[2], ranks[3]}, []*crypto.ECPoint{bigXs[0], bigXs[2], bigXs[3]})
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.True(t, bigWs[i].Equals(crypto.ScalarBaseMult(ec, wi)), "W_i must match w_i")
+		sum.Add(sum, wi)
+	}
+	assert.Equal(t, 0, new(big.Int).Mod(sum, q).Cmp(secret), "the additive shares must add up to the secret")
+
+	// without P0 nobody holds a share of the constant term
+	_, _, err = PrepareForHierarchicalSigning(ec, 0, threshold, shares[1].Share, ks[1:], ranks[1:], bigXs[1:])
+	assert.True(t, errors.Is(err, vss.ErrUnauthorizedSet))
+	_, _, err = PrepareForHierarchicalSigning(ec, 0, threshold, shares[0].Share, ks[:2], ranks[:2], bigXs[:2])
+	assert.True(t, errors.Is(err, vss.ErrUnauthorizedSet))
+}
+
+func TestE2EHierarchicalKeygenAndSigning(t *testing.T) {
+	setUp("info")
+	const threshold = 2
+	// "any 3 signers, at least one of whom is from the security team": P0 is the security team
+	ranks := []int{0, 1, 1, 1}
+	keys, pIDs := runTestKeygen(t, len(ranks), func(p2pCtx *tss.PeerContext, pID *tss.PartyID) *tss.Parameters {
+		params := tss.NewHierarchicalParameters(tss.S256(), p2pCtx, pID, ranks, threshold)
+		params.SetSessionNonce(big.NewInt(21))
+		return params
+	})
+	for j, key := range keys {
+		assert.Equal(t, ranks, key.Ranks)
+		assert.True(t, crypto.ScalarBaseMult(tss.S256(), key.Xi).Equals(keys[0].BigXj[j]))
+	}
+
+	msg := big.NewInt(42)
+	sign := func(signers []int, nonce int64) (*tss.Error, []tss.Party) {
+		unsorted := make(tss.UnSortedPartyIDs, 0, len(signers))
+		for _, j := range signers {
+			unsorted = append(unsorted, tss.NewPartyID(pIDs[j].Id, pIDs[j].Moniker, pIDs[j].KeyInt()))
+		}
+		signPIDs := tss.SortPartyIDs(unsorted)
+		signRanks := make([]int, len(signers))
+		for i, j := range signers {
+			signRanks[i] = ranks[j]
+		}
+		p2pCtx := tss.NewPeerContext(signPIDs)
+		parties := make([]tss.Party, 0, len(signPIDs))
+		errCh := make(chan *tss.Error, len(signPIDs))
+		outCh := make(chan tss.Message, len(signPIDs)*len(signPIDs))
+		endCh := make(chan common.SignatureData, len(signPIDs))
+		for i, id := range signPIDs {
+			params := tss.NewHierarchicalParameters(tss.S256(), p2pCtx, id, signRanks, threshold)
+			params.SetSessionNonce(big.NewInt(nonce))
+			P := NewLocalParty(msg, params, keys[signers[i]], outCh, endCh, 32)
+			parties = append(parties, P)
+		}
+		// an unauthorized set fails in Start, before anything is sent
+		for _, P := range parties {
+			if err := P.Start(); err != nil {
+				assert.Len(t, outCh, 0)
+				return err, nil
+			}
+		}
+		for ended := 0; ended < len(signPIDs); {
+			select {
+			case err := <-errCh:
+				return err, nil
+			case msg := <-outCh:
+				routeTestMessage(parties, msg, errCh)
+			case <-endCh:
+				ended++
+			}
+		}
+		return nil, parties
+	}
+
+	err2, _ := sign([]int{1, 2, 3}, 22)
+	if assert.NotNil(t, err2, "signers without the security team must be rejected") {
+		assert.True(t, errors.Is(err2.Cause(), vss.ErrUnauthorizedSet))
+	}
+
+	err2, parties := sign([]int{0, 2, 3}, 23)
+	if !assert.Nil(t, err2) {
+		return
+	}
+	pk := ecdsa.PublicKey{
+		Curve: tss.EC(),
+		X:     keys[0].ECDSAPub.X(),
+		Y:     keys[0].ECDSAPub.Y(),
+	}
+	for _, P := range parties {
+		sig := &P.(*LocalParty).data
+		assert.True(t, ecdsa.Verify(&pk, msg.FillBytes(make([]byte, 32)), new(big.Int).SetBytes(sig.R), new(big.Int).SetBytes(sig.S)),
+			"ecdsa verify must pass")
+	}
+}
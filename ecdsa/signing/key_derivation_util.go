@@ -5,9 +5,9 @@ package signing
 import (
 	"crypto/ecdsa"
 	"crypto/elliptic"
+	"fmt"
 	"math/big"
 
-	"github.com/bnb-chain/tss-lib/common"
 	"github.com/bnb-chain/tss-lib/crypto"
 	"github.com/bnb-chain/tss-lib/crypto/ckd"
 	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
@@ -21,39 +21,41 @@ func UpdatePublicKeyAndAdjustBigXj(keyDerivationDelta *big.Int, keys []keygen.Lo
 	for k := range keys {
 		keys[k].ECDSAPub, err = crypto.NewECPoint(ec, extendedChildPk.X, extendedChildPk.Y)
 		if err != nil {
-			common.Logger.Errorf("error creating new extended child public key")
-			return err
+			return fmt.Errorf("creating the extended child public key: %w", err)
 		}
 		// Suppose X_j has shamir shares X_j0,     X_j1,     ..., X_jn
 		// So X_j + D has shamir shares  X_j0 + D, X_j1 + D, ..., X_jn + D
+		// With a hierarchical key, the derivatives of rank 1 and above don't depend on the constant term
 		for j := range keys[k].BigXj {
+			if keys[k].IsHierarchical() && keys[k].Ranks[j] != 0 {
+				continue
+			}
 			keys[k].BigXj[j], err = keys[k].BigXj[j].Add(gDelta)
 			if err != nil {
-				common.Logger.Errorf("error in delta operation")
-				return err
+				return fmt.Errorf("adding the key derivation delta to BigXj[%d]: %w", j, err)
+			}
+		}
+		if keys[k].IsWeighted() {
+			// the same holds at every evaluation point; fresh slices leave the caller's save data untouched
+			weighted := make([][]*crypto.ECPoint, len(keys[k].WeightedBigXj))
+			for j, bigXjs := range keys[k].WeightedBigXj {
+				weighted[j] = make([]*crypto.ECPoint, len(bigXjs))
+				for m, bigXjm := range bigXjs {
+					if weighted[j][m], err = bigXjm.Add(gDelta); err != nil {
+						return fmt.Errorf("adding the key derivation delta to WeightedBigXj[%d][%d]: %w", j, m, err)
+					}
+				}
 			}
+			keys[k].WeightedBigXj = weighted
 		}
 	}
 	return nil
 }
 
 func derivingPubkeyFromPath(masterPub *crypto.ECPoint, chainCode []byte, path []uint32, ec elliptic.Curve) (*big.Int, *ckd.ExtendedKey, error) {
-	// build ecdsa key pair
-	pk := ecdsa.PublicKey{
-		Curve: ec,
-		X:     masterPub.X(),
-		Y:     masterPub.Y(),
+	extendedParentPk, err := ckd.NewMasterPublicKey(masterPub.ToECDSAPubKey(), chainCode, &chaincfg.MainNetParams, ckd.ScriptP2PKH)
+	if err != nil {
+		return nil, nil, err
 	}
-
-	net := &chaincfg.MainNetParams
-	extendedParentPk := &ckd.ExtendedKey{
-		PublicKey:  pk,
-		Depth:      0,
-		ChildIndex: 0,
-		ChainCode:  chainCode[:],
-		ParentFP:   []byte{0x00, 0x00, 0x00, 0x00},
-		Version:    net.HDPrivateKeyID[:],
-	}
-
 	return ckd.DeriveChildKeyFromHierarchy(path, extendedParentPk, ec.Params().N, ec)
 }
@@ -93,8 +93,13 @@ type (
 		Ti *crypto.ECPoint
 		DTelda cmt.HashDeCommitment
 
-		ssid      []byte
-		ssidNonce *big.Int
+		ssid           []byte
+		ssidNonce      *big.Int
+		messageBinding []byte
+
+		// set when signing a raw message
+		hashID common.MessageHash
+		digest []byte
 	}
 )
 
@@ -164,6 +169,59 @@ func NewLocalPartyWithKDD(
 	return p
 }
 
+// NewLocalPartyFromRawMessage hashes the raw `msg` with `hash` and signs the digest, so callers no longer
+// convert the digest to a big.Int or pick fullBytesLen themselves. All signers commit to the hash choice in
+// round 1; the output SignatureData.M holds the digest and HashId the hash used.
+func NewLocalPartyFromRawMessage(
+	msg []byte,
+	hash common.MessageHash,
+	params *tss.Parameters,
+	key keygen.LocalPartySaveData,
+	out chan<- tss.Message,
+	end chan<- common.SignatureData,
+) tss.Party {
+	return NewLocalPartyFromRawMessageWithKDD(msg, hash, params, key, nil, out, end)
+}
+
+// NewLocalPartyFromRawMessageWithKDD is NewLocalPartyFromRawMessage with a key derivation delta for HD support.
+func NewLocalPartyFromRawMessageWithKDD(
+	msg []byte,
+	hash common.MessageHash,
+	params *tss.Parameters,
+	key keygen.LocalPartySaveData,
+	keyDerivationDelta *big.Int,
+	out chan<- tss.Message,
+	end chan<- common.SignatureData,
+) tss.Party {
+	digest, err := hash.Digest(msg)
+	if err != nil {
+		panic(fmt.Errorf("NewLocalPartyFromRawMessageWithKDD: %v", err))
+	}
+	if params == nil || params.EC() == nil || params.EC().Params() == nil || params.EC().Params().N == nil {
+		panic(errors.New("NewLocalPartyFromRawMessageWithKDD: params with a curve order is required"))
+	}
+	m, fullBytesLen := digestToInt(digest, params.EC().Params().N)
+	p := NewLocalPartyWithKDD(m, params, key, keyDerivationDelta, out, end, fullBytesLen).(*LocalParty)
+	p.temp.hashID = hash
+	p.temp.digest = digest
+	return p
+}
+
+// digestToInt converts a digest to the ECDSA message scalar the way crypto/ecdsa does: the digest is truncated
+// to the bit length of the curve order and then reduced modulo it. It also returns the byte width of the scalar.
+func digestToInt(digest []byte, N *big.Int) (*big.Int, int) {
+	orderBits := N.BitLen()
+	orderBytes := (orderBits + 7) / 8
+	if len(digest) > orderBytes {
+		digest = digest[:orderBytes]
+	}
+	m := new(big.Int).SetBytes(digest)
+	if excess := len(digest)*8 - orderBits; excess > 0 {
+		m.Rsh(m, uint(excess))
+	}
+	return m.Mod(m, N), len(digest)
+}
+
 func validateFullBytesLen(caller string, msg *big.Int, params *tss.Parameters, fullBytesLen []int) int {
 	if len(fullBytesLen) != 1 {
 		panic(fmt.Errorf("%s: fullBytesLen is required and must match all signing parties", caller))
@@ -295,7 +353,7 @@ func (p *LocalParty) StoreMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
 		}
 		p.temp.signRound9Messages[fromPIdx] = msg
 	default: // unrecognised message, just ignore!
-		common.Logger.Warningf("unrecognised message ignored: %v", msg)
+		p.params.Logger().With(tss.LogKeyTask, TaskName).Warn("unrecognised message ignored", "msg", msg.String())
 		return false, nil
 	}
 	return true, nil
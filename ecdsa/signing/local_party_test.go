@@ -8,10 +8,12 @@ package signing
 
 import (
 	"crypto/ecdsa"
+	"crypto/elliptic"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"math/big"
+	mrand "math/rand"
 	"runtime"
 	"strings"
 	"sync/atomic"
@@ -23,7 +25,9 @@ import (
 
 	"github.com/bnb-chain/tss-lib/common"
 	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/metrics"
 	"github.com/bnb-chain/tss-lib/test"
+	"github.com/bnb-chain/tss-lib/test/netsim"
 	"github.com/bnb-chain/tss-lib/tss"
 )
 
@@ -114,9 +118,11 @@ func TestE2EConcurrent(t *testing.T) {
 
 	// init the parties
 	ceremonyNonce := big.NewInt(1)
+	registry := metrics.NewRegistry()
 	for i := 0; i < len(signPIDs); i++ {
 		params := tss.NewParameters(tss.S256(), p2pCtx, signPIDs[i], len(signPIDs), threshold)
 		params.SetSessionNonce(ceremonyNonce)
+		params.SetObserver(registry)
 
 		P := NewLocalParty(msgInt, params, keys[i], outCh, endCh, len(msgData)).(*LocalParty)
 		parties = append(parties, P)
@@ -184,12 +190,49 @@ signing:
 				t.Log("ECDSA signing test done.")
 				// END ECDSA verify
 
+				n := uint64(len(signPIDs))
+				for proof, perPeer := range map[string]uint64{
+					tss.ProofMtARange: 2, tss.ProofMtABob: 1, tss.ProofMtABobWC: 1, tss.ProofSchnorr: 3,
+				} {
+					count, _ := registry.Histogram(metrics.ProofDuration, TaskName, proof, "true")
+					assert.Equal(t, perPeer*n*(n-1), count, proof)
+				}
+				assert.Equal(t, float64(n), registry.Counter(metrics.RoundsStarted, TaskName, "10"), "every party should finalize")
+
 				break signing
 			}
 		}
 	}
 }
 
+func TestE2ESeededRandIsReproducible(t *testing.T) {
+	setUp("info")
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	msg := big.NewInt(42)
+
+	sign := func(seed int64) []byte {
+		net := netsim.New(netsim.Config{})
+		p2pCtx := tss.NewPeerContext(signPIDs)
+		endCh := make(chan common.SignatureData, len(signPIDs))
+		parties := make([]tss.Party, 0, len(signPIDs))
+		for i := range signPIDs {
+			params := tss.NewParameters(tss.S256(), p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+			params.SetSessionNonce(big.NewInt(1))
+			params.SetRand(mrand.New(mrand.NewSource(seed + int64(i))))
+			parties = append(parties, NewLocalParty(msg, params, keys[i], net.Out(), endCh, 32))
+		}
+		report := net.Run(parties)
+		if !assert.True(t, report.AllFinished(), "signing must finish: %v", report.Errors) {
+			t.FailNow()
+		}
+		return (<-endCh).Signature
+	}
+
+	// with every party drawing from a seeded reader, the nonce k and so the whole signature are fixed
+	assert.Equal(t, sign(1), sign(1))
+}
+
 func TestE2EWithHDKeyDerivation(t *testing.T) {
 	setUp("info")
 	threshold := testThreshold
@@ -300,6 +343,167 @@ signing:
 	}
 }
 
+func TestE2ERawMessageKeccak256(t *testing.T) {
+	setUp("info")
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]*LocalParty, 0, len(signPIDs))
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan common.SignatureData, len(signPIDs))
+	updater := test.SharedPartyUpdater
+
+	raw := []byte("raw message signed with keccak-256")
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(tss.S256(), p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+		params.SetSessionNonce(big.NewInt(1))
+		P := NewLocalPartyFromRawMessage(raw, common.MessageHashKeccak256, params, keys[i], outCh, endCh).(*LocalParty)
+		parties = append(parties, P)
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	digest, _ := common.MessageHashKeccak256.Digest(raw)
+	var ended int
+signing:
+	for {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+		case msg := <-outCh:
+			dest := msg.GetTo()
+			if dest == nil {
+				for _, P := range parties {
+					if P.PartyID().Index == msg.GetFrom().Index {
+						continue
+					}
+					go updater(P, msg, errCh)
+				}
+			} else {
+				go updater(parties[dest[0].Index], msg, errCh)
+			}
+		case <-endCh:
+			if ended++; ended < len(signPIDs) {
+				continue
+			}
+			data := &parties[0].data
+			assert.Equal(t, digest, data.M)
+			assert.Equal(t, uint32(common.MessageHashKeccak256), data.HashId)
+			pk := ecdsa.PublicKey{
+				Curve: tss.EC(),
+				X:     keys[0].ECDSAPub.X(),
+				Y:     keys[0].ECDSAPub.Y(),
+			}
+			ok := ecdsa.Verify(&pk, digest, new(big.Int).SetBytes(data.R), new(big.Int).SetBytes(data.S))
+			assert.True(t, ok, "ecdsa verify must pass for the digest")
+			break signing
+		}
+	}
+}
+
+func TestNewLocalPartyFromRawMessage_UnknownHash(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(2)
+	params := tss.NewParameters(tss.S256(), tss.NewPeerContext(pIDs), pIDs[0], len(pIDs), 1)
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("expected a panic for an unsupported message hash")
+		}
+		assert.Contains(t, fmt.Sprint(r), "unsupported message hash")
+	}()
+	NewLocalPartyFromRawMessage([]byte("abc"), common.MessageHashNone, params, keygen.LocalPartySaveData{}, nil, nil)
+}
+
+func TestDigestToInt(t *testing.T) {
+	// a 32-byte digest on a 224-bit order keeps the leading 224 bits, as crypto/ecdsa does
+	N := elliptic.P224().Params().N
+	digest := make([]byte, 32)
+	for i := range digest {
+		digest[i] = 0xff
+	}
+	m, length := digestToInt(digest, N)
+	assert.Equal(t, 28, length)
+	want := new(big.Int).Mod(new(big.Int).SetBytes(digest[:28]), N)
+	assert.Equal(t, 0, want.Cmp(m))
+
+	// a digest above the order is reduced so that round 1 accepts it
+	m, length = digestToInt(digest, tss.S256().Params().N)
+	assert.Equal(t, 32, length)
+	assert.True(t, m.Cmp(tss.S256().Params().N) < 0)
+}
+
+// TestE2EMessageDisagreementNamesParty hands one signer a different message. Every party must stop in
+// round 2, and the honest ones must name exactly that signer rather than failing in finalize.
+func TestE2EMessageDisagreementNamesParty(t *testing.T) {
+	setUp("info")
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]*LocalParty, 0, len(signPIDs))
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan common.SignatureData, len(signPIDs))
+	updater := test.SharedPartyUpdater
+
+	const odd = 3
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(tss.S256(), p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+		params.SetSessionNonce(big.NewInt(1))
+		msg := big.NewInt(42)
+		if i == odd {
+			msg = big.NewInt(43)
+		}
+		P := NewLocalParty(msg, params, keys[i], outCh, endCh, 32).(*LocalParty)
+		parties = append(parties, P)
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	failed := make(map[int]*tss.Error, len(signPIDs))
+	for len(failed) < len(signPIDs) {
+		select {
+		case err := <-errCh:
+			if _, ok := failed[err.Victim().Index]; !ok {
+				failed[err.Victim().Index] = err
+			}
+		case msg := <-outCh:
+			dest := msg.GetTo()
+			if dest == nil {
+				for _, P := range parties {
+					if P.PartyID().Index == msg.GetFrom().Index {
+						continue
+					}
+					go updater(P, msg, errCh)
+				}
+			} else {
+				go updater(parties[dest[0].Index], msg, errCh)
+			}
+		case <-endCh:
+			t.Fatal("signing must not finish when a signer holds a different message")
+		}
+	}
+	for i, err := range failed {
+		assert.Equal(t, 2, err.Round())
+		assert.Contains(t, err.Error(), "hold a different message")
+		if i == odd {
+			assert.Len(t, err.Culprits(), len(signPIDs)-1)
+			continue
+		}
+		if assert.Len(t, err.Culprits(), 1) {
+			assert.Equal(t, odd, err.Culprits()[0].Index)
+		}
+	}
+}
+
 // TestSigning_Start_RequiresSessionNonce pins that signing fails closed
 // when no SessionNonce is set. Previously the round-1 code fell back to
 // SHA512_256(messageBytes), making two concurrent ceremonies on the same
@@ -438,3 +642,44 @@ func fillBytes(x *big.Int, buf []byte) []byte {
 	}
 	return buf
 }
+
+// runTestKeygen runs keygen on a simulated network over `n` fixture parties, each with the parameters `newParams`
+// returns for it, and returns the save data in party order
+func runTestKeygen(t *testing.T, n int, newParams func(p2pCtx *tss.PeerContext, pID *tss.PartyID) *tss.Parameters) ([]keygen.LocalPartySaveData, tss.SortedPartyIDs) {
+	fixtures, pIDs, err := keygen.LoadKeygenTestFixtures(n)
+	if err != nil {
+		t.Skip("test fixtures required (LocalPreParams) to run a quick keygen")
+	}
+	net := netsim.New(netsim.Config{})
+	p2pCtx := tss.NewPeerContext(pIDs)
+	endCh := make(chan keygen.LocalPartySaveData, len(pIDs))
+	parties := make([]tss.Party, 0, len(pIDs))
+	for i := range pIDs {
+		parties = append(parties, keygen.NewLocalParty(newParams(p2pCtx, pIDs[i]), net.Out(), endCh, fixtures[i].LocalPreParams))
+	}
+	report := net.Run(parties)
+	if !assert.True(t, report.AllFinished(), "keygen must finish: %v", report.Errors) {
+		t.FailNow()
+	}
+	keys := make([]keygen.LocalPartySaveData, len(pIDs))
+	for range pIDs {
+		save := <-endCh
+		index, err := save.OriginalIndex()
+		assert.NoError(t, err)
+		keys[index] = save
+	}
+	return keys, pIDs
+}
+
+func routeTestMessage(parties []tss.Party, msg tss.Message, errCh chan<- *tss.Error) {
+	dest := msg.GetTo()
+	if dest == nil {
+		for _, P := range parties {
+			if P.PartyID().Index != msg.GetFrom().Index {
+				go test.SharedPartyUpdater(P, msg, errCh)
+			}
+		}
+		return
+	}
+	go test.SharedPartyUpdater(parties[dest[0].Index], msg, errCh)
+}
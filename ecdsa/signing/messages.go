@@ -34,6 +34,8 @@ var (
 		(*SignRound7Message)(nil),
 		(*SignRound8Message)(nil),
 		(*SignRound9Message)(nil),
+		(*SignReadyMessage)(nil),
+		(*SignBatchMessage)(nil),
 	}
 )
 
@@ -77,13 +79,15 @@ func (m *SignRound1Message1) UnmarshalRangeProofAlice() (*mta.RangeProofAlice, e
 func NewSignRound1Message2(
 	from *tss.PartyID,
 	commitment cmt.HashCommitment,
+	messageBinding []byte,
 ) tss.ParsedMessage {
 	meta := tss.MessageRouting{
 		From:        from,
 		IsBroadcast: true,
 	}
 	content := &SignRound1Message2{
-		Commitment: commitment.Bytes(),
+		Commitment:     commitment.Bytes(),
+		MessageBinding: messageBinding,
 	}
 	msg := tss.NewMessageWrapper(meta, content)
 	return tss.NewMessage(meta, content, msg)
@@ -91,7 +95,8 @@ func NewSignRound1Message2(
 
 func (m *SignRound1Message2) ValidateBasic() bool {
 	return m.Commitment != nil &&
-		common.NonEmptyBytes(m.GetCommitment())
+		common.NonEmptyBytes(m.GetCommitment()) &&
+		common.NonEmptyBytes(m.GetMessageBinding())
 }
 
 func (m *SignRound1Message2) UnmarshalCommitment() *big.Int {
@@ -389,3 +394,73 @@ func (m *SignRound9Message) ValidateBasic() bool {
 func (m *SignRound9Message) UnmarshalS() *big.Int {
 	return new(big.Int).SetBytes(m.S)
 }
+
+// ----- //
+
+func NewSignReadyMessage(
+	from *tss.PartyID,
+	sessionID, msgHash []byte,
+	proof *schnorr.ZKProof,
+) tss.ParsedMessage {
+	meta := tss.MessageRouting{
+		From:        from,
+		IsBroadcast: true,
+	}
+	content := &SignReadyMessage{
+		SessionId:   sessionID,
+		MessageHash: msgHash,
+		ProofAlphaX: proof.Alpha.X().Bytes(),
+		ProofAlphaY: proof.Alpha.Y().Bytes(),
+		ProofT:      proof.T.Bytes(),
+	}
+	msg := tss.NewMessageWrapper(meta, content)
+	return tss.NewMessage(meta, content, msg)
+}
+
+func (m *SignReadyMessage) ValidateBasic() bool {
+	return m != nil &&
+		common.NonEmptyBytes(m.SessionId) &&
+		common.NonEmptyBytes(m.MessageHash) &&
+		common.NonEmptyBytes(m.ProofAlphaX) &&
+		common.NonEmptyBytes(m.ProofAlphaY) &&
+		common.NonEmptyBytes(m.ProofT)
+}
+
+func (m *SignReadyMessage) UnmarshalZKProof(ec elliptic.Curve) (*schnorr.ZKProof, error) {
+	point, err := crypto.NewECPoint(
+		ec,
+		new(big.Int).SetBytes(m.GetProofAlphaX()),
+		new(big.Int).SetBytes(m.GetProofAlphaY()))
+	if err != nil {
+		return nil, err
+	}
+	return &schnorr.ZKProof{
+		Alpha: point,
+		T:     new(big.Int).SetBytes(m.GetProofT()),
+	}, nil
+}
+
+// ----- //
+
+// NewSignBatchMessage bundles the wire bytes of one round message per batched message. A nil `to` broadcasts it.
+func NewSignBatchMessage(
+	to []*tss.PartyID,
+	from *tss.PartyID,
+	items [][]byte,
+) tss.ParsedMessage {
+	meta := tss.MessageRouting{
+		From:        from,
+		To:          to,
+		IsBroadcast: to == nil,
+	}
+	content := &SignBatchMessage{
+		Items: items,
+	}
+	msg := tss.NewMessageWrapper(meta, content)
+	return tss.NewMessage(meta, content, msg)
+}
+
+func (m *SignBatchMessage) ValidateBasic() bool {
+	return m != nil &&
+		common.NonEmptyMultiBytes(m.Items)
+}
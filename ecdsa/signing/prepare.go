@@ -8,11 +8,13 @@ package signing
 
 import (
 	"crypto/elliptic"
+	"errors"
 	"fmt"
 	"math/big"
 
 	"github.com/bnb-chain/tss-lib/common"
 	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/crypto/vss"
 )
 
 // PrepareForSigning(), GG18Spec (11) Fig. 14
@@ -70,3 +72,100 @@ func PrepareForSigning(ec elliptic.Curve, i, pax int, xi *big.Int, ks []*big.Int
 	}
 	return wi, bigWs, nil
 }
+
+// PrepareForWeightedSigning is PrepareForSigning for a key generated with weighted sharing. Party j holds the
+// shares at the evaluation points ks[j], whose public shares are bigXs[j], and xis are party i's own shares. The
+// Lagrange coefficients are taken over the evaluation points of all signers together, so a party's shares add
+// up to a single additive share wi and the rest of the protocol, including its Paillier key, is unchanged.
+func PrepareForWeightedSigning(ec elliptic.Curve, i, threshold int, xis []*big.Int, ks [][]*big.Int, bigXs [][]*crypto.ECPoint) (wi *big.Int, bigWs []*crypto.ECPoint, err error) {
+	modQ := common.ModInt(ec.Params().N)
+	if len(ks) != len(bigXs) {
+		return nil, nil, fmt.Errorf("PrepareForWeightedSigning: len(ks) != len(bigXs) (%d != %d)", len(ks), len(bigXs))
+	}
+	if len(ks) <= i {
+		return nil, nil, fmt.Errorf("PrepareForWeightedSigning: len(ks) <= i (%d <= %d)", len(ks), i)
+	}
+	if len(xis) != len(ks[i]) {
+		return nil, nil, fmt.Errorf("PrepareForWeightedSigning: got %d shares for %d evaluation points", len(xis), len(ks[i]))
+	}
+	var flat []*big.Int
+	for j := range ks {
+		if len(ks[j]) == 0 || len(ks[j]) != len(bigXs[j]) {
+			return nil, nil, fmt.Errorf("PrepareForWeightedSigning: party %d has %d evaluation points and %d public shares", j, len(ks[j]), len(bigXs[j]))
+		}
+		flat = append(flat, ks[j]...)
+	}
+	if len(flat) <= threshold {
+		return nil, nil, fmt.Errorf("PrepareForWeightedSigning: the signers hold %d shares, more than t=%d are required", len(flat), threshold)
+	}
+	// lambda returns the Lagrange coefficient at 0 of the evaluation point flat[idx] over all the points in `flat`
+	lambda := func(idx int) (*big.Int, error) {
+		coef := big.NewInt(1)
+		for c, kc := range flat {
+			if c == idx {
+				continue
+			}
+			diff := modQ.Sub(kc, flat[idx])
+			if diff.Sign() == 0 {
+				return nil, errors.New("PrepareForWeightedSigning: evaluation points collide mod q")
+			}
+			coef = modQ.Mul(coef, modQ.Mul(kc, modQ.ModInverse(diff)))
+		}
+		return coef, nil
+	}
+
+	wi = big.NewInt(0)
+	bigWs = make([]*crypto.ECPoint, len(ks))
+	idx := 0
+	for j := range ks {
+		for m := range ks[j] {
+			coef, err := lambda(idx)
+			if err != nil {
+				return nil, nil, err
+			}
+			idx++
+			if j == i {
+				wi = modQ.Add(wi, modQ.Mul(xis[m], coef))
+			}
+			term := bigXs[j][m].ScalarMult(coef)
+			if bigWs[j] == nil {
+				bigWs[j] = term
+				continue
+			}
+			if bigWs[j], err = bigWs[j].Add(term); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	return wi, bigWs, nil
+}
+
+// PrepareForHierarchicalSigning is PrepareForSigning for a key generated with a hierarchical access structure,
+// where the share of the party at ks[j] is of the ranks[j]-th derivative of the polynomial. The Lagrange
+// coefficients are replaced with the Birkhoff interpolation coefficients of the signers; it returns an error
+// wrapping vss.ErrUnauthorizedSet if the signers are not authorized to sign.
+func PrepareForHierarchicalSigning(ec elliptic.Curve, i, threshold int, xi *big.Int, ks []*big.Int, ranks []int, bigXs []*crypto.ECPoint) (wi *big.Int, bigWs []*crypto.ECPoint, err error) {
+	modQ := common.ModInt(ec.Params().N)
+	if len(ks) != len(bigXs) || len(ks) != len(ranks) {
+		return nil, nil, fmt.Errorf("PrepareForHierarchicalSigning: got %d ks, %d ranks and %d bigXs", len(ks), len(ranks), len(bigXs))
+	}
+	if len(ks) <= i {
+		return nil, nil, fmt.Errorf("PrepareForHierarchicalSigning: len(ks) <= i (%d <= %d)", len(ks), i)
+	}
+	if len(ks) <= threshold {
+		return nil, nil, fmt.Errorf("PrepareForHierarchicalSigning: t+1=%d is not satisfied by the key count of %d: %w", threshold+1, len(ks), vss.ErrUnauthorizedSet)
+	}
+	if _, err = vss.CheckIndexes(ec, ks); err != nil {
+		return nil, nil, fmt.Errorf("PrepareForHierarchicalSigning: %w", err)
+	}
+	coefs, err := vss.BirkhoffCoefficients(ec, threshold, ks, ranks)
+	if err != nil {
+		return nil, nil, fmt.Errorf("PrepareForHierarchicalSigning: signers with ranks %v: %w", ranks, err)
+	}
+	wi = modQ.Mul(xi, coefs[i])
+	bigWs = make([]*crypto.ECPoint, len(ks))
+	for j, bigXj := range bigXs {
+		bigWs[j] = bigXj.ScalarMult(coefs[j])
+	}
+	return wi, bigWs, nil
+}
@@ -0,0 +1,163 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/crypto/schnorr"
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+var readyDomain = []byte("tss-lib/ecdsa/signing/ready")
+
+// ReadySet is the outcome of the readiness round: the t+1 signers every honest party derives from the same
+// announcements and the session nonce they sign with.
+type ReadySet struct {
+	Signers      tss.SortedPartyIDs
+	SessionNonce *big.Int
+	Threshold    int
+	// Culprits sent announcements with an invalid proof
+	Culprits []*tss.PartyID
+	// Disagreeing parties announced readiness for a different message hash and were not picked
+	Disagreeing []*tss.PartyID
+}
+
+// NewReadyAnnouncement builds the readiness broadcast for `sessionID` and `msgHash`, signed by proving knowledge of
+// the local share Xi of `key`. It is sent to all n keygen parties, not just to the eventual signers.
+func NewReadyAnnouncement(ec elliptic.Curve, from *tss.PartyID, key keygen.LocalPartySaveData, sessionID, msgHash []byte) (tss.ParsedMessage, error) {
+	if len(sessionID) < 16 {
+		return nil, errors.New("ready announcement: session ID must be at least 16 bytes")
+	}
+	if len(msgHash) == 0 {
+		return nil, errors.New("ready announcement: message hash is empty")
+	}
+	if key.Xi == nil {
+		return nil, errors.New("ready announcement: the save data holds no share")
+	}
+	Xi := crypto.ScalarBaseMult(ec, key.Xi)
+	proof, err := schnorr.NewZKProofWithSession(readySession(sessionID, msgHash, from.KeyInt()), key.Xi, Xi)
+	if err != nil {
+		return nil, err
+	}
+	return NewSignReadyMessage(from, sessionID, msgHash, proof), nil
+}
+
+// AgreeOnReadySigners picks the t+1 signers and the session nonce from the ready announcements collected for
+// `sessionID` and `msgHash`. Every honest party that feeds it the same announcements gets the same ReadySet, so the
+// collection window must close on the same set everywhere (e.g. all n announcements, or a broadcast channel with
+// agreed delivery). Each announcement is checked against the sender's BigXj in `key`; announcements for another
+// session are ignored and only the first valid announcement of a party counts; a party is a culprit when it has no
+// valid announcement but an invalid one.
+func AgreeOnReadySigners(
+	ec elliptic.Curve,
+	key keygen.LocalPartySaveData,
+	parties tss.SortedPartyIDs,
+	threshold int,
+	sessionID, msgHash []byte,
+	msgs []tss.ParsedMessage,
+) (*ReadySet, error) {
+	keyIdx := make(map[string]int, len(key.Ks))
+	for j, kj := range key.Ks {
+		keyIdx[kj.String()] = j
+	}
+	set := &ReadySet{Threshold: threshold}
+	ready := make(tss.SortedPartyIDs, 0, len(parties))
+	seen := make(map[string]struct{}, len(parties))
+	invalid := make([]*tss.PartyID, 0, len(parties))
+	for _, msg := range msgs {
+		content, ok := msg.Content().(*SignReadyMessage)
+		if !ok || !msg.IsBroadcast() {
+			continue
+		}
+		from := parties.FindByKey(msg.GetFrom().KeyInt())
+		if from == nil {
+			continue
+		}
+		if _, ok := seen[from.KeyInt().String()]; ok {
+			continue
+		}
+		if !bytes.Equal(content.GetSessionId(), sessionID) {
+			continue
+		}
+		j, ok := keyIdx[from.KeyInt().String()]
+		if !ok || !content.ValidateBasic() {
+			invalid = append(invalid, from)
+			continue
+		}
+		proof, err := content.UnmarshalZKProof(ec)
+		if err != nil || !proof.VerifyWithSession(readySession(sessionID, content.GetMessageHash(), from.KeyInt()), key.BigXj[j]) {
+			invalid = append(invalid, from)
+			continue
+		}
+		seen[from.KeyInt().String()] = struct{}{}
+		if !bytes.Equal(content.GetMessageHash(), msgHash) {
+			set.Disagreeing = append(set.Disagreeing, from)
+			continue
+		}
+		ready = append(ready, from)
+	}
+	// an invalid announcement may have been injected in a party's name, so it only counts without a valid one
+	for _, Pj := range invalid {
+		if _, ok := seen[Pj.KeyInt().String()]; !ok {
+			seen[Pj.KeyInt().String()] = struct{}{}
+			set.Culprits = append(set.Culprits, Pj)
+		}
+	}
+	signers, err := selectSigners(ready, threshold, nil, common.SHA512_256(readyDomain, sessionID, msgHash), nil)
+	if err != nil {
+		return set, fmt.Errorf("readiness: %w", err)
+	}
+	set.Signers = signers
+	set.SessionNonce = common.SHA512_256i(append([]*big.Int{
+		new(big.Int).SetBytes(sessionID),
+		new(big.Int).SetBytes(msgHash),
+	}, signers.Keys()...)...)
+	return set, nil
+}
+
+// Parameters returns the signing parameters of `partyID` for the agreed signers, with the session nonce set.
+// It fails when the party was not picked.
+func (set *ReadySet) Parameters(ec elliptic.Curve, partyID *tss.PartyID) (*tss.Parameters, error) {
+	self := set.Signers.FindByKey(partyID.KeyInt())
+	if self == nil {
+		return nil, errors.New("readiness: the party was not picked to sign")
+	}
+	params := tss.NewParameters(ec, tss.NewPeerContext(set.Signers), self, len(set.Signers), set.Threshold)
+	params.SetSessionNonce(set.SessionNonce)
+	return params, nil
+}
+
+// NewLocalPartyWithKDD builds the signing party of `partyID` for the agreed signers.
+// The other arguments are those of the package-level NewLocalPartyWithKDD.
+func (set *ReadySet) NewLocalPartyWithKDD(
+	ec elliptic.Curve,
+	partyID *tss.PartyID,
+	msg *big.Int,
+	key keygen.LocalPartySaveData,
+	keyDerivationDelta *big.Int,
+	out chan<- tss.Message,
+	end chan<- common.SignatureData,
+	fullBytesLen ...int,
+) (tss.Party, error) {
+	params, err := set.Parameters(ec, partyID)
+	if err != nil {
+		return nil, err
+	}
+	return NewLocalPartyWithKDD(msg, params, key, keyDerivationDelta, out, end, fullBytesLen...), nil
+}
+
+func readySession(sessionID, msgHash []byte, k *big.Int) []byte {
+	return common.SHA512_256(readyDomain, sessionID, msgHash, k.Bytes())
+}
@@ -0,0 +1,114 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+func TestAgreeOnReadySigners(t *testing.T) {
+	keys, pIDs, err := keygen.LoadKeygenTestFixtures(testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	sessionID := []byte("readiness-test-session-0001")
+	msgHash := common.SHA512_256([]byte("message"))
+
+	const offline, disagreeing, forger = 3, 5, 7
+	msgs := make([]tss.ParsedMessage, 0, len(pIDs))
+	for i, pID := range pIDs {
+		var msg tss.ParsedMessage
+		switch i {
+		case offline:
+			continue
+		case disagreeing:
+			msg, err = NewReadyAnnouncement(tss.S256(), pID, keys[i], sessionID, common.SHA512_256([]byte("other")))
+		case forger:
+			// proves knowledge of someone else's share
+			msg, err = NewReadyAnnouncement(tss.S256(), pID, keys[0], sessionID, msgHash)
+		default:
+			msg, err = NewReadyAnnouncement(tss.S256(), pID, keys[i], sessionID, msgHash)
+		}
+		assert.NoError(t, err)
+		msgs = append(msgs, msg)
+	}
+	// a second announcement of the same party does not count twice
+	msgs = append(msgs, msgs[0])
+
+	var first *ReadySet
+	for i := range pIDs {
+		set, err := AgreeOnReadySigners(tss.S256(), keys[i], pIDs, testThreshold, sessionID, msgHash, msgs)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Len(t, set.Signers, testThreshold+1)
+		if first == nil {
+			first = set
+			continue
+		}
+		assert.Equal(t, first.Signers.Keys(), set.Signers.Keys(), "every party must agree on the signers")
+		assert.Equal(t, 0, first.SessionNonce.Cmp(set.SessionNonce), "every party must agree on the session nonce")
+	}
+	if assert.Len(t, first.Culprits, 1) {
+		assert.Equal(t, pIDs[forger].KeyInt(), first.Culprits[0].KeyInt())
+	}
+	if assert.Len(t, first.Disagreeing, 1) {
+		assert.Equal(t, pIDs[disagreeing].KeyInt(), first.Disagreeing[0].KeyInt())
+	}
+	for _, i := range []int{offline, disagreeing, forger} {
+		assert.Nil(t, first.Signers.FindByKey(pIDs[i].KeyInt()))
+		_, err := first.Parameters(tss.S256(), pIDs[i])
+		assert.Error(t, err)
+	}
+
+	signer := first.Signers[0]
+	params, err := first.Parameters(tss.S256(), signer)
+	assert.NoError(t, err)
+	assert.Equal(t, signer.Index, params.PartyID().Index)
+	assert.Equal(t, 0, params.SessionNonce().Cmp(first.SessionNonce))
+
+	// too few announcements for the threshold
+	_, err = AgreeOnReadySigners(tss.S256(), keys[0], pIDs, testThreshold, sessionID, msgHash, msgs[:testThreshold])
+	assert.True(t, errors.Is(err, ErrNotEnoughSigners))
+
+	// announcements for another session do not count
+	other, err := NewReadyAnnouncement(tss.S256(), pIDs[0], keys[0], []byte("readiness-test-session-0002"), msgHash)
+	assert.NoError(t, err)
+	set, err := AgreeOnReadySigners(tss.S256(), keys[0], pIDs, testThreshold, sessionID, msgHash, []tss.ParsedMessage{other})
+	assert.True(t, errors.Is(err, ErrNotEnoughSigners))
+	assert.Empty(t, set.Culprits, "announcements for other sessions are ignored")
+}
+
+func TestAgreeOnReadySignersInvalidBeforeValid(t *testing.T) {
+	keys, pIDs, err := keygen.LoadKeygenTestFixtures(testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	sessionID := []byte("readiness-test-session-0003")
+	msgHash := common.SHA512_256([]byte("message"))
+
+	// an announcement injected in P[1]'s name, proving knowledge of P[0]'s share, arrives before P[1]'s own
+	forged, err := NewReadyAnnouncement(tss.S256(), pIDs[1], keys[0], sessionID, msgHash)
+	assert.NoError(t, err)
+	msgs := []tss.ParsedMessage{forged}
+	for i := 0; i <= testThreshold; i++ {
+		msg, err := NewReadyAnnouncement(tss.S256(), pIDs[i], keys[i], sessionID, msgHash)
+		assert.NoError(t, err)
+		msgs = append(msgs, msg)
+	}
+
+	set, err := AgreeOnReadySigners(tss.S256(), keys[0], pIDs, testThreshold, sessionID, msgHash, msgs)
+	if !assert.NoError(t, err, "the valid announcement of P[1] must still count") {
+		return
+	}
+	assert.Len(t, set.Signers, testThreshold+1)
+	assert.NotNil(t, set.Signers.FindByKey(pIDs[1].KeyInt()))
+	assert.Empty(t, set.Culprits)
+}
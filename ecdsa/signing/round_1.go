@@ -10,6 +10,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"reflect"
 
 	"github.com/bnb-chain/tss-lib/common"
 	"github.com/bnb-chain/tss-lib/crypto"
@@ -64,11 +65,12 @@ func (round *round1) Start() *tss.Error {
 	}
 	round.temp.ssid = ssid
 
-	k := common.GetRandomPositiveInt(round.Params().EC().Params().N)
-	gamma := common.GetRandomPositiveInt(round.Params().EC().Params().N)
+	rand := round.Params().Rand()
+	k := common.GetRandomPositiveIntFrom(rand, round.Params().EC().Params().N)
+	gamma := common.GetRandomPositiveIntFrom(rand, round.Params().EC().Params().N)
 
 	pointGamma := crypto.ScalarBaseMult(round.Params().EC(), gamma)
-	cmt := commitments.NewHashCommitment(pointGamma.X(), pointGamma.Y())
+	cmt := commitments.NewHashCommitmentFrom(rand, pointGamma.X(), pointGamma.Y())
 	round.temp.k = k
 	round.temp.gamma = gamma
 	round.temp.pointGamma = pointGamma
@@ -82,7 +84,7 @@ func (round *round1) Start() *tss.Error {
 			continue
 		}
 		contextJ := common.AppendUint64ToBytesSlice(round.temp.ssid, uint64(j))
-		cA, pi, err := mta.AliceInit(round.Params().EC(), round.key.PaillierPKs[i], k, round.key.NTildej[j], round.key.H1j[j], round.key.H2j[j], contextJ)
+		cA, pi, err := mta.AliceInitFrom(rand, round.Params().EC(), round.key.PaillierPKs[i], k, round.key.NTildej[j], round.key.H1j[j], round.key.H2j[j], contextJ)
 		if err != nil {
 			return round.WrapError(fmt.Errorf("failed to init mta: %v", err))
 		}
@@ -91,7 +93,8 @@ func (round *round1) Start() *tss.Error {
 		round.out <- r1msg1
 	}
 
-	r1msg2 := NewSignRound1Message2(round.PartyID(), cmt.C)
+	round.temp.messageBinding = round.messageBinding()
+	r1msg2 := NewSignRound1Message2(round.PartyID(), cmt.C, round.temp.messageBinding)
 	round.temp.signRound1Message2s[i] = r1msg2
 	round.out <- r1msg2
 
@@ -135,6 +138,22 @@ func (round *round1) NextRound() tss.Round {
 
 // ----- //
 
+// messageBinding commits to what this party was asked to sign: m, fullBytesLen, the key derivation delta and
+// the hash applied to a raw message, bound to the session. Signers that were handed a different message are
+// caught in round 2 instead of surfacing as an unattributed signature verification failure in finalize.
+func (round *round1) messageBinding() []byte {
+	kdd := zero
+	if round.temp.keyDerivationDelta != nil {
+		kdd = round.temp.keyDerivationDelta
+	}
+	return common.SHA512_256(
+		round.temp.ssid,
+		round.temp.m.Bytes(),
+		new(big.Int).SetInt64(int64(round.temp.fullBytesLen)).Bytes(),
+		kdd.Bytes(),
+		new(big.Int).SetUint64(uint64(round.temp.hashID)).Bytes())
+}
+
 // helper to call into PrepareForSigning()
 func (round *round1) prepare() error {
 	i := round.PartyID().Index
@@ -143,19 +162,44 @@ func (round *round1) prepare() error {
 	ks := round.key.Ks
 	bigXs := round.key.BigXj
 
-	if round.temp.keyDerivationDelta != nil {
+	if round.temp.keyDerivationDelta != nil && (!round.key.IsHierarchical() || round.key.Ranks[i] == 0) {
 		// adding the key derivation delta to the xi's
 		// Suppose x has shamir shares x_0,     x_1,     ..., x_n
 		// So x + D has shamir shares  x_0 + D, x_1 + D, ..., x_n + D
+		// (a hierarchical share of rank 1 and above is of a derivative, which D doesn't change)
 		mod := common.ModInt(round.Params().EC().Params().N)
 		xi = mod.Add(round.temp.keyDerivationDelta, xi)
 		round.key.Xi = xi
 	}
 
-	if round.Threshold()+1 > len(ks) {
-		return fmt.Errorf("t+1=%d is not satisfied by the key count of %d", round.Threshold()+1, len(ks))
+	var wi *big.Int
+	var bigWs []*crypto.ECPoint
+	var err error
+	if round.key.IsWeighted() {
+		xis := round.key.WeightedXi
+		if round.temp.keyDerivationDelta != nil {
+			mod := common.ModInt(round.Params().EC().Params().N)
+			xis = make([]*big.Int, len(round.key.WeightedXi))
+			for m, xim := range round.key.WeightedXi {
+				xis[m] = mod.Add(round.temp.keyDerivationDelta, xim)
+			}
+			round.key.WeightedXi = xis
+		}
+		if weights := round.Params().Weights(); weights != nil && !reflect.DeepEqual(weights, round.key.Weights()) {
+			return fmt.Errorf("the parameters' weights %v do not match the key's %v", weights, round.key.Weights())
+		}
+		wi, bigWs, err = PrepareForWeightedSigning(round.Params().EC(), i, round.Threshold(), xis, round.key.WeightedKs, round.key.WeightedBigXj)
+	} else if round.key.IsHierarchical() {
+		if ranks := round.Params().Ranks(); ranks != nil && !reflect.DeepEqual(ranks, round.key.Ranks) {
+			return fmt.Errorf("the parameters' ranks %v do not match the key's %v", ranks, round.key.Ranks)
+		}
+		wi, bigWs, err = PrepareForHierarchicalSigning(round.Params().EC(), i, round.Threshold(), xi, ks, round.key.Ranks, bigXs)
+	} else {
+		if round.Threshold()+1 > len(ks) {
+			return fmt.Errorf("t+1=%d is not satisfied by the key count of %d", round.Threshold()+1, len(ks))
+		}
+		wi, bigWs, err = PrepareForSigning(round.Params().EC(), i, len(ks), xi, ks, bigXs)
 	}
-	wi, bigWs, err := PrepareForSigning(round.Params().EC(), i, len(ks), xi, ks, bigXs)
 	if err != nil {
 		return err
 	}
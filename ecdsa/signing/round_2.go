@@ -7,8 +7,12 @@
 package signing
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
+	"io"
 	"sync"
+	"time"
 
 	errorspkg "github.com/pkg/errors"
 
@@ -28,10 +32,19 @@ func (round *round2) Start() *tss.Error {
 	i := round.PartyID().Index
 	round.ok[i] = true
 
+	// every signer must have been handed the same m, fullBytesLen, key derivation delta and message hash
+	if culprits := round.messageDisagreements(); len(culprits) > 0 {
+		return round.WrapError(fmt.Errorf("parties %v hold a different message, fullBytesLen, key derivation delta or message hash than %s", culprits, round.PartyID()), culprits...)
+	}
+
 	errChs := make(chan *tss.Error, (len(round.Parties().IDs())-1)*2)
 	wg := sync.WaitGroup{}
 	wg.Add((len(round.Parties().IDs()) - 1) * 2)
 	contextI := common.AppendUint64ToBytesSlice(round.temp.ssid, uint64(i))
+	// Bob's step starts with the check of Alice's range proof, so it is timed as a whole
+	observeRangeProof := func(Pj *tss.PartyID, start time.Time, err error) {
+		round.observeProof(tss.ProofMtARange, Pj, time.Since(start), !errors.Is(err, mta.ErrRangeProofVerify))
+	}
 	attributeBobMidErr := func(err error, Pj *tss.PartyID) *tss.Error {
 		if errors.Is(err, mta.ErrRangeProofVerify) {
 			return round.WrapError(errorspkg.Wrap(err, "peer RangeProofAlice rejected"), Pj)
@@ -42,8 +55,9 @@ func (round *round2) Start() *tss.Error {
 		if j == i {
 			continue
 		}
+		// each goroutine draws from a stream of its own
 		// Bob_mid
-		go func(j int, Pj *tss.PartyID) {
+		go func(j int, Pj *tss.PartyID, rand io.Reader) {
 			defer wg.Done()
 			r1msg := round.temp.signRound1Message1s[j].Content().(*SignRound1Message1)
 			rangeProofAliceJ, err := r1msg.UnmarshalRangeProofAlice()
@@ -51,7 +65,9 @@ func (round *round2) Start() *tss.Error {
 				errChs <- round.WrapError(errorspkg.Wrapf(err, "UnmarshalRangeProofAlice failed"), Pj)
 				return
 			}
-			beta, c1ji, _, pi1ji, err := mta.BobMid(
+			start := time.Now()
+			beta, c1ji, _, pi1ji, err := mta.BobMidFrom(
+				rand,
 				round.Parameters.EC(),
 				round.key.PaillierPKs[j],
 				rangeProofAliceJ,
@@ -64,6 +80,7 @@ func (round *round2) Start() *tss.Error {
 				round.key.H1j[i],
 				round.key.H2j[i],
 				contextI)
+			observeRangeProof(Pj, start, err)
 			// should be thread safe as these are pre-allocated
 			round.temp.betas[j] = beta
 			round.temp.c1jis[j] = c1ji
@@ -71,9 +88,9 @@ func (round *round2) Start() *tss.Error {
 			if err != nil {
 				errChs <- attributeBobMidErr(err, Pj)
 			}
-		}(j, Pj)
+		}(j, Pj, common.ForkRand(round.Params().Rand()))
 		// Bob_mid_wc
-		go func(j int, Pj *tss.PartyID) {
+		go func(j int, Pj *tss.PartyID, rand io.Reader) {
 			defer wg.Done()
 			r1msg := round.temp.signRound1Message1s[j].Content().(*SignRound1Message1)
 			rangeProofAliceJ, err := r1msg.UnmarshalRangeProofAlice()
@@ -81,7 +98,9 @@ func (round *round2) Start() *tss.Error {
 				errChs <- round.WrapError(errorspkg.Wrapf(err, "UnmarshalRangeProofAlice failed"), Pj)
 				return
 			}
-			v, c2ji, _, pi2ji, err := mta.BobMidWC(
+			start := time.Now()
+			v, c2ji, _, pi2ji, err := mta.BobMidWCFrom(
+				rand,
 				round.Parameters.EC(),
 				round.key.PaillierPKs[j],
 				rangeProofAliceJ,
@@ -95,13 +114,14 @@ func (round *round2) Start() *tss.Error {
 				round.key.H2j[i],
 				round.temp.bigWs[i],
 				contextI)
+			observeRangeProof(Pj, start, err)
 			round.temp.vs[j] = v
 			round.temp.c2jis[j] = c2ji
 			round.temp.pi2jis[j] = pi2ji
 			if err != nil {
 				errChs <- attributeBobMidErr(err, Pj)
 			}
-		}(j, Pj)
+		}(j, Pj, common.ForkRand(round.Params().Rand()))
 	}
 	// consume error channels; wait for goroutines
 	wg.Wait()
@@ -147,6 +167,21 @@ func (round *round2) CanAccept(msg tss.ParsedMessage) bool {
 	return false
 }
 
+// messageDisagreements returns the parties whose round 1 message binding differs from ours
+func (round *round2) messageDisagreements() []*tss.PartyID {
+	var culprits []*tss.PartyID
+	for j, Pj := range round.Parties().IDs() {
+		if j == round.PartyID().Index {
+			continue
+		}
+		r1msg2 := round.temp.signRound1Message2s[j].Content().(*SignRound1Message2)
+		if !bytes.Equal(r1msg2.GetMessageBinding(), round.temp.messageBinding) {
+			culprits = append(culprits, Pj)
+		}
+	}
+	return culprits
+}
+
 func (round *round2) NextRound() tss.Round {
 	round.started = false
 	return &round3{round}
@@ -10,6 +10,7 @@ import (
 	"errors"
 	"math/big"
 	"sync"
+	"time"
 
 	errorspkg "github.com/pkg/errors"
 
@@ -48,6 +49,7 @@ func (round *round3) Start() *tss.Error {
 				errChs <- round.WrapError(errorspkg.Wrapf(err, "UnmarshalProofBob failed"), Pj)
 				return
 			}
+			start := time.Now()
 			alphaIj, err := mta.AliceEnd(
 				round.Params().EC(),
 				round.key.PaillierPKs[i],
@@ -59,6 +61,7 @@ func (round *round3) Start() *tss.Error {
 				round.key.NTildej[i],
 				round.key.PaillierSK,
 				contextJ)
+			round.observeProof(tss.ProofMtABob, Pj, time.Since(start), err == nil)
 			alphas[j] = alphaIj
 			if err != nil {
 				errChs <- round.WrapError(err, Pj)
@@ -73,6 +76,7 @@ func (round *round3) Start() *tss.Error {
 				errChs <- round.WrapError(errorspkg.Wrapf(err, "UnmarshalProofBobWC failed"), Pj)
 				return
 			}
+			start := time.Now()
 			uIj, err := mta.AliceEndWC(
 				round.Params().EC(),
 				round.key.PaillierPKs[i],
@@ -85,6 +89,7 @@ func (round *round3) Start() *tss.Error {
 				round.key.H2j[i],
 				round.key.PaillierSK,
 				contextJ)
+			round.observeProof(tss.ProofMtABobWC, Pj, time.Since(start), err == nil)
 			us[j] = uIj
 			if err != nil {
 				errChs <- round.WrapError(err, Pj)
@@ -46,7 +46,7 @@ func (round *round4) Start() *tss.Error {
 	}
 	i := round.PartyID().Index
 	contextI := common.AppendUint64ToBytesSlice(round.temp.ssid, uint64(i))
-	piGamma, err := schnorr.NewZKProofWithSession(contextI, round.temp.gamma, round.temp.pointGamma)
+	piGamma, err := schnorr.NewZKProofWithSessionFrom(round.Params().Rand(), contextI, round.temp.gamma, round.temp.pointGamma)
 	if err != nil {
 		return round.WrapError(errors2.Wrapf(err, "NewZKProof(gamma, bigGamma)"))
 	}
@@ -8,6 +8,7 @@ package signing
 
 import (
 	"errors"
+	"time"
 
 	errors2 "github.com/pkg/errors"
 
@@ -47,7 +48,9 @@ func (round *round5) Start() *tss.Error {
 			return round.WrapError(errors.New("failed to unmarshal bigGamma proof"), Pj)
 		}
 		contextJ := common.AppendUint64ToBytesSlice(round.temp.ssid, uint64(j))
+		start := time.Now()
 		ok = proof.VerifyWithSession(contextJ, bigGammaJPoint)
+		round.observeProof(tss.ProofSchnorr, Pj, time.Since(start), ok)
 		if !ok {
 			return round.WrapError(errors.New("failed to prove bigGamma"), Pj)
 		}
@@ -68,8 +71,8 @@ func (round *round5) Start() *tss.Error {
 	round.temp.w = zero
 	round.temp.k = zero
 
-	li := common.GetRandomPositiveInt(N)  // li
-	roI := common.GetRandomPositiveInt(N) // pi
+	li := common.GetRandomPositiveIntFrom(round.Params().Rand(), N)  // li
+	roI := common.GetRandomPositiveIntFrom(round.Params().Rand(), N) // pi
 	rToSi := R.ScalarMult(si)
 	liPoint := crypto.ScalarBaseMult(round.Params().EC(), li)
 	bigAi := crypto.ScalarBaseMult(round.Params().EC(), roI)
@@ -78,7 +81,7 @@ func (round *round5) Start() *tss.Error {
 		return round.WrapError(errors2.Wrapf(err, "rToSi.Add(li)"))
 	}
 
-	cmt := commitments.NewHashCommitment(bigVi.X(), bigVi.Y(), bigAi.X(), bigAi.Y())
+	cmt := commitments.NewHashCommitmentFrom(round.Params().Rand(), bigVi.X(), bigVi.Y(), bigAi.X(), bigAi.Y())
 	r5msg := NewSignRound5Message(round.PartyID(), cmt.C)
 	round.temp.signRound5Messages[round.PartyID().Index] = r5msg
 	round.out <- r5msg
@@ -26,11 +26,11 @@ func (round *round6) Start() *tss.Error {
 
 	i := round.PartyID().Index
 	contextI := common.AppendUint64ToBytesSlice(round.temp.ssid, uint64(i))
-	piAi, err := schnorr.NewZKProofWithSession(contextI, round.temp.roi, round.temp.bigAi)
+	piAi, err := schnorr.NewZKProofWithSessionFrom(round.Params().Rand(), contextI, round.temp.roi, round.temp.bigAi)
 	if err != nil {
 		return round.WrapError(errors2.Wrapf(err, "NewZKProof(roi, bigAi)"))
 	}
-	piV, err := schnorr.NewZKVProofWithSession(contextI, round.temp.bigVi, round.temp.bigR, round.temp.si, round.temp.li)
+	piV, err := schnorr.NewZKVProofWithSessionFrom(round.Params().Rand(), contextI, round.temp.bigVi, round.temp.bigR, round.temp.si, round.temp.li)
 	if err != nil {
 		return round.WrapError(errors2.Wrapf(err, "NewZKVProof(bigVi, bigR, si, li)"))
 	}
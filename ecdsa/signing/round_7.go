@@ -9,6 +9,7 @@ package signing
 import (
 	"errors"
 	"math/big"
+	"time"
 
 	errors2 "github.com/pkg/errors"
 
@@ -52,12 +53,18 @@ func (round *round7) Start() *tss.Error {
 		}
 		bigAjs[j] = bigAj
 		contextJ := common.AppendUint64ToBytesSlice(round.temp.ssid, uint64(j))
+		start := time.Now()
 		pijA, err := r6msg.UnmarshalZKProof(round.Params().EC())
-		if err != nil || !pijA.VerifyWithSession(contextJ, bigAj) {
+		ok = err == nil && pijA.VerifyWithSession(contextJ, bigAj)
+		round.observeProof(tss.ProofSchnorr, Pj, time.Since(start), ok)
+		if !ok {
 			return round.WrapError(errors.New("schnorr verify for Aj failed"), Pj)
 		}
+		start = time.Now()
 		pijV, err := r6msg.UnmarshalZKVProof(round.Params().EC())
-		if err != nil || !pijV.VerifyWithSession(contextJ, bigVj, round.temp.bigR) {
+		ok = err == nil && pijV.VerifyWithSession(contextJ, bigVj, round.temp.bigR)
+		round.observeProof(tss.ProofSchnorr, Pj, time.Since(start), ok)
+		if !ok {
 			return round.WrapError(errors.New("vverify for Vj failed"), Pj)
 		}
 	}
@@ -83,7 +90,7 @@ func (round *round7) Start() *tss.Error {
 	TiX, TiY := round.Params().EC().ScalarMult(AX, AY, round.temp.li.Bytes())
 	round.temp.Ui = crypto.NewECPointNoCurveCheck(round.Params().EC(), UiX, UiY)
 	round.temp.Ti = crypto.NewECPointNoCurveCheck(round.Params().EC(), TiX, TiY)
-	cmt := commitments.NewHashCommitment(UiX, UiY, TiX, TiY)
+	cmt := commitments.NewHashCommitmentFrom(round.Params().Rand(), UiX, UiY, TiX, TiY)
 	r7msg := NewSignRound7Message(round.PartyID(), cmt.C)
 	round.temp.signRound7Messages[round.PartyID().Index] = r7msg
 	round.out <- r7msg
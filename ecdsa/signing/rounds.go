@@ -8,6 +8,7 @@ package signing
 
 import (
 	"math/big"
+	"time"
 
 	"github.com/bnb-chain/tss-lib/common"
 	"github.com/bnb-chain/tss-lib/crypto"
@@ -116,6 +117,11 @@ func (round *base) WrapError(err error, culprits ...*tss.PartyID) *tss.Error {
 	return tss.NewError(err, TaskName, round.number, round.PartyID(), culprits...)
 }
 
+// observeProof reports the verification of a proof by `prover` to the observer of the parameters
+func (round *base) observeProof(proof string, prover *tss.PartyID, took time.Duration, valid bool) {
+	tss.ObserveProof(round.Parameters, TaskName, round.number, proof, prover, took, valid)
+}
+
 // ----- //
 
 // `ok` tracks parties which have been verified by Update()
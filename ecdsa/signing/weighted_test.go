@@ -0,0 +1,129 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/crypto/vss"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+func TestPrepareForWeightedSigning(t *testing.T) {
+	ec := tss.S256()
+	q := ec.Params().N
+	const threshold = 3
+	ks := [][]*big.Int{
+		{big.NewInt(11), big.NewInt(12), big.NewInt(13)},
+		{big.NewInt(21)},
+		{big.NewInt(31), big.NewInt(32)},
+	}
+	var flat []*big.Int
+	for _, ksj := range ks {
+		flat = append(flat, ksj...)
+	}
+	secret := common.GetRandomPositiveInt(q)
+	_, shares, err := vss.Create(ec, threshold, secret, flat)
+	assert.NoError(t, err)
+
+	xis := make([][]*big.Int, len(ks))
+	bigXs := make([][]*crypto.ECPoint, len(ks))
+	k := 0
+	for j := range ks {
+		for range ks[j] {
+			xis[j] = append(xis[j], shares[k].Share)
+			bigXs[j] = append(bigXs[j], crypto.ScalarBaseMult(ec, shares[k].Share))
+			k++
+		}
+	}
+
+	// parties 0 and 2 hold 5 > t shares together
+	signers := []int{0, 2}
+	sum := big.NewInt(0)
+	for i, j := range signers {
+		wi, bigWs, err := PrepareForWeightedSigning(ec, i, threshold, xis[j], [][]*big.Int{ks[0], ks[2]}, [][]*crypto.ECPoint{bigXs[0], bigXs[2]})
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.True(t, bigWs[i].Equals(crypto.ScalarBaseMult(ec, wi)), "W_i must match w_i")
+		sum.Add(sum, wi)
+	}
+	assert.Equal(t, 0, new(big.Int).Mod(sum, q).Cmp(secret), "the additive shares must add up to the secret")
+
+	// parties 1 and 2 hold only 3 shares
+	_, _, err = PrepareForWeightedSigning(ec, 0, threshold, xis[1], [][]*big.Int{ks[1], ks[2]}, [][]*crypto.ECPoint{bigXs[1], bigXs[2]})
+	assert.Error(t, err)
+}
+
+func TestE2EWeightedKeygenAndSigning(t *testing.T) {
+	setUp("info")
+	const threshold = 2
+	weights := []int{3, 1, 1}
+	keys, pIDs := runTestKeygen(t, len(weights), func(p2pCtx *tss.PeerContext, pID *tss.PartyID) *tss.Parameters {
+		params := tss.NewWeightedParameters(tss.S256(), p2pCtx, pID, weights, threshold)
+		params.SetSessionNonce(big.NewInt(11))
+		return params
+	})
+	for j, key := range keys {
+		assert.Equal(t, weights, key.Weights())
+		assert.Len(t, key.WeightedXi, weights[j])
+		assert.Equal(t, 0, key.WeightedXi[0].Cmp(key.Xi))
+		for m, xim := range key.WeightedXi {
+			assert.True(t, crypto.ScalarBaseMult(tss.S256(), xim).Equals(keys[0].WeightedBigXj[j][m]))
+		}
+	}
+
+	// parties 0 and 2 hold 4 > t shares and sign together
+	signPIDs := tss.SortPartyIDs(tss.UnSortedPartyIDs{
+		tss.NewPartyID(pIDs[0].Id, pIDs[0].Moniker, pIDs[0].KeyInt()),
+		tss.NewPartyID(pIDs[2].Id, pIDs[2].Moniker, pIDs[2].KeyInt()),
+	})
+	signWeights := []int{weights[0], weights[2]}
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]tss.Party, 0, len(signPIDs))
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs)*len(signPIDs))
+	endCh := make(chan common.SignatureData, len(signPIDs))
+	msg := big.NewInt(42)
+	for i, id := range signPIDs {
+		params := tss.NewWeightedParameters(tss.S256(), p2pCtx, id, signWeights, threshold)
+		params.SetSessionNonce(big.NewInt(12))
+		P := NewLocalParty(msg, params, keys[[]int{0, 2}[i]], outCh, endCh, 32)
+		parties = append(parties, P)
+		go func(P tss.Party) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+	for ended := 0; ended < len(signPIDs); {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+		case msg := <-outCh:
+			routeTestMessage(parties, msg, errCh)
+		case <-endCh:
+			ended++
+		}
+	}
+	pk := ecdsa.PublicKey{
+		Curve: tss.EC(),
+		X:     keys[0].ECDSAPub.X(),
+		Y:     keys[0].ECDSAPub.Y(),
+	}
+	for _, P := range parties {
+		sig := &P.(*LocalParty).data
+		assert.True(t, ecdsa.Verify(&pk, msg.FillBytes(make([]byte, 32)), new(big.Int).SetBytes(sig.R), new(big.Int).SetBytes(sig.S)),
+			"ecdsa verify must pass")
+	}
+}
@@ -0,0 +1,346 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package transcript
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/big"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/ecdsa/signing"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+type (
+	// Options control what a Recorder captures.
+	Options struct {
+		// Debug records the party's secrets too: its randomness, its pre-parameters or key, and the keygen shares
+		// it sends and receives. A debug transcript must be handled like the key itself.
+		Debug bool
+	}
+
+	// Recorder captures the messages of one party. Wrap the party's out channel with Out() before constructing
+	// the party, then wrap the party with Party() and drive the wrapper instead of the party:
+	//
+	//	rec := transcript.NewKeygenRecorder(params, transcript.Options{}, preParams)
+	//	P := rec.Party(keygen.NewLocalParty(params, rec.Out(outCh), endCh, preParams))
+	//	defer rec.Close()
+	Recorder struct {
+		mtx  sync.Mutex
+		tr   Transcript
+		opts Options
+		self *tss.PartyID
+		rand *lockedBuffer
+
+		in     chan tss.Message
+		synced chan struct{}
+		quit   chan struct{}
+		once   sync.Once
+	}
+
+	recordingParty struct {
+		tss.Party
+		rec *Recorder
+	}
+
+	lockedBuffer struct {
+		mtx sync.Mutex
+		buf bytes.Buffer
+	}
+)
+
+// NewKeygenRecorder returns a recorder for the keygen party constructed with `params` and `optionalPreParams`. With
+// Options.Debug it replaces the source of randomness of `params` with one that records what the party draws, so it
+// must be called before the party is constructed.
+func NewKeygenRecorder(params *tss.Parameters, opts Options, optionalPreParams ...keygen.LocalPreParams) *Recorder {
+	rec := newRecorder(keygen.TaskName, params, opts)
+	if opts.Debug && 0 < len(optionalPreParams) {
+		preParams := optionalPreParams[0]
+		rec.tr.Secrets.PreParams = &preParams
+	}
+	return rec
+}
+
+// NewSigningRecorder returns a recorder for the signing party constructed with the same arguments. With
+// Options.Debug it replaces the source of randomness of `params` with one that records what the party draws, so it
+// must be called before the party is constructed.
+func NewSigningRecorder(
+	params *tss.Parameters,
+	opts Options,
+	msg *big.Int,
+	key keygen.LocalPartySaveData,
+	keyDerivationDelta *big.Int,
+	fullBytesLen int,
+) *Recorder {
+	rec := newRecorder(signing.TaskName, params, opts)
+	rec.tr.Parameters.Message = msg
+	rec.tr.Parameters.KeyDerivationDelta = keyDerivationDelta
+	rec.tr.Parameters.FullBytesLen = fullBytesLen
+	if opts.Debug {
+		rec.tr.Secrets.Key = &key
+	}
+	return rec
+}
+
+func newRecorder(task string, params *tss.Parameters, opts Options) *Recorder {
+	rec := &Recorder{
+		tr:     Transcript{Parameters: recordParameters(task, params)},
+		opts:   opts,
+		self:   params.PartyID(),
+		synced: make(chan struct{}),
+		quit:   make(chan struct{}),
+	}
+	if opts.Debug {
+		rec.rand = new(lockedBuffer)
+		rec.tr.Secrets = new(Secrets)
+		params.SetRand(io.TeeReader(params.Rand(), rec.rand))
+	}
+	return rec
+}
+
+// Out returns the channel to construct the party with; the recorder records what the party sends to it and
+// forwards it to `out`. It may be called once.
+func (rec *Recorder) Out(out chan<- tss.Message) chan<- tss.Message {
+	if rec.in != nil {
+		panic(errors.New("transcript: Recorder.Out called twice"))
+	}
+	rec.in = make(chan tss.Message)
+	go rec.forward(out)
+	return rec.in
+}
+
+// Party returns a party that records what `P` receives and the errors it returns.
+func (rec *Recorder) Party(P tss.Party) tss.Party {
+	return &recordingParty{Party: P, rec: rec}
+}
+
+// Transcript returns what was recorded so far.
+func (rec *Recorder) Transcript() *Transcript {
+	rec.mtx.Lock()
+	defer rec.mtx.Unlock()
+	tr := rec.tr
+	tr.Entries = append([]Entry(nil), rec.tr.Entries...)
+	if rec.tr.Secrets != nil {
+		secrets := *rec.tr.Secrets
+		secrets.Rand = rec.rand.bytes()
+		tr.Secrets = &secrets
+	}
+	return &tr
+}
+
+// Close stops forwarding the messages sent to Out().
+func (rec *Recorder) Close() {
+	rec.once.Do(func() { close(rec.quit) })
+}
+
+// forward records and passes on the party's messages; a nil message is the sync of sync().
+func (rec *Recorder) forward(out chan<- tss.Message) {
+	for {
+		select {
+		case msg := <-rec.in:
+			if msg == nil {
+				rec.synced <- struct{}{}
+				continue
+			}
+			rec.record(rec.outboundEntry(msg))
+			select {
+			case out <- msg:
+			case <-rec.quit:
+				return
+			}
+		case <-rec.quit:
+			return
+		}
+	}
+}
+
+// sync returns once the messages the party sent before it returned were forwarded, so that the caller sees them
+// on its own out channel in the same order as without the recorder.
+func (rec *Recorder) sync() {
+	if rec.in == nil {
+		return
+	}
+	select {
+	case rec.in <- nil:
+		<-rec.synced
+	case <-rec.quit:
+	}
+}
+
+// record appends `entry` and returns its index, for the error the party may return on it.
+func (rec *Recorder) record(entry Entry) int {
+	rec.mtx.Lock()
+	defer rec.mtx.Unlock()
+	rec.tr.Entries = append(rec.tr.Entries, entry)
+	return len(rec.tr.Entries) - 1
+}
+
+func (rec *Recorder) recordError(entry int, err *tss.Error) {
+	if err == nil {
+		return
+	}
+	rec.mtx.Lock()
+	defer rec.mtx.Unlock()
+	if entry < 0 {
+		rec.tr.StartError = err.Error()
+		return
+	}
+	rec.tr.Entries[entry].Error = err.Error()
+}
+
+func (rec *Recorder) outboundEntry(msg tss.Message) Entry {
+	entry := Entry{
+		Direction: Outbound,
+		From:      msg.GetFrom().Index,
+		To:        indexes(msg.GetTo()),
+		Broadcast: msg.IsBroadcast(),
+		Type:      msg.Type(),
+		Round:     roundOf(msg.Type()),
+	}
+	if parsed, ok := msg.(tss.ParsedMessage); ok {
+		entry.Wire, entry.Redacted = rec.wire(parsed)
+	}
+	return entry
+}
+
+func (rec *Recorder) inboundEntry(msg tss.ParsedMessage, from *tss.PartyID, isBroadcast bool) Entry {
+	entry := Entry{
+		Direction: Inbound,
+		From:      from.Index,
+		Broadcast: isBroadcast,
+		Type:      msg.Type(),
+		Round:     roundOf(msg.Type()),
+	}
+	if !isBroadcast {
+		entry.To = []int{rec.self.Index}
+	}
+	entry.Wire, entry.Redacted = rec.wire(msg)
+	return entry
+}
+
+// wire returns the wire bytes of `msg`, with the secrets cleared unless recording in debug mode.
+func (rec *Recorder) wire(msg tss.ParsedMessage) ([]byte, bool) {
+	content, redacted := msg.Content(), false
+	if !rec.opts.Debug {
+		content, redacted = redact(content, msg.GetFrom(), msg.IsBroadcast())
+	}
+	if !redacted {
+		if wire, _, err := msg.WireBytes(); err == nil {
+			return wire, false
+		}
+	}
+	wire, err := marshalContent(content)
+	if err != nil {
+		return nil, redacted
+	}
+	return wire, redacted
+}
+
+// redact returns `content` with the keygen shares cleared, also from the items of a batch keygen message, and
+// whether it cleared anything. An item that does not parse is cleared whole, as it cannot be told apart from a share.
+func redact(content tss.MessageContent, from *tss.PartyID, isBroadcast bool) (tss.MessageContent, bool) {
+	switch c := content.(type) {
+	case *keygen.KGRound2Message1:
+		clean := proto.Clone(c).(*keygen.KGRound2Message1)
+		clean.Share, clean.SubShares = nil, nil
+		return clean, true
+	case *keygen.KGBatchMessage:
+		items, redacted := make([][]byte, len(c.GetItems())), false
+		for k, bz := range c.GetItems() {
+			items[k] = bz
+			if len(bz) == 0 {
+				continue
+			}
+			item, err := tss.ParseWireMessage(bz, from, isBroadcast)
+			if err != nil {
+				items[k], redacted = nil, true
+				continue
+			}
+			clean, ok := redact(item.Content(), from, isBroadcast)
+			if !ok {
+				continue
+			}
+			if items[k], err = marshalContent(clean); err != nil {
+				items[k] = nil
+			}
+			redacted = true
+		}
+		if !redacted {
+			return content, false
+		}
+		return &keygen.KGBatchMessage{Items: items}, true
+	default:
+		return content, false
+	}
+}
+
+// marshalContent returns the wire bytes of a message with `content`, as tss.NewMessageWrapper encodes it.
+func marshalContent(content tss.MessageContent) ([]byte, error) {
+	any, err := anypb.New(content)
+	if err != nil {
+		return nil, err
+	}
+	return proto.MarshalOptions{Deterministic: true}.Marshal(any)
+}
+
+// ----- //
+
+func (p *recordingParty) Start() *tss.Error {
+	err := p.Party.Start()
+	p.rec.sync()
+	p.rec.recordError(-1, err)
+	return err
+}
+
+func (p *recordingParty) Update(msg tss.ParsedMessage) (bool, *tss.Error) {
+	entry := -1
+	if msg != nil && msg.GetFrom() != nil {
+		entry = p.rec.record(p.rec.inboundEntry(msg, msg.GetFrom(), msg.IsBroadcast()))
+	}
+	ok, err := p.Party.Update(msg)
+	p.rec.sync()
+	if 0 <= entry {
+		p.rec.recordError(entry, err)
+	}
+	return ok, err
+}
+
+func (p *recordingParty) UpdateFromBytes(wireBytes []byte, from *tss.PartyID, isBroadcast bool) (bool, *tss.Error) {
+	entry := -1
+	if from != nil {
+		if msg, err := tss.ParseWireMessage(wireBytes, from, isBroadcast); err == nil {
+			entry = p.rec.record(p.rec.inboundEntry(msg, from, isBroadcast))
+		} else {
+			// kept as received, so that the replay fails to parse it too
+			entry = p.rec.record(Entry{Direction: Inbound, From: from.Index, Broadcast: isBroadcast, Wire: wireBytes})
+		}
+	}
+	ok, err := p.Party.UpdateFromBytes(wireBytes, from, isBroadcast)
+	p.rec.sync()
+	if 0 <= entry {
+		p.rec.recordError(entry, err)
+	}
+	return ok, err
+}
+
+func (buf *lockedBuffer) Write(p []byte) (int, error) {
+	buf.mtx.Lock()
+	defer buf.mtx.Unlock()
+	return buf.buf.Write(p)
+}
+
+func (buf *lockedBuffer) bytes() []byte {
+	buf.mtx.Lock()
+	defer buf.mtx.Unlock()
+	return append([]byte(nil), buf.buf.Bytes()...)
+}
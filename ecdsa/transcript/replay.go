@@ -0,0 +1,152 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package transcript
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/ecdsa/signing"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+// Result is the outcome of a replay.
+type Result struct {
+	// Err is the first error the party returned, or nil
+	Err *tss.Error
+	// Entry is the index in Transcript.Entries of the inbound message Err was returned on, or -1 if it was returned
+	// by Start or there was no error
+	Entry int
+	// Out holds the messages the party sent, in order
+	Out []tss.Message
+	// Finished is set when the party got through its last round
+	Finished bool
+	// Redacted is the index in Transcript.Entries of the redacted inbound entry the replay stopped before, since the
+	// party would reject the message without the secrets left out of it, or -1 if there was none
+	Redacted int
+}
+
+// ReplayKeygen feeds the inbound messages of a keygen transcript to a fresh keygen.LocalParty, until the party
+// returns an error or the messages run out. A transcript recorded without Options.Debug lacks the shares of round 2,
+// so the replay stops before the first of them and reports it in Result.Redacted. The pre-parameters recorded in
+// debug mode are used unless `optionalPreParams` are given; without either the party generates new ones.
+func ReplayKeygen(tr *Transcript, optionalPreParams ...keygen.LocalPreParams) (*Result, error) {
+	if tr.Parameters.Task != keygen.TaskName {
+		return nil, fmt.Errorf("transcript: cannot replay a %q transcript as keygen", tr.Parameters.Task)
+	}
+	params, err := replayParameters(tr)
+	if err != nil {
+		return nil, err
+	}
+	if len(optionalPreParams) == 0 && tr.Secrets != nil && tr.Secrets.PreParams != nil {
+		optionalPreParams = []keygen.LocalPreParams{*tr.Secrets.PreParams}
+	}
+	return replay(tr, params, func(out chan<- tss.Message) tss.Party {
+		end := make(chan keygen.LocalPartySaveData, 1)
+		return keygen.NewLocalParty(params, out, end, optionalPreParams...)
+	})
+}
+
+// ReplaySigning feeds the inbound messages of a signing transcript to a fresh signing.LocalParty, until the party
+// returns an error or the messages run out. The key recorded in debug mode is used unless `optionalKey` is given;
+// one of them is required.
+func ReplaySigning(tr *Transcript, optionalKey ...keygen.LocalPartySaveData) (*Result, error) {
+	if tr.Parameters.Task != signing.TaskName {
+		return nil, fmt.Errorf("transcript: cannot replay a %q transcript as signing", tr.Parameters.Task)
+	}
+	var key keygen.LocalPartySaveData
+	switch {
+	case 0 < len(optionalKey):
+		key = optionalKey[0]
+	case tr.Secrets != nil && tr.Secrets.Key != nil:
+		key = *tr.Secrets.Key
+	default:
+		return nil, fmt.Errorf("transcript: the key was not recorded and must be given to replay signing")
+	}
+	if tr.Parameters.Message == nil {
+		return nil, fmt.Errorf("transcript: the message to sign is missing")
+	}
+	params, err := replayParameters(tr)
+	if err != nil {
+		return nil, err
+	}
+	var fullBytesLen []int
+	if 0 < tr.Parameters.FullBytesLen {
+		fullBytesLen = []int{tr.Parameters.FullBytesLen}
+	}
+	return replay(tr, params, func(out chan<- tss.Message) tss.Party {
+		end := make(chan common.SignatureData, 1)
+		return signing.NewLocalPartyWithKDD(
+			tr.Parameters.Message, params, key, tr.Parameters.KeyDerivationDelta, out, end, fullBytesLen...)
+	})
+}
+
+// ----- //
+
+func replayParameters(tr *Transcript) (*tss.Parameters, error) {
+	params, err := tr.Parameters.TSSParameters()
+	if err != nil {
+		return nil, err
+	}
+	if tr.Secrets != nil && tr.Secrets.Rand != nil {
+		// the party draws the recorded bytes again; should it draw more, the replay has already diverged
+		params.SetRand(io.MultiReader(bytes.NewReader(tr.Secrets.Rand), rand.Reader))
+	}
+	return params, nil
+}
+
+func replay(tr *Transcript, params *tss.Parameters, newParty func(out chan<- tss.Message) tss.Party) (*Result, error) {
+	pIDs := params.Parties().IDs()
+	for i, entry := range tr.Entries {
+		if entry.Direction == Inbound && (entry.From < 0 || len(pIDs) <= entry.From) {
+			return nil, fmt.Errorf("transcript: entry %d is from unknown party %d", i, entry.From)
+		}
+	}
+	var (
+		wg   sync.WaitGroup
+		sent []tss.Message
+	)
+	out := make(chan tss.Message)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for msg := range out {
+			sent = append(sent, msg)
+		}
+	}()
+	P := newParty(out)
+
+	res := &Result{Entry: -1, Redacted: -1}
+	if err := P.Start(); err != nil {
+		res.Err = err
+	}
+	for i, entry := range tr.Entries {
+		if res.Err != nil {
+			break
+		}
+		if entry.Direction != Inbound {
+			continue
+		}
+		if entry.Redacted {
+			res.Redacted = i
+			break
+		}
+		if _, err := P.UpdateFromBytes(entry.Wire, pIDs[entry.From], entry.Broadcast); err != nil {
+			res.Err, res.Entry = err, i
+		}
+	}
+	// the party only sends from within Start and Update, which have returned
+	close(out)
+	wg.Wait()
+	res.Out, res.Finished = sent, !P.Running()
+	return res, nil
+}
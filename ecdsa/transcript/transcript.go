@@ -0,0 +1,219 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package transcript records the messages a keygen or signing party sends and receives, together with its
+// parameters, so that a failed ceremony can be replayed offline against a fresh party to reproduce the error.
+//
+// Recordings leave out the secrets unless Options.Debug is set: the party's randomness, its pre-parameters or key,
+// and the keygen shares sent privately between parties. Without them a replay reproduces the errors caused by the
+// peers' messages that do not depend on the party's own secrets, e.g. a bad proof or a failed de-commitment; with
+// them it reproduces the run exactly, and the file must then be protected like a key.
+package transcript
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"regexp"
+	"strconv"
+
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+const (
+	Inbound  Direction = "in"
+	Outbound Direction = "out"
+)
+
+type (
+	// Direction tells whether the recorded party received or sent a message.
+	Direction string
+
+	// Entry is a message received or sent by the recorded party.
+	Entry struct {
+		Direction Direction
+		// From is the index of the sender and To those of the recipients, or nil for a broadcast to all
+		From      int
+		To        []int `json:",omitempty"`
+		Broadcast bool
+		// Round is the round number in the message type, e.g. 2 for KGRound2Message1
+		Round int
+		Type  string
+		// Wire holds the bytes of the MessageWrapper's Message, as returned by WireBytes
+		Wire []byte
+		// Redacted is set when secrets were cleared from the message before it was recorded
+		Redacted bool `json:",omitempty"`
+		// Error is the error the party returned on this inbound message, if any
+		Error string `json:",omitempty"`
+	}
+
+	// PartyID is a recorded tss.PartyID.
+	PartyID struct {
+		ID, Moniker string
+		Key         []byte
+	}
+
+	// Parameters are the public inputs of the recorded party.
+	Parameters struct {
+		// Task is keygen.TaskName or signing.TaskName
+		Task  string
+		Curve tss.CurveName
+		// Parties are the sorted party IDs of the ceremony, and Party the index of the recorded party among them
+		Parties             []PartyID
+		Party               int
+		Threshold           int
+		Weights             []int `json:",omitempty"`
+		Ranks               []int `json:",omitempty"`
+		SessionNonce        *big.Int
+		ChainCodeGeneration bool `json:",omitempty"`
+		// Concurrency matters to a replay of the pre-parameters generation, which only depends on the randomness
+		// with a concurrency of 1
+		Concurrency int
+
+		// the inputs of a signing party besides its key
+		Message            *big.Int `json:",omitempty"`
+		FullBytesLen       int      `json:",omitempty"`
+		KeyDerivationDelta *big.Int `json:",omitempty"`
+	}
+
+	// Secrets are recorded with Options.Debug only.
+	Secrets struct {
+		// Rand holds the bytes the party drew from its source of randomness, in order
+		Rand      []byte
+		PreParams *keygen.LocalPreParams     `json:",omitempty"`
+		Key       *keygen.LocalPartySaveData `json:",omitempty"`
+	}
+
+	// Transcript is the recording of one party in one ceremony.
+	Transcript struct {
+		Parameters Parameters
+		// StartError is the error the party returned from Start, if any
+		StartError string `json:",omitempty"`
+		Entries    []Entry
+		Secrets    *Secrets `json:",omitempty"`
+	}
+)
+
+var roundRegexp = regexp.MustCompile(`Round(\d+)`)
+
+// ReadFile loads a transcript written by WriteFile.
+func ReadFile(path string) (*Transcript, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	tr := new(Transcript)
+	if err = json.Unmarshal(bz, tr); err != nil {
+		return nil, fmt.Errorf("transcript: unable to parse %s: %v", path, err)
+	}
+	return tr, nil
+}
+
+// WriteFile saves the transcript as JSON, readable by the owner only.
+func (tr *Transcript) WriteFile(path string) error {
+	bz, err := json.MarshalIndent(tr, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, bz, 0600)
+}
+
+// Inbound returns the entries of the messages the party received, in the order it received them.
+func (tr *Transcript) Inbound() []Entry {
+	var in []Entry
+	for _, e := range tr.Entries {
+		if e.Direction == Inbound {
+			in = append(in, e)
+		}
+	}
+	return in
+}
+
+// PartyIDs returns the recorded party IDs, sorted as in the ceremony.
+func (params *Parameters) PartyIDs() tss.SortedPartyIDs {
+	unsorted := make(tss.UnSortedPartyIDs, len(params.Parties))
+	for i, id := range params.Parties {
+		unsorted[i] = tss.NewPartyID(id.ID, id.Moniker, new(big.Int).SetBytes(id.Key))
+	}
+	return tss.SortPartyIDs(unsorted)
+}
+
+// TSSParameters rebuilds the tss.Parameters of the recorded party.
+func (params *Parameters) TSSParameters() (*tss.Parameters, error) {
+	ec, ok := tss.GetCurveByName(params.Curve)
+	if !ok {
+		return nil, fmt.Errorf("transcript: unknown curve %q", params.Curve)
+	}
+	pIDs := params.PartyIDs()
+	if params.Party < 0 || len(pIDs) <= params.Party {
+		return nil, fmt.Errorf("transcript: party %d is not one of the %d parties", params.Party, len(pIDs))
+	}
+	if params.SessionNonce == nil || params.SessionNonce.Sign() <= 0 {
+		return nil, fmt.Errorf("transcript: the session nonce is missing")
+	}
+	ctx := tss.NewPeerContext(pIDs)
+	var tssParams *tss.Parameters
+	switch {
+	case params.Weights != nil:
+		tssParams = tss.NewWeightedParameters(ec, ctx, pIDs[params.Party], params.Weights, params.Threshold)
+	case params.Ranks != nil:
+		tssParams = tss.NewHierarchicalParameters(ec, ctx, pIDs[params.Party], params.Ranks, params.Threshold)
+	default:
+		tssParams = tss.NewParameters(ec, ctx, pIDs[params.Party], len(pIDs), params.Threshold)
+	}
+	tssParams.SetSessionNonce(params.SessionNonce)
+	tssParams.SetChainCodeGeneration(params.ChainCodeGeneration)
+	if 0 < params.Concurrency {
+		tssParams.SetConcurrency(params.Concurrency)
+	}
+	return tssParams, nil
+}
+
+// ----- //
+
+func recordParameters(task string, params *tss.Parameters) Parameters {
+	curve, _ := tss.GetCurveName(params.EC())
+	pIDs := params.Parties().IDs()
+	parties := make([]PartyID, len(pIDs))
+	for i, id := range pIDs {
+		parties[i] = PartyID{ID: id.Id, Moniker: id.Moniker, Key: id.Key}
+	}
+	return Parameters{
+		Task:                task,
+		Curve:               curve,
+		Parties:             parties,
+		Party:               params.PartyID().Index,
+		Threshold:           params.Threshold(),
+		Weights:             params.Weights(),
+		Ranks:               params.Ranks(),
+		SessionNonce:        params.SessionNonce(),
+		ChainCodeGeneration: params.ChainCodeGeneration(),
+		Concurrency:         params.Concurrency(),
+	}
+}
+
+func indexes(ids []*tss.PartyID) []int {
+	if ids == nil {
+		return nil
+	}
+	out := make([]int, len(ids))
+	for i, id := range ids {
+		out[i] = id.Index
+	}
+	return out
+}
+
+// roundOf returns the round number in a message type, e.g. 2 for KGRound2Message1, or 0 if it has none.
+func roundOf(msgType string) int {
+	match := roundRegexp.FindStringSubmatch(msgType)
+	if match == nil {
+		return 0
+	}
+	round, _ := strconv.Atoi(match[1])
+	return round
+}
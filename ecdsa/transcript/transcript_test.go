@@ -0,0 +1,286 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package transcript
+
+import (
+	"bytes"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ipfs/go-log"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/ecdsa/signing"
+	"github.com/bnb-chain/tss-lib/test"
+	"github.com/bnb-chain/tss-lib/test/adversary"
+	"github.com/bnb-chain/tss-lib/test/netsim"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+const (
+	testParticipants = 3
+	testThreshold    = 1
+	testAdversary    = 1
+	testVictim       = 2
+	testObserver     = 0
+)
+
+func setUp(level string) {
+	if err := log.SetLogLevel("tss-lib", level); err != nil {
+		panic(err)
+	}
+}
+
+func TestRecordAndReplayKeygen(t *testing.T) {
+	setUp("error")
+	fixtures, pIDs, err := keygen.LoadKeygenTestFixtures(testParticipants)
+	if err != nil {
+		t.Skip("test fixtures required (LocalPreParams) to run a quick keygen")
+	}
+	adv := adversary.New(testAdversary, adversary.CorruptShare(testVictim))
+	net := netsim.New(netsim.Config{Tamper: adv.Tamper})
+	p2pCtx := tss.NewPeerContext(pIDs)
+	endCh := make(chan keygen.LocalPartySaveData, len(pIDs))
+
+	// the victim is recorded with its secrets, another honest party without
+	recorders := make(map[int]*Recorder)
+	parties := make([]tss.Party, 0, len(pIDs))
+	for i := range pIDs {
+		params := tss.NewParameters(tss.S256(), p2pCtx, pIDs[i], len(pIDs), testThreshold)
+		params.SetSessionNonce(big.NewInt(400))
+		if i != testVictim && i != testObserver {
+			parties = append(parties, keygen.NewLocalParty(params, net.Out(), endCh, fixtures[i].LocalPreParams))
+			continue
+		}
+		rec := NewKeygenRecorder(params, Options{Debug: i == testVictim}, fixtures[i].LocalPreParams)
+		defer rec.Close()
+		recorders[i] = rec
+		parties = append(parties, rec.Party(keygen.NewLocalParty(params, rec.Out(net.Out()), endCh, fixtures[i].LocalPreParams)))
+	}
+	report := net.Run(parties)
+	assert.NoError(t, adv.Check(report), "the recorders must not change the run")
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "victim.json")
+	assert.NoError(t, recorders[testVictim].Transcript().WriteFile(path))
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	tr, err := ReadFile(path)
+	assert.NoError(t, err)
+	assert.NotNil(t, tr.Secrets)
+
+	failed := -1
+	for i, e := range tr.Entries {
+		if e.Error != "" {
+			failed = i
+			break
+		}
+	}
+	if !assert.True(t, 0 <= failed, "the victim should have failed") {
+		return
+	}
+
+	res, err := ReplayKeygen(tr)
+	assert.NoError(t, err)
+	assert.Equal(t, failed, res.Entry)
+	if assert.NotNil(t, res.Err) {
+		assert.Equal(t, tr.Entries[failed].Error, res.Err.Error())
+		if assert.Equal(t, 1, len(res.Err.Culprits())) {
+			assert.Equal(t, testAdversary, res.Err.Culprits()[0].Index)
+		}
+	}
+	assert.False(t, res.Finished)
+	// with the recorded randomness the party sends exactly what it sent during the run
+	var sent [][]byte
+	for _, e := range tr.Entries[:failed] {
+		if e.Direction == Outbound {
+			sent = append(sent, e.Wire)
+		}
+	}
+	if assert.True(t, len(sent) <= len(res.Out)) {
+		for k, wire := range sent {
+			replayed, _, err := res.Out[k].WireBytes()
+			assert.NoError(t, err)
+			assert.Equal(t, wire, replayed, "message %d of the replay differs", k)
+		}
+	}
+
+	// without the debug flag the secrets are left out
+	observed := recorders[testObserver].Transcript()
+	assert.Nil(t, observed.Secrets)
+	redacted := 0
+	for _, e := range observed.Entries {
+		if e.Type != "binance.tsslib.ecdsa.keygen.KGRound2Message1" {
+			assert.False(t, e.Redacted)
+			continue
+		}
+		from := observed.Parameters.PartyIDs()[e.From]
+		msg, err := tss.ParseWireMessage(e.Wire, from, e.Broadcast)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		assert.True(t, e.Redacted)
+		assert.Empty(t, msg.Content().(*keygen.KGRound2Message1).Share)
+		redacted++
+	}
+	// the observer sends a share to each of the two others and receives one from each
+	assert.Equal(t, 2*(testParticipants-1), redacted)
+	// a replay of it stops before the first share received, which the party would reject as malformed
+	firstRedacted := -1
+	for i, e := range observed.Entries {
+		if e.Direction == Inbound && e.Redacted {
+			firstRedacted = i
+			break
+		}
+	}
+	res, err = ReplayKeygen(observed, fixtures[testObserver].LocalPreParams)
+	assert.NoError(t, err)
+	assert.True(t, 0 < firstRedacted)
+	assert.Equal(t, firstRedacted, res.Redacted)
+	assert.Nil(t, res.Err)
+	assert.False(t, res.Finished)
+	_, err = ReplaySigning(observed)
+	assert.Error(t, err, "a keygen transcript is not a signing one")
+}
+
+func TestRecordBatchKeygenRedactsShares(t *testing.T) {
+	setUp("error")
+	fixtures, pIDs, err := keygen.LoadKeygenTestFixtures(testParticipants)
+	if err != nil {
+		t.Skip("test fixtures required (LocalPreParams) to run a quick keygen")
+	}
+	// every share sent, to look for in the transcript
+	var shares [][]byte
+	net := netsim.New(netsim.Config{Tamper: func(msg tss.Message) []tss.Message {
+		batch := msg.(tss.ParsedMessage).Content().(*keygen.KGBatchMessage)
+		for _, bz := range batch.GetItems() {
+			item, err := tss.ParseWireMessage(bz, msg.GetFrom(), msg.IsBroadcast())
+			if err != nil {
+				continue
+			}
+			if r2msg1, ok := item.Content().(*keygen.KGRound2Message1); ok {
+				shares = append(shares, r2msg1.GetShare())
+			}
+		}
+		return []tss.Message{msg}
+	}})
+	p2pCtx := tss.NewPeerContext(pIDs)
+	endCh := make(chan []keygen.LocalPartySaveData, len(pIDs))
+
+	var rec *Recorder
+	parties := make([]tss.Party, 0, len(pIDs))
+	for i := range pIDs {
+		params := tss.NewParameters(tss.S256(), p2pCtx, pIDs[i], len(pIDs), testThreshold)
+		params.SetSessionNonce(big.NewInt(402))
+		if i != testObserver {
+			parties = append(parties, keygen.NewBatchLocalParty(params, 2, net.Out(), endCh, fixtures[i].LocalPreParams))
+			continue
+		}
+		rec = NewKeygenRecorder(params, Options{}, fixtures[i].LocalPreParams)
+		defer rec.Close()
+		parties = append(parties, rec.Party(keygen.NewBatchLocalParty(params, 2, rec.Out(net.Out()), endCh, fixtures[i].LocalPreParams)))
+	}
+	report := net.Run(parties)
+	if !assert.True(t, report.AllFinished(), report.String()) {
+		return
+	}
+	// two keys, so two shares per batch message, and each party sends one to each of the two others
+	assert.Len(t, shares, 2*testParticipants*(testParticipants-1))
+
+	tr := rec.Transcript()
+	assert.Nil(t, tr.Secrets)
+	redacted := 0
+	for _, e := range tr.Entries {
+		for _, share := range shares {
+			assert.False(t, bytes.Contains(e.Wire, share), "a share is left in %s from %d", e.Type, e.From)
+		}
+		if !e.Redacted {
+			continue
+		}
+		from := tr.Parameters.PartyIDs()[e.From]
+		msg, err := tss.ParseWireMessage(e.Wire, from, e.Broadcast)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		for _, bz := range msg.Content().(*keygen.KGBatchMessage).GetItems() {
+			item, err := tss.ParseWireMessage(bz, from, e.Broadcast)
+			if assert.NoError(t, err) {
+				assert.Empty(t, item.Content().(*keygen.KGRound2Message1).GetShare())
+			}
+		}
+		redacted++
+	}
+	// the observer sends a batch of shares to each of the two others and receives one from each
+	assert.Equal(t, 2*(testParticipants-1), redacted)
+}
+
+func TestRecordAndReplaySigning(t *testing.T) {
+	setUp("error")
+	keys, signPIDs, err := keygen.LoadKeygenTestFixtures(test.TestThreshold + 1)
+	if err != nil {
+		t.Skip("test fixtures required to sign")
+	}
+	net := netsim.New(netsim.Config{})
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	endCh := make(chan common.SignatureData, len(signPIDs))
+	msg, fullBytesLen := big.NewInt(42), 32
+
+	var rec *Recorder
+	parties := make([]tss.Party, 0, len(signPIDs))
+	for i := range signPIDs {
+		params := tss.NewParameters(tss.S256(), p2pCtx, signPIDs[i], len(signPIDs), test.TestThreshold)
+		params.SetSessionNonce(big.NewInt(401))
+		if i != testObserver {
+			parties = append(parties, signing.NewLocalParty(msg, params, keys[i], net.Out(), endCh, fullBytesLen))
+			continue
+		}
+		rec = NewSigningRecorder(params, Options{Debug: true}, msg, keys[i], nil, fullBytesLen)
+		defer rec.Close()
+		parties = append(parties, rec.Party(signing.NewLocalParty(msg, params, keys[i], rec.Out(net.Out()), endCh, fullBytesLen)))
+	}
+	report := net.Run(parties)
+	if !assert.True(t, report.AllFinished(), report.String()) {
+		return
+	}
+	tr := rec.Transcript()
+	if !assert.NotNil(t, tr.Secrets) || !assert.NotNil(t, tr.Secrets.Key) {
+		return
+	}
+
+	// with the recorded key and randomness the party signs again, sending exactly what it sent during the run
+	res, err := ReplaySigning(tr)
+	assert.NoError(t, err)
+	assert.Nil(t, res.Err)
+	assert.Equal(t, -1, res.Entry)
+	assert.Equal(t, -1, res.Redacted)
+	assert.True(t, res.Finished)
+	var sent [][]byte
+	for _, e := range tr.Entries {
+		if e.Direction == Outbound {
+			sent = append(sent, e.Wire)
+		}
+	}
+	if assert.Equal(t, len(sent), len(res.Out)) {
+		for k, wire := range sent {
+			replayed, _, err := res.Out[k].WireBytes()
+			assert.NoError(t, err)
+			assert.Equal(t, wire, replayed, "message %d of the replay differs", k)
+		}
+	}
+
+	// the key is a secret, so it must be given to replay a transcript recorded without it
+	tr.Secrets = nil
+	_, err = ReplaySigning(tr)
+	assert.Error(t, err)
+	_, err = ReplayKeygen(tr)
+	assert.Error(t, err, "a signing transcript is not a keygen one")
+}
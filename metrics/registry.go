@@ -0,0 +1,268 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package metrics provides tss.Observer implementations: a Registry of Prometheus-style counters and histograms kept
+// in memory, and a Tracer that turns the events into spans of any tracing library, such as OpenTelemetry.
+// Neither needs a collector to run; set one on the parameters of every party with tss.Parameters.SetObserver.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+// Metric names of the Registry
+const (
+	RoundsStarted     = "tss_rounds_started_total"
+	RoundDuration     = "tss_round_duration_seconds"
+	MessagesReceived  = "tss_messages_received_total"
+	MessageBytes      = "tss_message_received_bytes_total"
+	ProofDuration     = "tss_proof_verification_duration_seconds"
+	PreParamsDuration = "tss_preparams_generation_duration_seconds"
+	CulpritsDetected  = "tss_culprits_total"
+)
+
+const (
+	counterKind        = "counter"
+	histogramKind      = "histogram"
+	textFormatMimeType = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+// DurationBuckets are the upper bounds, in seconds, of the duration histograms of NewRegistry by default. They span
+// a Schnorr proof on the fast end and a round waiting on a slow peer on the other.
+var DurationBuckets = []float64{.001, .005, .01, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60, 120, 300}
+
+type (
+	// Registry keeps counters and histograms of the events of the parties that share it, labelled by task, round,
+	// message type and proof but not by party, to keep the number of series bounded. WriteText and ServeHTTP expose
+	// them in the Prometheus text format; Counter and Histogram read them back.
+	Registry struct {
+		mtx      sync.Mutex
+		families map[string]*family
+	}
+
+	family struct {
+		name, help, kind string
+		labels           []string
+		buckets          []float64
+		series           map[string]*series
+	}
+
+	series struct {
+		labelValues []string
+		value       float64  // of a counter
+		counts      []uint64 // of a histogram, per bucket and not cumulative
+		count       uint64
+		sum         float64
+	}
+)
+
+var _ tss.Observer = (*Registry)(nil)
+
+// NewRegistry returns an empty Registry whose duration histograms have the upper bounds `buckets`, in seconds and in
+// increasing order, or DurationBuckets if none are given.
+func NewRegistry(buckets ...float64) *Registry {
+	if len(buckets) == 0 {
+		buckets = DurationBuckets
+	}
+	r := &Registry{families: make(map[string]*family)}
+	r.add(RoundsStarted, counterKind, "Rounds started.", nil, "task", "round")
+	r.add(RoundDuration, histogramKind, "Time from the start of a round until the party had every message of it, including the wait on peers.", buckets, "task", "round")
+	r.add(MessagesReceived, counterKind, "Messages received.", nil, "task", "round", "type")
+	r.add(MessageBytes, counterKind, "Size of the messages received.", nil, "task", "round", "type")
+	r.add(ProofDuration, histogramKind, "Time spent verifying the proofs of peers.", buckets, "task", "proof", "valid")
+	r.add(PreParamsDuration, histogramKind, "Time spent generating the safe primes and Paillier key of the party.", buckets, "task", "ok")
+	r.add(CulpritsDetected, counterKind, "Parties blamed for an error.", nil, "task", "round")
+	return r
+}
+
+func (r *Registry) add(name, kind, help string, buckets []float64, labels ...string) {
+	r.families[name] = &family{name: name, help: help, kind: kind, labels: labels, buckets: buckets, series: make(map[string]*series)}
+}
+
+func (r *Registry) RoundStarted(e tss.RoundEvent) {
+	r.inc(RoundsStarted, 1, e.Task, strconv.Itoa(e.Round))
+}
+
+func (r *Registry) RoundFinished(e tss.RoundEvent) {
+	r.observe(RoundDuration, e.Duration, e.Task, strconv.Itoa(e.Round))
+}
+
+func (r *Registry) MessageReceived(e tss.MessageEvent) {
+	r.inc(MessagesReceived, 1, e.Task, strconv.Itoa(e.Round), e.Type)
+	r.inc(MessageBytes, float64(e.Size), e.Task, strconv.Itoa(e.Round), e.Type)
+}
+
+func (r *Registry) ProofVerified(e tss.ProofEvent) {
+	r.observe(ProofDuration, e.Duration, e.Task, e.Proof, strconv.FormatBool(e.Valid))
+}
+
+func (r *Registry) PreParamsGenerated(e tss.PreParamsEvent) {
+	r.observe(PreParamsDuration, e.Duration, e.Task, strconv.FormatBool(e.Err == nil))
+}
+
+func (r *Registry) CulpritDetected(e tss.CulpritEvent) {
+	r.inc(CulpritsDetected, float64(len(e.Culprits)), e.Task, strconv.Itoa(e.Round))
+}
+
+// Counter returns the value of the counter `name` with the label values `labelValues`, in the order of its labels.
+func (r *Registry) Counter(name string, labelValues ...string) float64 {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if s := r.lookup(name, labelValues); s != nil {
+		return s.value
+	}
+	return 0
+}
+
+// Histogram returns the number and the sum of the observations of the histogram `name` with the label values
+// `labelValues`, in the order of its labels.
+func (r *Registry) Histogram(name string, labelValues ...string) (count uint64, sum float64) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if s := r.lookup(name, labelValues); s != nil {
+		return s.count, s.sum
+	}
+	return 0, 0
+}
+
+func (r *Registry) lookup(name string, labelValues []string) *series {
+	if f, ok := r.families[name]; ok {
+		return f.series[strings.Join(labelValues, "\xff")]
+	}
+	return nil
+}
+
+func (r *Registry) get(name string, labelValues []string) (*family, *series) {
+	f := r.families[name]
+	key := strings.Join(labelValues, "\xff")
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{labelValues: labelValues}
+		if f.kind == histogramKind {
+			s.counts = make([]uint64, len(f.buckets))
+		}
+		f.series[key] = s
+	}
+	return f, s
+}
+
+func (r *Registry) inc(name string, delta float64, labelValues ...string) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	_, s := r.get(name, labelValues)
+	s.value += delta
+}
+
+func (r *Registry) observe(name string, d time.Duration, labelValues ...string) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	f, s := r.get(name, labelValues)
+	seconds := d.Seconds()
+	if i := sort.SearchFloat64s(f.buckets, seconds); i < len(f.buckets) {
+		s.counts[i]++
+	}
+	s.count++
+	s.sum += seconds
+}
+
+// WriteText writes every series in the Prometheus text exposition format, sorted by name and labels.
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	names := make([]string, 0, len(r.families))
+	for name := range r.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := r.families[name].write(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ServeHTTP serves the metrics in the Prometheus text format, for a scraper to collect them from e.g. /metrics.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", textFormatMimeType)
+	if err := r.WriteText(w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (f *family) write(w io.Writer) error {
+	if len(f.series) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(f.series))
+	for key := range f.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.name, f.help, f.name, f.kind); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		s := f.series[key]
+		labels := f.labelPairs(s.labelValues)
+		if f.kind == counterKind {
+			if _, err := fmt.Fprintf(w, "%s%s %s\n", f.name, braces(labels), formatFloat(s.value)); err != nil {
+				return err
+			}
+			continue
+		}
+		cumulative := uint64(0)
+		for i, bound := range f.buckets {
+			cumulative += s.counts[i]
+			le := append(labels[:len(labels):len(labels)], `le="`+formatFloat(bound)+`"`)
+			if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n", f.name, braces(le), cumulative); err != nil {
+				return err
+			}
+		}
+		le := append(labels[:len(labels):len(labels)], `le="+Inf"`)
+		if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n%s_sum%s %s\n%s_count%s %d\n",
+			f.name, braces(le), s.count,
+			f.name, braces(labels), formatFloat(s.sum),
+			f.name, braces(labels), s.count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *family) labelPairs(values []string) []string {
+	pairs := make([]string, len(values))
+	for i, value := range values {
+		pairs[i] = f.labels[i] + `="` + labelValueEscaper.Replace(value) + `"`
+	}
+	return pairs
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func braces(pairs []string) string {
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatFloat(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,96 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package metrics
+
+import (
+	"bytes"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+func TestRegistryWriteText(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(3)
+	at := func(round int) tss.Event {
+		return tss.Event{Party: pIDs[0], Task: "ecdsa-keygen", Round: round, Time: time.Now()}
+	}
+	r := NewRegistry(0.1, 1)
+	r.PreParamsGenerated(tss.PreParamsEvent{Event: at(1), Duration: 90 * time.Second})
+	r.RoundStarted(tss.RoundEvent{Event: at(2)})
+	r.MessageReceived(tss.MessageEvent{Event: at(2), From: pIDs[1], Type: `a"b`, Size: 100})
+	r.MessageReceived(tss.MessageEvent{Event: at(2), From: pIDs[2], Type: `a"b`, Size: 50})
+	r.ProofVerified(tss.ProofEvent{Event: at(2), Proof: tss.ProofDLN, Prover: pIDs[1], Duration: 50 * time.Millisecond, Valid: true})
+	r.ProofVerified(tss.ProofEvent{Event: at(2), Proof: tss.ProofDLN, Prover: pIDs[2], Duration: 500 * time.Millisecond, Valid: true})
+	r.ProofVerified(tss.ProofEvent{Event: at(2), Proof: tss.ProofMod, Prover: pIDs[2], Duration: 2 * time.Second, Valid: false})
+	r.RoundFinished(tss.RoundEvent{Event: at(2), Duration: 3 * time.Second})
+	r.CulpritDetected(tss.CulpritEvent{Event: at(2), Culprits: pIDs[1:], Err: tss.NewError(errors.New("bad"), "ecdsa-keygen", 2, pIDs[0], pIDs[1:]...)})
+
+	var b bytes.Buffer
+	assert.NoError(t, r.WriteText(&b))
+	assert.Equal(t, `# HELP tss_culprits_total Parties blamed for an error.
+# TYPE tss_culprits_total counter
+tss_culprits_total{task="ecdsa-keygen",round="2"} 2
+# HELP tss_message_received_bytes_total Size of the messages received.
+# TYPE tss_message_received_bytes_total counter
+tss_message_received_bytes_total{task="ecdsa-keygen",round="2",type="a\"b"} 150
+# HELP tss_messages_received_total Messages received.
+# TYPE tss_messages_received_total counter
+tss_messages_received_total{task="ecdsa-keygen",round="2",type="a\"b"} 2
+# HELP tss_preparams_generation_duration_seconds Time spent generating the safe primes and Paillier key of the party.
+# TYPE tss_preparams_generation_duration_seconds histogram
+tss_preparams_generation_duration_seconds_bucket{task="ecdsa-keygen",ok="true",le="0.1"} 0
+tss_preparams_generation_duration_seconds_bucket{task="ecdsa-keygen",ok="true",le="1"} 0
+tss_preparams_generation_duration_seconds_bucket{task="ecdsa-keygen",ok="true",le="+Inf"} 1
+tss_preparams_generation_duration_seconds_sum{task="ecdsa-keygen",ok="true"} 90
+tss_preparams_generation_duration_seconds_count{task="ecdsa-keygen",ok="true"} 1
+# HELP tss_proof_verification_duration_seconds Time spent verifying the proofs of peers.
+# TYPE tss_proof_verification_duration_seconds histogram
+tss_proof_verification_duration_seconds_bucket{task="ecdsa-keygen",proof="dln",valid="true",le="0.1"} 1
+tss_proof_verification_duration_seconds_bucket{task="ecdsa-keygen",proof="dln",valid="true",le="1"} 2
+tss_proof_verification_duration_seconds_bucket{task="ecdsa-keygen",proof="dln",valid="true",le="+Inf"} 2
+tss_proof_verification_duration_seconds_sum{task="ecdsa-keygen",proof="dln",valid="true"} 0.55
+tss_proof_verification_duration_seconds_count{task="ecdsa-keygen",proof="dln",valid="true"} 2
+tss_proof_verification_duration_seconds_bucket{task="ecdsa-keygen",proof="mod",valid="false",le="0.1"} 0
+tss_proof_verification_duration_seconds_bucket{task="ecdsa-keygen",proof="mod",valid="false",le="1"} 0
+tss_proof_verification_duration_seconds_bucket{task="ecdsa-keygen",proof="mod",valid="false",le="+Inf"} 1
+tss_proof_verification_duration_seconds_sum{task="ecdsa-keygen",proof="mod",valid="false"} 2
+tss_proof_verification_duration_seconds_count{task="ecdsa-keygen",proof="mod",valid="false"} 1
+# HELP tss_round_duration_seconds Time from the start of a round until the party had every message of it, including the wait on peers.
+# TYPE tss_round_duration_seconds histogram
+tss_round_duration_seconds_bucket{task="ecdsa-keygen",round="2",le="0.1"} 0
+tss_round_duration_seconds_bucket{task="ecdsa-keygen",round="2",le="1"} 0
+tss_round_duration_seconds_bucket{task="ecdsa-keygen",round="2",le="+Inf"} 1
+tss_round_duration_seconds_sum{task="ecdsa-keygen",round="2"} 3
+tss_round_duration_seconds_count{task="ecdsa-keygen",round="2"} 1
+# HELP tss_rounds_started_total Rounds started.
+# TYPE tss_rounds_started_total counter
+tss_rounds_started_total{task="ecdsa-keygen",round="2"} 1
+`, b.String())
+
+	assert.Equal(t, float64(2), r.Counter(MessagesReceived, "ecdsa-keygen", "2", `a"b`))
+	assert.Equal(t, float64(0), r.Counter(MessagesReceived, "ecdsa-keygen", "3", `a"b`))
+	count, sum := r.Histogram(ProofDuration, "ecdsa-keygen", tss.ProofDLN, "true")
+	assert.Equal(t, uint64(2), count)
+	assert.InDelta(t, 0.55, sum, 1e-9)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, textFormatMimeType, rec.Header().Get("Content-Type"))
+	assert.Equal(t, b.String(), rec.Body.String())
+}
+
+func TestRegistryEmpty(t *testing.T) {
+	var b strings.Builder
+	assert.NoError(t, NewRegistry().WriteText(&b))
+	assert.Empty(t, b.String(), "families without series are left out")
+}
@@ -0,0 +1,217 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+// Attribute keys of the spans and span events of the Tracer
+const (
+	AttrParty     = "tss.party"
+	AttrTask      = "tss.task"
+	AttrRound     = "tss.round"
+	AttrFrom      = "tss.from"
+	AttrType      = "tss.type"
+	AttrSize      = "tss.size"
+	AttrBroadcast = "tss.broadcast"
+	AttrProof     = "tss.proof"
+	AttrProver    = "tss.prover"
+	AttrValid     = "tss.valid"
+	AttrCulprits  = "tss.culprits"
+)
+
+type (
+	// Attribute is a key and a value of a span or span event.
+	Attribute struct {
+		Key   string
+		Value interface{}
+	}
+
+	// Span is the part of a tracing library's span that the Tracer uses.
+	Span interface {
+		AddEvent(name string, at time.Time, attrs ...Attribute)
+		SetError(err error)
+		End(at time.Time)
+	}
+
+	// SpanStarter starts the spans of a tracing library. The Tracer calls it with a nil `parent` for a root span.
+	//
+	// With OpenTelemetry, a Span may carry the context of its span for its children:
+	//
+	//	type otelStarter struct{ tracer trace.Tracer }
+	//	type otelSpan struct {
+	//		ctx context.Context
+	//		trace.Span
+	//	}
+	//
+	//	func (s otelStarter) Start(parent metrics.Span, name string, at time.Time, attrs ...metrics.Attribute) metrics.Span {
+	//		ctx := context.Background()
+	//		if parent != nil {
+	//			ctx = parent.(otelSpan).ctx
+	//		}
+	//		ctx, span := s.tracer.Start(ctx, name, trace.WithTimestamp(at), trace.WithAttributes(otelAttributes(attrs)...))
+	//		return otelSpan{ctx, span}
+	//	}
+	//
+	//	func (s otelSpan) AddEvent(name string, at time.Time, attrs ...metrics.Attribute) {
+	//		s.Span.AddEvent(name, trace.WithTimestamp(at), trace.WithAttributes(otelAttributes(attrs)...))
+	//	}
+	//	func (s otelSpan) SetError(err error) { s.Span.RecordError(err); s.Span.SetStatus(codes.Error, err.Error()) }
+	//	func (s otelSpan) End(at time.Time)   { s.Span.End(trace.WithTimestamp(at)) }
+	//
+	// where otelAttributes converts each Attribute with attribute.String, attribute.StringSlice, attribute.Int or
+	// attribute.Bool.
+	SpanStarter interface {
+		Start(parent Span, name string, at time.Time, attrs ...Attribute) Span
+	}
+
+	// Tracer turns the events of the parties into spans: a root span named after the task for the run of each party,
+	// with a child span per round. A verified proof, or the generation of the party's pre-params, is a child span of
+	// its round, ending when the event is observed and lasting the time of the work; a received message and the
+	// detection of culprits are events of the round span. An error that blames culprits also marks the round and the
+	// run as failed and ends them, as it ends the party's run.
+	Tracer struct {
+		starter SpanStarter
+		mtx     sync.Mutex
+		runs    map[runKey]*run
+	}
+
+	runKey struct {
+		party *tss.PartyID
+		task  string
+	}
+
+	run struct {
+		root, round Span
+	}
+)
+
+var _ tss.Observer = (*Tracer)(nil)
+
+// NewTracer returns a Tracer that starts its spans with `starter`.
+func NewTracer(starter SpanStarter) *Tracer {
+	return &Tracer{starter: starter, runs: make(map[runKey]*run)}
+}
+
+func (t *Tracer) RoundStarted(e tss.RoundEvent) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	key := runKey{e.Party, e.Task}
+	r, ok := t.runs[key]
+	if !ok {
+		r = &run{root: t.starter.Start(nil, e.Task, e.Time, Attribute{AttrParty, partyString(e.Party)}, Attribute{AttrTask, e.Task})}
+		t.runs[key] = r
+	}
+	if r.round != nil {
+		r.round.End(e.Time)
+	}
+	r.round = t.starter.Start(r.root, fmt.Sprintf("%s round %d", e.Task, e.Round), e.Time, Attribute{AttrRound, e.Round})
+}
+
+func (t *Tracer) RoundFinished(e tss.RoundEvent) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	key := runKey{e.Party, e.Task}
+	r, ok := t.runs[key]
+	if !ok {
+		return
+	}
+	if r.round != nil {
+		r.round.End(e.Time)
+		r.round = nil
+	}
+	if e.Last {
+		r.root.End(e.Time)
+		delete(t.runs, key)
+	}
+}
+
+func (t *Tracer) MessageReceived(e tss.MessageEvent) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if r, ok := t.runs[runKey{e.Party, e.Task}]; ok && r.round != nil {
+		r.round.AddEvent("message received", e.Time,
+			Attribute{AttrFrom, partyString(e.From)},
+			Attribute{AttrType, e.Type},
+			Attribute{AttrSize, e.Size},
+			Attribute{AttrBroadcast, e.Broadcast})
+	}
+}
+
+func (t *Tracer) ProofVerified(e tss.ProofEvent) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	r, ok := t.runs[runKey{e.Party, e.Task}]
+	if !ok || r.round == nil {
+		return
+	}
+	span := t.starter.Start(r.round, "verify "+e.Proof, e.Time.Add(-e.Duration),
+		Attribute{AttrProof, e.Proof},
+		Attribute{AttrProver, partyString(e.Prover)},
+		Attribute{AttrValid, e.Valid})
+	if !e.Valid {
+		span.SetError(fmt.Errorf("the %s proof of %s is invalid", e.Proof, partyString(e.Prover)))
+	}
+	span.End(e.Time)
+}
+
+func (t *Tracer) PreParamsGenerated(e tss.PreParamsEvent) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	r, ok := t.runs[runKey{e.Party, e.Task}]
+	if !ok || r.round == nil {
+		return
+	}
+	span := t.starter.Start(r.round, "generate pre-params", e.Time.Add(-e.Duration))
+	if e.Err != nil {
+		span.SetError(e.Err)
+	}
+	span.End(e.Time)
+}
+
+func (t *Tracer) CulpritDetected(e tss.CulpritEvent) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	key := runKey{e.Party, e.Task}
+	r, ok := t.runs[key]
+	if !ok {
+		return
+	}
+	var err error = errors.New("culprit detected")
+	if e.Err != nil {
+		err = e.Err
+	}
+	culprits := make([]string, len(e.Culprits))
+	for i, culprit := range e.Culprits {
+		culprits[i] = partyString(culprit)
+	}
+	span := r.root
+	if r.round != nil {
+		span = r.round
+	}
+	span.AddEvent("culprit detected", e.Time, Attribute{AttrCulprits, culprits}, Attribute{AttrRound, e.Round})
+	if r.round != nil {
+		r.round.SetError(err)
+		r.round.End(e.Time)
+	}
+	r.root.SetError(err)
+	r.root.End(e.Time)
+	delete(t.runs, key)
+}
+
+func partyString(p *tss.PartyID) string {
+	if p == nil {
+		return ""
+	}
+	return p.String()
+}
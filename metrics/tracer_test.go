@@ -0,0 +1,140 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package metrics
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+// recorder is an in-memory SpanStarter in place of a tracing library
+type recorder struct {
+	mtx   sync.Mutex
+	spans []*recordedSpan
+}
+
+type recordedSpan struct {
+	name       string
+	parent     *recordedSpan
+	start, end time.Time
+	attrs      map[string]interface{}
+	events     []string
+	err        error
+}
+
+func (r *recorder) Start(parent Span, name string, at time.Time, attrs ...Attribute) Span {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	span := &recordedSpan{name: name, start: at, attrs: make(map[string]interface{})}
+	if parent != nil {
+		span.parent = parent.(*recordedSpan)
+	}
+	for _, attr := range attrs {
+		span.attrs[attr.Key] = attr.Value
+	}
+	r.spans = append(r.spans, span)
+	return span
+}
+
+func (r *recorder) named(name string) *recordedSpan {
+	for _, span := range r.spans {
+		if span.name == name {
+			return span
+		}
+	}
+	return nil
+}
+
+func (s *recordedSpan) AddEvent(name string, _ time.Time, _ ...Attribute) {
+	s.events = append(s.events, name)
+}
+
+func (s *recordedSpan) SetError(err error) {
+	s.err = err
+}
+
+func (s *recordedSpan) End(at time.Time) {
+	s.end = at
+}
+
+func TestTracerSpans(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(3)
+	t0 := time.Now()
+	at := func(round int, after time.Duration) tss.Event {
+		return tss.Event{Party: pIDs[0], Task: "ecdsa-keygen", Round: round, Time: t0.Add(after)}
+	}
+	rec := new(recorder)
+	tracer := NewTracer(rec)
+	tracer.RoundStarted(tss.RoundEvent{Event: at(1, 0)})
+	tracer.PreParamsGenerated(tss.PreParamsEvent{Event: at(1, 900*time.Millisecond), Duration: 800 * time.Millisecond})
+	tracer.RoundFinished(tss.RoundEvent{Event: at(1, time.Second), Duration: time.Second})
+	tracer.RoundStarted(tss.RoundEvent{Event: at(2, time.Second)})
+	tracer.ProofVerified(tss.ProofEvent{Event: at(2, 3*time.Second), Proof: tss.ProofDLN, Prover: pIDs[1], Duration: time.Second, Valid: true})
+	tracer.ProofVerified(tss.ProofEvent{Event: at(2, 4*time.Second), Proof: tss.ProofMod, Prover: pIDs[2], Duration: time.Second, Valid: false})
+	tracer.MessageReceived(tss.MessageEvent{Event: at(2, 5*time.Second), From: pIDs[1], Type: "KGRound2Message1", Size: 10})
+	tracer.RoundFinished(tss.RoundEvent{Event: at(2, 6*time.Second), Duration: 5 * time.Second, Last: true})
+
+	if !assert.Len(t, rec.spans, 6) {
+		return
+	}
+	root, round1, round2 := rec.spans[0], rec.spans[1], rec.spans[3]
+	assert.Equal(t, "ecdsa-keygen", root.name)
+	assert.Nil(t, root.parent)
+	assert.Equal(t, pIDs[0].String(), root.attrs[AttrParty])
+	assert.Equal(t, t0.Add(6*time.Second), root.end)
+
+	assert.Equal(t, "ecdsa-keygen round 1", round1.name)
+	assert.Equal(t, root, round1.parent)
+	assert.Equal(t, t0.Add(time.Second), round1.end)
+	preParams := rec.named("generate pre-params")
+	assert.Equal(t, round1, preParams.parent)
+	assert.Equal(t, t0.Add(100*time.Millisecond), preParams.start, "the pre-params span lasts the time of the generation")
+	assert.Equal(t, t0.Add(900*time.Millisecond), preParams.end)
+	assert.NoError(t, preParams.err)
+	assert.Equal(t, root, round2.parent)
+	assert.Equal(t, 2, round2.attrs[AttrRound])
+	assert.Equal(t, []string{"message received"}, round2.events)
+
+	dln, mod := rec.named("verify dln"), rec.named("verify mod")
+	assert.Equal(t, round2, dln.parent)
+	assert.Equal(t, t0.Add(2*time.Second), dln.start, "a proof span lasts the time of the verification")
+	assert.Equal(t, t0.Add(3*time.Second), dln.end)
+	assert.Equal(t, pIDs[1].String(), dln.attrs[AttrProver])
+	assert.NoError(t, dln.err)
+	assert.Error(t, mod.err)
+	assert.Empty(t, tracer.runs, "the run is forgotten once it ends")
+}
+
+func TestTracerCulpritEndsRun(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(3)
+	event := tss.Event{Party: pIDs[0], Task: "signing", Round: 3, Time: time.Now()}
+	rec := new(recorder)
+	tracer := NewTracer(rec)
+	tracer.RoundStarted(tss.RoundEvent{Event: event})
+	// the events of other parties and tasks are kept apart
+	tracer.RoundStarted(tss.RoundEvent{Event: tss.Event{Party: pIDs[1], Task: "signing", Round: 3, Time: event.Time}})
+
+	err := tss.NewError(errors.New("bad proof"), "signing", 3, pIDs[0], pIDs[2])
+	tracer.CulpritDetected(tss.CulpritEvent{Event: event, Culprits: err.Culprits(), Err: err})
+	root, round := rec.spans[0], rec.spans[1]
+	assert.Equal(t, err, root.err)
+	assert.Equal(t, err, round.err)
+	assert.Equal(t, []string{"culprit detected"}, round.events)
+	assert.Equal(t, event.Time, root.end)
+	assert.Equal(t, event.Time, round.end)
+	assert.Len(t, tracer.runs, 1)
+
+	// events after the end of the run are dropped
+	tracer.ProofVerified(tss.ProofEvent{Event: event, Proof: tss.ProofSchnorr, Valid: true})
+	assert.Len(t, rec.spans, 4)
+}
@@ -0,0 +1,283 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package adversary turns a party into a byzantine one by rewriting the messages it sends, for conformance tests
+// checking that each kind of misbehaviour is attributed to the right party. The party itself runs the honest
+// protocol; the mutations are applied on the way out, through netsim.Config.Tamper.
+package adversary
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/ecdsa/signing"
+	"github.com/bnb-chain/tss-lib/test/netsim"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+type (
+	// Mutation rewrites a message sent by the adversary. It returns the messages to send instead: none to drop
+	// it, or msg itself to leave it alone.
+	Mutation func(msg tss.ParsedMessage) []tss.ParsedMessage
+
+	// Adversary is the party at index Party, whose outbound messages go through the mutations in order.
+	Adversary struct {
+		Party     int
+		mutations []Mutation
+	}
+
+	// Recorder is a Mutation that keeps a copy of the messages it lets through, to Replay them later.
+	Recorder struct {
+		msgs []tss.ParsedMessage
+	}
+)
+
+// New returns the adversary controlling the party at index `party`.
+func New(party int, mutations ...Mutation) *Adversary {
+	return &Adversary{Party: party, mutations: mutations}
+}
+
+// Tamper applies the mutations to the messages of the adversary and passes the others on; it is meant for
+// netsim.Config.Tamper.
+func (adv *Adversary) Tamper(msg tss.Message) []tss.Message {
+	parsed, ok := msg.(tss.ParsedMessage)
+	if !ok || msg.GetFrom().Index != adv.Party {
+		return []tss.Message{msg}
+	}
+	msgs := []tss.ParsedMessage{parsed}
+	for _, mutate := range adv.mutations {
+		var next []tss.ParsedMessage
+		for _, m := range msgs {
+			next = append(next, mutate(m)...)
+		}
+		msgs = next
+	}
+	out := make([]tss.Message, len(msgs))
+	for i, m := range msgs {
+		out[i] = m
+	}
+	return out
+}
+
+// Check returns an error unless the run caught the adversary: at least one honest party failed, every error of
+// an honest party names exactly the adversary, and no honest party finished.
+func (adv *Adversary) Check(report *netsim.Report) error {
+	caught := false
+	for _, pErr := range report.Errors {
+		if pErr.Party == adv.Party {
+			continue
+		}
+		// a round may report the same culprit once per failed check
+		culprits := pErr.Err.Culprits()
+		if len(culprits) == 0 {
+			return fmt.Errorf("party %d blamed nobody: %v", pErr.Party, pErr.Err)
+		}
+		for _, culprit := range culprits {
+			if culprit.Index != adv.Party {
+				return fmt.Errorf("party %d blamed %v instead of party %d: %v", pErr.Party, culprits, adv.Party, pErr.Err)
+			}
+		}
+		caught = true
+	}
+	if !caught {
+		return errors.New("no honest party failed")
+	}
+	for i, finished := range report.Finished {
+		if finished && i != adv.Party {
+			return fmt.Errorf("honest party %d finished the ceremony", i)
+		}
+	}
+	return nil
+}
+
+// ----- //
+
+// CorruptShare sends the party at index `victim` a wrong keygen share in round 2, and reveals the same wrong
+// share if it is accused in round 4, so the complaint is upheld instead of dismissed.
+func CorruptShare(victim int) Mutation {
+	return corruptShare(victim, false)
+}
+
+// CorruptShareThenJustify sends the party at index `victim` a wrong keygen share in round 2 but reveals the right
+// one if it is accused in round 4, as a dealer trying to frame the victim for a false complaint would. The
+// complaint is dismissed and the victim uses the revealed share, so nobody is blamed and keygen completes.
+func CorruptShareThenJustify(victim int) Mutation {
+	return corruptShare(victim, true)
+}
+
+func corruptShare(victim int, justify bool) Mutation {
+	var corrupted []byte
+	return func(msg tss.ParsedMessage) []tss.ParsedMessage {
+		switch content := msg.Content().(type) {
+		case *keygen.KGRound2Message1:
+			if to := msg.GetTo(); len(to) != 1 || to[0].Index != victim {
+				break
+			}
+			bad := proto.Clone(content).(*keygen.KGRound2Message1)
+			bad.Share = new(big.Int).Add(new(big.Int).SetBytes(content.Share), big.NewInt(1)).Bytes()
+			corrupted = bad.Share
+			return []tss.ParsedMessage{rebuild(msg, msg.GetTo(), bad)}
+		case *keygen.KGRound4Message:
+			if corrupted == nil || justify {
+				break
+			}
+			bad := proto.Clone(content).(*keygen.KGRound4Message)
+			for k, c := range content.UnmarshalComplainers() {
+				if c == victim {
+					bad.Shares[k] = corrupted
+				}
+			}
+			return []tss.ParsedMessage{rebuild(msg, msg.GetTo(), bad)}
+		}
+		return []tss.ParsedMessage{msg}
+	}
+}
+
+// SwapDLNProofs swaps the two DLN proofs of the keygen round 1 broadcast, so each proves the wrong statement.
+func SwapDLNProofs() Mutation {
+	return func(msg tss.ParsedMessage) []tss.ParsedMessage {
+		content, ok := msg.Content().(*keygen.KGRound1Message)
+		if !ok {
+			return []tss.ParsedMessage{msg}
+		}
+		bad := proto.Clone(content).(*keygen.KGRound1Message)
+		bad.Dlnproof_1, bad.Dlnproof_2 = bad.Dlnproof_2, bad.Dlnproof_1
+		return []tss.ParsedMessage{rebuild(msg, msg.GetTo(), bad)}
+	}
+}
+
+// Equivocate sends the parties at the `victims` indexes a different version of a broadcast than the rest of
+// `parties`. mutate changes the content in place and reports whether it applies to it; the broadcasts it
+// doesn't apply to are sent as they are.
+func Equivocate(parties tss.SortedPartyIDs, victims []int, mutate func(content tss.MessageContent) bool) Mutation {
+	isVictim := make(map[int]bool, len(victims))
+	for _, v := range victims {
+		isVictim[v] = true
+	}
+	return func(msg tss.ParsedMessage) []tss.ParsedMessage {
+		if !msg.IsBroadcast() {
+			return []tss.ParsedMessage{msg}
+		}
+		bad := proto.Clone(msg.Content()).(tss.MessageContent)
+		if !mutate(bad) {
+			return []tss.ParsedMessage{msg}
+		}
+		var honest, fooled []*tss.PartyID
+		for _, Pj := range parties {
+			switch {
+			case Pj.Index == msg.GetFrom().Index:
+			case isVictim[Pj.Index]:
+				fooled = append(fooled, Pj)
+			default:
+				honest = append(honest, Pj)
+			}
+		}
+		var msgs []tss.ParsedMessage
+		if 0 < len(honest) {
+			msgs = append(msgs, rebuild(msg, honest, msg.Content()))
+		}
+		if 0 < len(fooled) {
+			msgs = append(msgs, rebuild(msg, fooled, bad))
+		}
+		return msgs
+	}
+}
+
+// Record lets msg through and keeps a copy of it.
+func (rec *Recorder) Record(msg tss.ParsedMessage) []tss.ParsedMessage {
+	rec.msgs = append(rec.msgs, msg)
+	return []tss.ParsedMessage{msg}
+}
+
+// Messages returns the recorded messages.
+func (rec *Recorder) Messages() []tss.ParsedMessage {
+	return rec.msgs
+}
+
+// Replay sends the content of a recorded message, e.g. from another session, in place of each message of the
+// same type to the same recipient; the messages without a recorded counterpart are sent as they are.
+func Replay(recorded []tss.ParsedMessage) Mutation {
+	return func(msg tss.ParsedMessage) []tss.ParsedMessage {
+		for _, old := range recorded {
+			if old.Type() == msg.Type() && sameRecipients(old.GetTo(), msg.GetTo()) {
+				return []tss.ParsedMessage{rebuild(msg, msg.GetTo(), old.Content())}
+			}
+		}
+		return []tss.ParsedMessage{msg}
+	}
+}
+
+// ReplaySignRound2 is Replay for the signing round 2 messages only.
+func ReplaySignRound2(recorded []tss.ParsedMessage) Mutation {
+	var round2 []tss.ParsedMessage
+	for _, msg := range recorded {
+		if _, ok := msg.Content().(*signing.SignRound2Message); ok {
+			round2 = append(round2, msg)
+		}
+	}
+	return Replay(round2)
+}
+
+// BadProofBobWC sends a ProofBobWC in signing round 2 whose U is off by G, so that it is well-formed but fails
+// verification.
+func BadProofBobWC(ec elliptic.Curve) Mutation {
+	return func(msg tss.ParsedMessage) []tss.ParsedMessage {
+		content, ok := msg.Content().(*signing.SignRound2Message)
+		if !ok {
+			return []tss.ParsedMessage{msg}
+		}
+		proof, err := content.UnmarshalProofBobWC(ec)
+		if err != nil {
+			panic(fmt.Errorf("adversary: unable to parse our own ProofBobWC: %v", err))
+		}
+		if proof.U, err = proof.U.Add(crypto.ScalarBaseMult(ec, big.NewInt(1))); err != nil {
+			panic(fmt.Errorf("adversary: unable to shift U: %v", err))
+		}
+		bad := proto.Clone(content).(*signing.SignRound2Message)
+		bzs := proof.Bytes()
+		bad.ProofBobWc = bzs[:]
+		return []tss.ParsedMessage{rebuild(msg, msg.GetTo(), bad)}
+	}
+}
+
+// ----- //
+
+func rebuild(msg tss.ParsedMessage, to []*tss.PartyID, content tss.MessageContent) tss.ParsedMessage {
+	routing := tss.MessageRouting{
+		From:        msg.GetFrom(),
+		To:          to,
+		IsBroadcast: msg.IsBroadcast(),
+	}
+	return tss.NewMessage(routing, content, tss.NewMessageWrapper(routing, content))
+}
+
+func sameRecipients(a, b []*tss.PartyID) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	indexes := func(ids []*tss.PartyID) []int {
+		out := make([]int, len(ids))
+		for i, id := range ids {
+			out[i] = id.Index
+		}
+		sort.Ints(out)
+		return out
+	}
+	ia, ib := indexes(a), indexes(b)
+	for i := range ia {
+		if ia[i] != ib[i] {
+			return false
+		}
+	}
+	return true
+}
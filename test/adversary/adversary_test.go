@@ -0,0 +1,207 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package adversary
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ipfs/go-log"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/ecdsa/signing"
+	"github.com/bnb-chain/tss-lib/test/netsim"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+const (
+	testParticipants = 3
+	testThreshold    = 1
+	testAdversary    = 1
+	testVictim       = 2
+)
+
+var (
+	testKeys     []keygen.LocalPartySaveData
+	testKeysErr  error
+	testKeysOnce sync.Once
+)
+
+func setUp(level string) {
+	if err := log.SetLogLevel("tss-lib", level); err != nil {
+		panic(err)
+	}
+}
+
+func testConfig(adv *Adversary) netsim.Config {
+	cfg := netsim.Config{Seed: 1, Latency: 10 * time.Millisecond, Jitter: 10 * time.Millisecond}
+	if adv != nil {
+		cfg.Tamper = adv.Tamper
+	}
+	return cfg
+}
+
+func runKeygen(t *testing.T, adv *Adversary, nonce int64) (*netsim.Report, []keygen.LocalPartySaveData) {
+	fixtures, pIDs, err := keygen.LoadKeygenTestFixtures(testParticipants)
+	if err != nil {
+		t.Skip("test fixtures required (LocalPreParams) to run a quick keygen")
+	}
+	net := netsim.New(testConfig(adv))
+	p2pCtx := tss.NewPeerContext(pIDs)
+	endCh := make(chan keygen.LocalPartySaveData, len(pIDs))
+	parties := make([]tss.Party, 0, len(pIDs))
+	for i := range pIDs {
+		params := tss.NewParameters(tss.S256(), p2pCtx, pIDs[i], len(pIDs), testThreshold)
+		params.SetSessionNonce(big.NewInt(nonce))
+		parties = append(parties, keygen.NewLocalParty(params, net.Out(), endCh, fixtures[i].LocalPreParams))
+	}
+	report := net.Run(parties)
+	keys := make([]keygen.LocalPartySaveData, len(pIDs))
+	for len(endCh) > 0 {
+		save := <-endCh
+		index, err := save.OriginalIndex()
+		assert.NoError(t, err)
+		keys[index] = save
+	}
+	return report, keys
+}
+
+// keys returns the keys of an honest keygen, shared by the signing tests
+func keys(t *testing.T) []keygen.LocalPartySaveData {
+	testKeysOnce.Do(func() {
+		report, keys := runKeygen(t, nil, 300)
+		if !report.AllFinished() {
+			testKeysErr = report.Errors[0].Err
+			return
+		}
+		testKeys = keys
+	})
+	if testKeysErr != nil {
+		t.Fatalf("honest keygen failed: %v", testKeysErr)
+	}
+	return testKeys
+}
+
+func runSigning(t *testing.T, adv *Adversary, nonce int64) *netsim.Report {
+	keys := keys(t)
+	unsorted := make(tss.UnSortedPartyIDs, 0, len(keys))
+	for _, key := range keys {
+		unsorted = append(unsorted, tss.NewPartyID(key.ShareID.String(), "", key.ShareID))
+	}
+	pIDs := tss.SortPartyIDs(unsorted)
+	net := netsim.New(testConfig(adv))
+	p2pCtx := tss.NewPeerContext(pIDs)
+	endCh := make(chan common.SignatureData, len(pIDs))
+	parties := make([]tss.Party, 0, len(pIDs))
+	for i, id := range pIDs {
+		params := tss.NewParameters(tss.S256(), p2pCtx, id, len(pIDs), testThreshold)
+		params.SetSessionNonce(big.NewInt(nonce))
+		parties = append(parties, signing.NewLocalParty(big.NewInt(42), params, keys[i], net.Out(), endCh, 32))
+	}
+	return net.Run(parties)
+}
+
+func TestCorruptShare(t *testing.T) {
+	setUp("error")
+	adv := New(testAdversary, CorruptShare(testVictim))
+	report, _ := runKeygen(t, adv, 301)
+	assert.NoError(t, adv.Check(report))
+}
+
+func TestCorruptShareThenJustify(t *testing.T) {
+	setUp("error")
+	adv := New(testAdversary, CorruptShareThenJustify(testVictim))
+	report, keys := runKeygen(t, adv, 307)
+	// the victim's complaint is dismissed without blaming it, and it keeps a consistent share
+	if !assert.True(t, report.AllFinished(), "nobody should be blamed: %v", report.Errors) {
+		return
+	}
+	for _, key := range keys {
+		assert.True(t, key.ECDSAPub.Equals(keys[0].ECDSAPub), "everyone should have the same public key")
+	}
+	victimXi := crypto.ScalarBaseMult(tss.S256(), keys[testVictim].Xi)
+	assert.True(t, keys[0].BigXj[testVictim].Equals(victimXi), "the victim should use the revealed share")
+}
+
+func TestSwapDLNProofs(t *testing.T) {
+	setUp("error")
+	adv := New(testAdversary, SwapDLNProofs())
+	report, _ := runKeygen(t, adv, 302)
+	assert.NoError(t, adv.Check(report))
+}
+
+func TestEquivocateCommitment(t *testing.T) {
+	setUp("error")
+	_, pIDs, err := keygen.LoadKeygenTestFixtures(testParticipants)
+	if err != nil {
+		t.Skip("test fixtures required (LocalPreParams) to run a quick keygen")
+	}
+	// the victim gets a different round 1 commitment than the others, so the decommitment fails for it alone
+	adv := New(testAdversary, Equivocate(pIDs, []int{testVictim}, func(content tss.MessageContent) bool {
+		r1msg, ok := content.(*keygen.KGRound1Message)
+		if ok {
+			r1msg.Commitment = common.SHA512_256(r1msg.Commitment)
+		}
+		return ok
+	}))
+	report, _ := runKeygen(t, adv, 303)
+	assert.NoError(t, adv.Check(report))
+	if assert.Len(t, report.Errors, 1) {
+		assert.Equal(t, testVictim, report.Errors[0].Party)
+	}
+}
+
+func TestReplaySignRound2FromAnotherSession(t *testing.T) {
+	setUp("error")
+	rec := new(Recorder)
+	report := runSigning(t, New(testAdversary, rec.Record), 304)
+	if !assert.True(t, report.AllFinished(), "the recorded session must succeed: %v", report.Errors) {
+		return
+	}
+
+	adv := New(testAdversary, ReplaySignRound2(rec.Messages()))
+	report = runSigning(t, adv, 305)
+	assert.NoError(t, adv.Check(report))
+}
+
+func TestBadProofBobWC(t *testing.T) {
+	setUp("error")
+	adv := New(testAdversary, BadProofBobWC(tss.S256()))
+	report := runSigning(t, adv, 306)
+	assert.NoError(t, adv.Check(report))
+}
+
+func TestCheck(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(3)
+	adv := New(1)
+	blame := func(party int, culprits ...*tss.PartyID) netsim.PartyError {
+		return netsim.PartyError{Party: party, Err: tss.NewError(assert.AnError, "test", 1, pIDs[party], culprits...)}
+	}
+
+	assert.Error(t, adv.Check(&netsim.Report{Finished: []bool{true, true, true}}), "nobody failed")
+	assert.Error(t, adv.Check(&netsim.Report{
+		Finished: []bool{false, false, false},
+		Errors:   []netsim.PartyError{blame(0)},
+	}), "nobody was blamed")
+	assert.NoError(t, adv.Check(&netsim.Report{
+		Finished: []bool{false, true, false},
+		Errors:   []netsim.PartyError{blame(0, pIDs[1]), blame(2, pIDs[1], pIDs[1])},
+	}))
+	assert.Error(t, adv.Check(&netsim.Report{
+		Finished: []bool{false, false, false},
+		Errors:   []netsim.PartyError{blame(0, pIDs[1], pIDs[2])},
+	}), "an honest party was blamed too")
+	assert.Error(t, adv.Check(&netsim.Report{
+		Finished: []bool{false, false, true},
+		Errors:   []netsim.PartyError{blame(0, pIDs[1])},
+	}), "an honest party finished")
+}
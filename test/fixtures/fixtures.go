@@ -0,0 +1,163 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package fixtures generates keygen test fixtures for any number of parties, threshold and curve, in the format
+// read by keygen.LoadKeygenTestFixturesFromDir.
+package fixtures
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"os"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/test/netsim"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+const (
+	// ProfileStandard generates fresh pre-parameters for every party, with the 2048-bit Paillier and NTilde
+	// moduli that keygen requires. It takes minutes per party.
+	ProfileStandard Profile = "standard"
+	// ProfileTest reuses the pre-parameters of the bundled fixtures in test/_ecdsa_fixtures, so it is quick but
+	// limited to as many parties as there are bundled fixtures, and every fixture set made with it shares the same
+	// Paillier and NTilde moduli. It is only fit for tests.
+	ProfileTest Profile = "test"
+)
+
+type (
+	// Profile decides how the parties' pre-parameters are obtained.
+	Profile string
+
+	// Options describe the fixtures to generate.
+	Options struct {
+		Participants, Threshold int
+		// Curve defaults to secp256k1; any other curve must be registered with tss.RegisterCurve
+		Curve   tss.CurveName
+		Profile Profile
+		// Rand is the source of randomness, crypto/rand if nil. With a seeded reader and a Concurrency of 1 the
+		// same fixtures are generated again.
+		Rand        io.Reader
+		Concurrency int
+	}
+)
+
+// Generate runs a keygen with the given options and writes the save data of each party to `dir`, which is created
+// if needed. It returns the save data, ordered by party index.
+func Generate(ctx context.Context, dir string, opts Options) ([]keygen.LocalPartySaveData, error) {
+	if opts.Participants < 2 || opts.Threshold < 1 || opts.Participants <= opts.Threshold {
+		return nil, fmt.Errorf("fixtures: need 0 < threshold < participants, got threshold %d of %d",
+			opts.Threshold, opts.Participants)
+	}
+	if opts.Curve == "" {
+		opts.Curve = tss.Secp256k1
+	}
+	ec, ok := tss.GetCurveByName(opts.Curve)
+	if !ok {
+		return nil, fmt.Errorf("fixtures: curve %q is not registered", opts.Curve)
+	}
+	if opts.Rand == nil {
+		opts.Rand = rand.Reader
+	}
+	if opts.Concurrency < 1 {
+		opts.Concurrency = 1
+	}
+
+	preParams, err := preParameters(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	pIDs := partyIDs(opts.Rand, opts.Participants)
+	nonce := common.GetRandomPositiveIntFrom(opts.Rand, ec.Params().N)
+
+	net := netsim.New(netsim.Config{})
+	p2pCtx := tss.NewPeerContext(pIDs)
+	endCh := make(chan keygen.LocalPartySaveData, len(pIDs))
+	parties := make([]tss.Party, 0, len(pIDs))
+	for i := range pIDs {
+		params := tss.NewParameters(ec, p2pCtx, pIDs[i], len(pIDs), opts.Threshold)
+		params.SetSessionNonce(nonce)
+		params.SetConcurrency(opts.Concurrency)
+		params.SetRand(common.ForkRand(opts.Rand))
+		parties = append(parties, keygen.NewLocalParty(params, net.Out(), endCh, preParams[i]))
+	}
+	report := net.Run(parties)
+	if !report.AllFinished() {
+		if 0 < len(report.Errors) {
+			return nil, fmt.Errorf("fixtures: keygen failed: %v", report.Errors[0].Err)
+		}
+		return nil, errors.New("fixtures: keygen did not finish")
+	}
+
+	keys := make([]keygen.LocalPartySaveData, len(pIDs))
+	for len(endCh) > 0 {
+		key := <-endCh
+		index, err := key.OriginalIndex()
+		if err != nil {
+			return nil, err
+		}
+		keys[index] = key
+	}
+	if err = os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	for i := range keys {
+		bz, err := json.Marshal(&keys[i])
+		if err != nil {
+			return nil, err
+		}
+		if err = ioutil.WriteFile(keygen.TestFixtureFilePath(dir, i), bz, 0600); err != nil {
+			return nil, err
+		}
+	}
+	return keys, nil
+}
+
+// ----- //
+
+func preParameters(ctx context.Context, opts Options) ([]keygen.LocalPreParams, error) {
+	preParams := make([]keygen.LocalPreParams, opts.Participants)
+	switch opts.Profile {
+	case ProfileStandard, "":
+		for i := range preParams {
+			pre, err := keygen.GeneratePreParamsWithContextAndRandom(ctx, common.ForkRand(opts.Rand), opts.Concurrency)
+			if err != nil {
+				return nil, fmt.Errorf("fixtures: pre-parameters of party %d: %v", i, err)
+			}
+			preParams[i] = *pre
+		}
+	case ProfileTest:
+		bundled, _, err := keygen.LoadKeygenTestFixtures(opts.Participants)
+		if err != nil {
+			return nil, fmt.Errorf("fixtures: the %q profile needs %d bundled fixtures: %v",
+				ProfileTest, opts.Participants, err)
+		}
+		for i := range preParams {
+			preParams[i] = bundled[i].LocalPreParams
+		}
+	default:
+		return nil, fmt.Errorf("fixtures: unknown profile %q", opts.Profile)
+	}
+	return preParams, nil
+}
+
+// partyIDs returns the IDs GenerateTestPartyIDs would, with the key drawn from `rand`.
+func partyIDs(rand io.Reader, count int) tss.SortedPartyIDs {
+	key := common.MustGetRandomIntFrom(rand, 256)
+	ids := make(tss.UnSortedPartyIDs, 0, count)
+	for i := 0; i < count; i++ {
+		moniker := fmt.Sprintf("%d", i+1)
+		ids = append(ids, tss.NewPartyID(moniker, moniker, new(big.Int).Sub(key, big.NewInt(int64(count-i)))))
+	}
+	return tss.SortPartyIDs(ids)
+}
@@ -0,0 +1,75 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package fixtures
+
+import (
+	"context"
+	"crypto/elliptic"
+	"math/rand"
+	"testing"
+
+	"github.com/ipfs/go-log"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+func setUp(level string) {
+	if err := log.SetLogLevel("tss-lib", level); err != nil {
+		panic(err)
+	}
+}
+
+func TestGenerateRejectsBadOptions(t *testing.T) {
+	dir := t.TempDir()
+	for _, opts := range []Options{
+		{Participants: 3, Threshold: 3},
+		{Participants: 3, Threshold: 0},
+		{Participants: 3, Threshold: 1, Curve: "no-such-curve"},
+		{Participants: 3, Threshold: 1, Profile: "paranoid"},
+	} {
+		_, err := Generate(context.Background(), dir, opts)
+		assert.Error(t, err, "options %+v", opts)
+	}
+}
+
+func TestGenerateP256AndLoad(t *testing.T) {
+	setUp("error")
+	if _, _, err := keygen.LoadKeygenTestFixtures(3); err != nil {
+		t.Skip("test fixtures required (LocalPreParams) for the test profile")
+	}
+	tss.RegisterCurve("p256", elliptic.P256())
+	dir := t.TempDir()
+	opts := Options{
+		Participants: 3,
+		Threshold:    1,
+		Curve:        "p256",
+		Profile:      ProfileTest,
+		Rand:         rand.New(rand.NewSource(1)),
+	}
+	generated, err := Generate(context.Background(), dir, opts)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	keys, pIDs, err := keygen.LoadKeygenTestFixturesFromDir(dir, opts.Participants)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, opts.Participants, len(keys))
+	for i, key := range keys {
+		assert.Equal(t, generated[i].ShareID, key.ShareID)
+		assert.Equal(t, pIDs[i].KeyInt(), key.ShareID)
+		assert.True(t, tss.SameCurve(elliptic.P256(), key.ECDSAPub.Curve()))
+		assert.True(t, key.ECDSAPub.Equals(keys[0].ECDSAPub), "every party has the same public key")
+		// each party's share matches the public share the others hold for it
+		Xi := crypto.ScalarBaseMult(elliptic.P256(), key.Xi)
+		assert.True(t, Xi.Equals(keys[0].BigXj[i]))
+	}
+}
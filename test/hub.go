@@ -0,0 +1,88 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package test
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+type (
+	// Hub is an in-memory network for the transports of coordinated attempts; messages for parties that never
+	// opened a transport are dropped
+	Hub struct {
+		mtx     sync.Mutex
+		inboxes map[string]*hubTransport // by attempt number and party key
+	}
+
+	hubTransport struct {
+		hub     *Hub
+		attempt *tss.Attempt
+		self    *tss.PartyID
+		in      chan tss.ParsedMessage
+	}
+)
+
+func NewHub() *Hub {
+	return &Hub{inboxes: make(map[string]*hubTransport)}
+}
+
+func hubInbox(attempt int, key *big.Int) string {
+	return fmt.Sprintf("%d/%s", attempt, key)
+}
+
+// Transport returns the TransportFactory of the party `self`
+func (hub *Hub) Transport(self *tss.PartyID) tss.TransportFactory {
+	return func(attempt *tss.Attempt) (tss.Transport, error) {
+		tr := &hubTransport{
+			hub:     hub,
+			attempt: attempt,
+			self:    attempt.Parties.FindByKey(self.KeyInt()),
+			in:      make(chan tss.ParsedMessage, 16*len(attempt.Parties)),
+		}
+		hub.mtx.Lock()
+		defer hub.mtx.Unlock()
+		hub.inboxes[hubInbox(attempt.Number, self.KeyInt())] = tr
+		return tr, nil
+	}
+}
+
+func (tr *hubTransport) Send(msg tss.Message) error {
+	bz, _, err := msg.WireBytes()
+	if err != nil {
+		return err
+	}
+	dest := msg.GetTo()
+	if dest == nil {
+		dest = tr.attempt.Parties.Exclude(tr.self)
+	}
+	for _, to := range dest {
+		tr.hub.mtx.Lock()
+		inbox, ok := tr.hub.inboxes[hubInbox(tr.attempt.Number, to.KeyInt())]
+		tr.hub.mtx.Unlock()
+		if !ok {
+			continue
+		}
+		pMsg, err := tss.ParseWireMessage(bz, inbox.attempt.Parties.FindByKey(msg.GetFrom().KeyInt()), msg.IsBroadcast())
+		if err != nil {
+			return err
+		}
+		inbox.in <- pMsg
+	}
+	return nil
+}
+
+func (tr *hubTransport) Incoming() <-chan tss.ParsedMessage {
+	return tr.in
+}
+
+func (tr *hubTransport) Close() error {
+	return nil
+}
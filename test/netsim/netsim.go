@@ -0,0 +1,396 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package netsim runs a set of parties over a simulated network. Unlike wiring parties together with
+// test.SharedPartyUpdater, the network delivers messages one at a time from a single goroutine in the order of a
+// virtual clock, so latency, reordering, duplication, drops and partitions are reproducible from a seed.
+package netsim
+
+import (
+	"container/heap"
+	"fmt"
+	"math/rand"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+type (
+	// Link is the direction from one party to another, by party index.
+	Link struct {
+		From, To int
+	}
+
+	// Partition splits the network while a message would be delivered between From and Until (virtual time):
+	// messages between parties of different groups are held back until Until, or dropped if Until is 0. The
+	// parties not listed in Groups form one more group.
+	Partition struct {
+		From, Until time.Duration
+		Groups      [][]int
+	}
+
+	// Config describes the network. The zero value delivers every message once, instantly, in the order sent.
+	Config struct {
+		// Seed makes the faults and delays reproducible: two runs with the same seed deliver the same messages at
+		// the same virtual times in the same order
+		Seed int64
+		// Latency is the one-way delay of every link not listed in LinkLatency
+		Latency     time.Duration
+		LinkLatency map[Link]time.Duration
+		// Jitter adds a random delay in [0, Jitter) to every delivery, which reorders the messages sent close
+		// together, including those on the same link
+		Jitter time.Duration
+		// DropRate and DuplicateRate are the probabilities that a delivery is lost or happens twice
+		DropRate, DuplicateRate float64
+		Partitions              []Partition
+		// Tamper, if set, replaces every message sent with the messages it returns, e.g. those of an adversary; a
+		// broadcast it returns with recipients set only goes to them
+		Tamper func(msg tss.Message) []tss.Message
+	}
+
+	// Network delivers the messages of the parties that send to Out().
+	Network struct {
+		cfg     Config
+		rng     *rand.Rand
+		out     chan tss.Message
+		flushed chan []tss.Message
+		quit    chan struct{}
+		now     time.Duration
+		seq     uint64
+		queue   deliveries
+		rounds  map[int]*RoundStats
+	}
+
+	// RoundStats are the network statistics of the messages of one round, by the round number of their type.
+	RoundStats struct {
+		Round int
+		// Messages and Bytes count the deliveries of the round's messages and their wire bytes; a broadcast
+		// counts once per recipient
+		Messages, Bytes     int
+		Dropped, Duplicated int
+		// FirstSent and LastDelivered are virtual times
+		FirstSent, LastDelivered time.Duration
+		// Compute is the real time the parties spent handling the round's messages, including starting the
+		// rounds those messages completed; the Start of the parties counts towards round 1
+		Compute time.Duration
+
+		sent bool
+	}
+
+	// PartyError is an error returned by a party while it was started or handed a message.
+	PartyError struct {
+		Party int
+		Err   *tss.Error
+	}
+
+	// Report is the outcome of Run.
+	Report struct {
+		Rounds []*RoundStats
+		// Elapsed is the virtual time of the last delivery
+		Elapsed time.Duration
+		// Finished holds, by party index, whether the party got through its last round
+		Finished []bool
+		Errors   []PartyError
+	}
+
+	delivery struct {
+		at        time.Duration
+		seq       uint64
+		from      *tss.PartyID
+		to        int
+		broadcast bool
+		wire      []byte
+		round     int
+	}
+
+	deliveries []*delivery
+)
+
+var roundRegexp = regexp.MustCompile(`Round(\d+)`)
+
+// New returns a network; pass Out() to the constructors of the parties and then call Run. The parties' end
+// channels are not read during the run, so they must be buffered for one output per party.
+func New(cfg Config) *Network {
+	return &Network{
+		cfg:     cfg,
+		rng:     rand.New(rand.NewSource(cfg.Seed)),
+		out:     make(chan tss.Message),
+		flushed: make(chan []tss.Message),
+		quit:    make(chan struct{}),
+		rounds:  make(map[int]*RoundStats),
+	}
+}
+
+// Out is the channel the parties send their messages to.
+func (net *Network) Out() chan<- tss.Message {
+	return net.out
+}
+
+// Run starts the parties, whose index must match their position in `parties`, and delivers their messages until
+// there are none left: either every party finished or the rest are stuck waiting for messages that were dropped.
+func (net *Network) Run(parties []tss.Party) *Report {
+	for i, P := range parties {
+		if P.PartyID().Index != i {
+			panic(fmt.Errorf("netsim: party %s is at position %d", P.PartyID(), i))
+		}
+	}
+	go net.collect()
+	defer close(net.quit)
+
+	report := &Report{Finished: make([]bool, len(parties))}
+	for i, P := range parties {
+		began := time.Now()
+		if err := P.Start(); err != nil {
+			report.Errors = append(report.Errors, PartyError{Party: i, Err: err})
+		}
+		net.roundStats(1).Compute += time.Since(began)
+		net.send(net.flush(), len(parties))
+	}
+	for net.queue.Len() > 0 {
+		d := heap.Pop(&net.queue).(*delivery)
+		net.now = d.at
+		if d.to < 0 || len(parties) <= d.to {
+			continue
+		}
+		began := time.Now()
+		if _, err := parties[d.to].UpdateFromBytes(d.wire, d.from, d.broadcast); err != nil {
+			report.Errors = append(report.Errors, PartyError{Party: d.to, Err: err})
+		}
+		net.roundStats(d.round).Compute += time.Since(began)
+		net.send(net.flush(), len(parties))
+	}
+
+	report.Elapsed = net.now
+	for i, P := range parties {
+		report.Finished[i] = !P.Running()
+	}
+	for _, stats := range net.rounds {
+		report.Rounds = append(report.Rounds, stats)
+	}
+	sort.Slice(report.Rounds, func(a, b int) bool { return report.Rounds[a].Round < report.Rounds[b].Round })
+	return report
+}
+
+// collect buffers the messages sent by a party until the network flushes them; a nil message is the flush.
+func (net *Network) collect() {
+	var pending []tss.Message
+	for {
+		select {
+		case msg := <-net.out:
+			if msg != nil {
+				pending = append(pending, msg)
+				continue
+			}
+			net.flushed <- pending
+			pending = nil
+		case <-net.quit:
+			return
+		}
+	}
+}
+
+// flush returns the messages sent since the last flush. Out() is unbuffered, so once the nil message is
+// received every message a party sent before returning has been collected.
+func (net *Network) flush() []tss.Message {
+	net.out <- nil
+	msgs := <-net.flushed
+	// a round may send from several goroutines; sort so that the random draws below do not depend on scheduling
+	sort.SliceStable(msgs, func(a, b int) bool {
+		if msgs[a].Type() != msgs[b].Type() {
+			return msgs[a].Type() < msgs[b].Type()
+		}
+		return firstRecipient(msgs[a]) < firstRecipient(msgs[b])
+	})
+	if net.cfg.Tamper == nil {
+		return msgs
+	}
+	tampered := make([]tss.Message, 0, len(msgs))
+	for _, msg := range msgs {
+		tampered = append(tampered, net.cfg.Tamper(msg)...)
+	}
+	return tampered
+}
+
+func (net *Network) send(msgs []tss.Message, partyCount int) {
+	for _, msg := range msgs {
+		wire, _, err := msg.WireBytes()
+		if err != nil {
+			panic(fmt.Errorf("netsim: unable to encode %s: %v", msg.Type(), err))
+		}
+		from := msg.GetFrom()
+		var to []int
+		if msg.GetTo() == nil {
+			for j := 0; j < partyCount; j++ {
+				if j != from.Index {
+					to = append(to, j)
+				}
+			}
+		} else {
+			for _, Pj := range msg.GetTo() {
+				to = append(to, Pj.Index)
+			}
+		}
+		round := roundOf(msg)
+		stats := net.roundStats(round)
+		if !stats.sent {
+			stats.FirstSent, stats.sent = net.now, true
+		}
+		for _, j := range to {
+			copies := 1
+			switch {
+			case net.rng.Float64() < net.cfg.DropRate:
+				stats.Dropped++
+				continue
+			case net.rng.Float64() < net.cfg.DuplicateRate:
+				stats.Duplicated++
+				copies = 2
+			}
+			for c := 0; c < copies; c++ {
+				at, ok := net.deliveryTime(Link{From: from.Index, To: j})
+				if !ok {
+					stats.Dropped++
+					continue
+				}
+				stats.Messages++
+				stats.Bytes += len(wire)
+				if stats.LastDelivered < at {
+					stats.LastDelivered = at
+				}
+				net.seq++
+				heap.Push(&net.queue, &delivery{
+					at:        at,
+					seq:       net.seq,
+					from:      from,
+					to:        j,
+					broadcast: msg.IsBroadcast(),
+					wire:      wire,
+					round:     round,
+				})
+			}
+		}
+	}
+}
+
+// deliveryTime returns when a message sent now on the link arrives, or false if a partition drops it.
+func (net *Network) deliveryTime(link Link) (time.Duration, bool) {
+	latency, ok := net.cfg.LinkLatency[link]
+	if !ok {
+		latency = net.cfg.Latency
+	}
+	at := net.now + latency
+	if 0 < net.cfg.Jitter {
+		at += time.Duration(net.rng.Int63n(int64(net.cfg.Jitter)))
+	}
+	for _, p := range net.cfg.Partitions {
+		if at < p.From || (p.Until != 0 && p.Until <= at) || p.groupOf(link.From) == p.groupOf(link.To) {
+			continue
+		}
+		if p.Until == 0 {
+			return 0, false
+		}
+		at = p.Until
+	}
+	return at, true
+}
+
+func (net *Network) roundStats(round int) *RoundStats {
+	stats, ok := net.rounds[round]
+	if !ok {
+		stats = &RoundStats{Round: round}
+		net.rounds[round] = stats
+	}
+	return stats
+}
+
+func (p Partition) groupOf(party int) int {
+	for g, group := range p.Groups {
+		for _, member := range group {
+			if member == party {
+				return g
+			}
+		}
+	}
+	return len(p.Groups)
+}
+
+// roundOf returns the round number in the message's type name, e.g. 2 for KGRound2Message1, or 0 if it has none.
+func roundOf(msg tss.Message) int {
+	match := roundRegexp.FindStringSubmatch(msg.Type())
+	if match == nil {
+		return 0
+	}
+	round, _ := strconv.Atoi(match[1])
+	return round
+}
+
+func firstRecipient(msg tss.Message) int {
+	if to := msg.GetTo(); len(to) != 0 {
+		return to[0].Index
+	}
+	return -1
+}
+
+// ----- //
+
+// AllFinished reports whether every party finished without an error.
+func (r *Report) AllFinished() bool {
+	for _, finished := range r.Finished {
+		if !finished {
+			return false
+		}
+	}
+	return len(r.Errors) == 0
+}
+
+// Bytes returns the wire bytes of all the deliveries.
+func (r *Report) Bytes() int {
+	total := 0
+	for _, stats := range r.Rounds {
+		total += stats.Bytes
+	}
+	return total
+}
+
+// String formats the report as a table of the rounds.
+func (r *Report) String() string {
+	var sb strings.Builder
+	tw := tabwriter.NewWriter(&sb, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "round\tmessages\tbytes\tdropped\tduplicated\tfirst sent\tlast delivered\tcompute\t")
+	for _, s := range r.Rounds {
+		fmt.Fprintf(tw, "%d\t%d\t%d\t%d\t%d\t%s\t%s\t%s\t\n",
+			s.Round, s.Messages, s.Bytes, s.Dropped, s.Duplicated, s.FirstSent, s.LastDelivered, s.Compute.Round(time.Millisecond))
+	}
+	_ = tw.Flush()
+	fmt.Fprintf(&sb, "elapsed %s, %d bytes, finished %v, %d errors", r.Elapsed, r.Bytes(), r.Finished, len(r.Errors))
+	return sb.String()
+}
+
+// ----- //
+
+func (q deliveries) Len() int { return len(q) }
+
+func (q deliveries) Less(a, b int) bool {
+	if q[a].at != q[b].at {
+		return q[a].at < q[b].at
+	}
+	return q[a].seq < q[b].seq
+}
+
+func (q deliveries) Swap(a, b int) { q[a], q[b] = q[b], q[a] }
+
+func (q *deliveries) Push(x interface{}) { *q = append(*q, x.(*delivery)) }
+
+func (q *deliveries) Pop() interface{} {
+	old := *q
+	d := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return d
+}
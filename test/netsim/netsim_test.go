@@ -0,0 +1,156 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package netsim
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ipfs/go-log"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/ecdsa/signing"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+const testThreshold = 1
+
+func setUp(level string) {
+	if err := log.SetLogLevel("tss-lib", level); err != nil {
+		panic(err)
+	}
+}
+
+func newKeygenParties(t *testing.T, net *Network, count int, nonce int64) ([]tss.Party, chan keygen.LocalPartySaveData) {
+	fixtures, pIDs, err := keygen.LoadKeygenTestFixtures(count)
+	if err != nil {
+		t.Skip("test fixtures required (LocalPreParams) to run a quick keygen")
+	}
+	p2pCtx := tss.NewPeerContext(pIDs)
+	endCh := make(chan keygen.LocalPartySaveData, len(pIDs))
+	parties := make([]tss.Party, 0, len(pIDs))
+	for i := range pIDs {
+		params := tss.NewParameters(tss.S256(), p2pCtx, pIDs[i], len(pIDs), testThreshold)
+		params.SetSessionNonce(big.NewInt(nonce))
+		parties = append(parties, keygen.NewLocalParty(params, net.Out(), endCh, fixtures[i].LocalPreParams))
+	}
+	return parties, endCh
+}
+
+func TestKeygenAndSigningOverFaultyNetwork(t *testing.T) {
+	setUp("info")
+	cfg := Config{
+		Seed:          42,
+		Latency:       20 * time.Millisecond,
+		LinkLatency:   map[Link]time.Duration{{From: 2, To: 0}: 150 * time.Millisecond},
+		Jitter:        30 * time.Millisecond,
+		DuplicateRate: 0.2,
+		// P1 is cut off for the first 100ms, so its round 1 messages arrive late
+		Partitions: []Partition{{From: 0, Until: 100 * time.Millisecond, Groups: [][]int{{1}}}},
+	}
+
+	net := New(cfg)
+	parties, endCh := newKeygenParties(t, net, 3, 51)
+	report := net.Run(parties)
+	t.Logf("keygen:\n%s", report)
+	if !assert.True(t, report.AllFinished(), "keygen must finish: %v", report.Errors) {
+		return
+	}
+	assert.Len(t, endCh, len(parties))
+	assert.True(t, 100*time.Millisecond <= report.Elapsed)
+	assert.NotZero(t, report.Bytes())
+	duplicated := 0
+	for _, stats := range report.Rounds {
+		duplicated += stats.Duplicated
+	}
+	assert.NotZero(t, duplicated, "the seed must duplicate some messages")
+
+	keys := make([]keygen.LocalPartySaveData, len(parties))
+	for range parties {
+		save := <-endCh
+		index, err := save.OriginalIndex()
+		assert.NoError(t, err)
+		keys[index] = save
+	}
+
+	// signing by all of the parties, over a fresh network
+	signPIDs := make(tss.UnSortedPartyIDs, 0, len(parties))
+	for _, P := range parties {
+		signPIDs = append(signPIDs, tss.NewPartyID(P.PartyID().Id, P.PartyID().Moniker, P.PartyID().KeyInt()))
+	}
+	sortedPIDs := tss.SortPartyIDs(signPIDs)
+	p2pCtx := tss.NewPeerContext(sortedPIDs)
+	net = New(cfg)
+	sigCh := make(chan common.SignatureData, len(parties))
+	msg := big.NewInt(42)
+	signers := make([]tss.Party, 0, len(parties))
+	for i, id := range sortedPIDs {
+		params := tss.NewParameters(tss.S256(), p2pCtx, id, len(sortedPIDs), testThreshold)
+		params.SetSessionNonce(big.NewInt(52))
+		signers = append(signers, signing.NewLocalParty(msg, params, keys[i], net.Out(), sigCh, 32))
+	}
+	report = net.Run(signers)
+	t.Logf("signing:\n%s", report)
+	if !assert.True(t, report.AllFinished(), "signing must finish: %v", report.Errors) {
+		return
+	}
+	pk := ecdsa.PublicKey{
+		Curve: tss.EC(),
+		X:     keys[0].ECDSAPub.X(),
+		Y:     keys[0].ECDSAPub.Y(),
+	}
+	for range signers {
+		// R || S, each padded to 32 bytes
+		sig := (<-sigCh).Signature
+		if assert.Len(t, sig, 64) {
+			assert.True(t, ecdsa.Verify(&pk, msg.FillBytes(make([]byte, 32)), new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])),
+				"ecdsa verify must pass")
+		}
+	}
+}
+
+func TestRunIsReproducible(t *testing.T) {
+	setUp("info")
+	cfg := Config{
+		Seed:          7,
+		Latency:       10 * time.Millisecond,
+		Jitter:        50 * time.Millisecond,
+		DuplicateRate: 0.5,
+		// P0 never hears from the others, so nobody gets past round 1
+		Partitions: []Partition{{Groups: [][]int{{0}}}},
+	}
+	run := func() *Report {
+		net := New(cfg)
+		parties, _ := newKeygenParties(t, net, 3, 53)
+		return net.Run(parties)
+	}
+	first, second := run(), run()
+
+	assert.Equal(t, []bool{false, false, false}, first.Finished)
+	assert.Empty(t, first.Errors)
+	if assert.Len(t, first.Rounds, 1) {
+		assert.Equal(t, 1, first.Rounds[0].Round)
+		// the three broadcasts go to two parties each, and the four to or from P0 are dropped
+		assert.Equal(t, 6+first.Rounds[0].Duplicated, first.Rounds[0].Messages+first.Rounds[0].Dropped)
+		assert.True(t, 4 <= first.Rounds[0].Dropped)
+	}
+
+	assert.Equal(t, first.Elapsed, second.Elapsed)
+	if assert.Len(t, second.Rounds, len(first.Rounds)) {
+		for r, stats := range first.Rounds {
+			other := second.Rounds[r]
+			assert.Equal(t, stats.Messages, other.Messages)
+			assert.Equal(t, stats.Dropped, other.Dropped)
+			assert.Equal(t, stats.Duplicated, other.Duplicated)
+			assert.Equal(t, stats.LastDelivered, other.LastDelivered)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/bnb-chain/tss-lib/common"
+)
+
+// ErrAttemptTimeout is the cause of the Error returned by RunParty when the parties it waits for stay silent too long.
+var ErrAttemptTimeout = errors.New("timed out waiting for parties")
+
+type (
+	// Attempt describes a single run of a protocol started by a retrying coordinator.
+	Attempt struct {
+		// 1-based attempt counter
+		Number int
+		// the parties taking part in this attempt, as fresh copies indexed for this attempt only
+		Parties SortedPartyIDs
+		// the session nonce passed to Parameters.SetSessionNonce for this attempt
+		SessionNonce *big.Int
+	}
+
+	// Transport carries the messages of one attempt between the local party and its peers.
+	Transport interface {
+		// Send delivers an outbound message to msg.GetTo(), or to every other party of the attempt when it is a broadcast.
+		Send(msg Message) error
+		// Incoming returns the inbound messages of the attempt, e.g. parsed with ParseWireMessage.
+		// The sender must be resolved against Attempt.Parties so its Index matches this attempt.
+		Incoming() <-chan ParsedMessage
+		// Close releases the attempt's resources; no more messages are sent or received afterwards.
+		Close() error
+	}
+
+	// TransportFactory opens the Transport used by a new attempt.
+	TransportFactory func(attempt *Attempt) (Transport, error)
+
+	// Exclusion records why a party was removed from later attempts.
+	Exclusion struct {
+		Party   *PartyID
+		Attempt int
+		Round   int
+		Reason  string
+	}
+)
+
+// NewAttempt copies `parties` into a freshly sorted set and derives the attempt's session nonce.
+// Every party derives the same nonce from the same `sessionID`, attempt number and party set, so a
+// retry never reuses the SSID of an earlier attempt.
+func NewAttempt(sessionID []byte, number int, parties SortedPartyIDs) *Attempt {
+	ids := make(UnSortedPartyIDs, len(parties))
+	for i, pid := range parties {
+		ids[i] = NewPartyID(pid.Id, pid.Moniker, pid.KeyInt())
+	}
+	sorted := SortPartyIDs(ids)
+	nonceInput := append([]*big.Int{new(big.Int).SetBytes(sessionID), big.NewInt(int64(number))}, sorted.Keys()...)
+	return &Attempt{
+		Number:       number,
+		Parties:      sorted,
+		SessionNonce: common.SHA512_256i(nonceInput...),
+	}
+}
+
+// RunParty starts `party` and pumps messages between it and `transport` until `done` is closed or the party fails.
+// `out` must be the channel given to the party's constructor. When no inbound message arrives for `timeout`, the
+// attempt is abandoned with an ErrAttemptTimeout error blaming the parties the current round is still waiting for.
+func RunParty(party Party, out <-chan Message, done <-chan struct{}, transport Transport, timeout time.Duration) *Error {
+	sendErr := make(chan error, 1)
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		for {
+			select {
+			case msg := <-out:
+				if err := transport.Send(msg); err != nil {
+					select {
+					case sendErr <- err:
+					default:
+					}
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	if err := party.Start(); err != nil {
+		return err
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		select {
+		case <-done:
+			return nil
+		case err := <-sendErr:
+			return party.WrapError(fmt.Errorf("transport send failed: %v", err))
+		case msg, ok := <-transport.Incoming():
+			if !ok {
+				return party.WrapError(errors.New("transport closed before the party finished"))
+			}
+			if _, err := party.Update(msg); err != nil {
+				return err
+			}
+			if !timer.Stop() {
+				<-timer.C
+			}
+			timer.Reset(timeout)
+		case <-timer.C:
+			// updates can take a while; give messages that queued up meanwhile a chance first
+			if 0 < len(transport.Incoming()) {
+				timer.Reset(timeout)
+				continue
+			}
+			select {
+			case <-done:
+				return nil
+			default:
+			}
+			return party.WrapError(ErrAttemptTimeout, party.WaitingFor()...)
+		}
+	}
+}
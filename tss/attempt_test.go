@@ -0,0 +1,104 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAttemptDerivesFreshNonces(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(4)
+	sessionID := []byte("attempt-test-session-id")
+
+	a1 := NewAttempt(sessionID, 1, pIDs)
+	a1Again := NewAttempt(sessionID, 1, pIDs)
+	a2 := NewAttempt(sessionID, 2, pIDs)
+	a2Fewer := NewAttempt(sessionID, 2, pIDs.Exclude(pIDs[1]))
+
+	assert.Equal(t, a1.SessionNonce, a1Again.SessionNonce)
+	assert.NotEqual(t, a1.SessionNonce, a2.SessionNonce)
+	assert.NotEqual(t, a2.SessionNonce, a2Fewer.SessionNonce)
+
+	assert.Len(t, a2Fewer.Parties, 3)
+	assert.Equal(t, 1, a2Fewer.Parties[1].Index)
+	assert.Equal(t, 2, pIDs[2].Index, "the caller's party IDs must not be re-indexed")
+	assert.False(t, a2Fewer.Parties[1] == pIDs[2], "attempt parties must be copies")
+}
+
+func TestRunPartyTimesOutBlamingSilentParties(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(3)
+	out := make(chan Message, 1)
+	party := newAttemptTestParty(pIDs)
+	transport := &attemptTestTransport{in: make(chan ParsedMessage)}
+
+	err := RunParty(party, out, make(chan struct{}), transport, 50*time.Millisecond)
+	if !assert.NotNil(t, err) {
+		return
+	}
+	assert.True(t, errors.Is(err, ErrAttemptTimeout))
+	assert.Equal(t, []*PartyID{pIDs[1], pIDs[2]}, err.Culprits())
+}
+
+func TestRunPartyReturnsWhenDone(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(3)
+	done := make(chan struct{})
+	close(done)
+	party := newAttemptTestParty(pIDs)
+	transport := &attemptTestTransport{in: make(chan ParsedMessage)}
+
+	assert.Nil(t, RunParty(party, make(chan Message), done, transport, time.Minute))
+}
+
+// ----- //
+
+type attemptTestTransport struct {
+	in chan ParsedMessage
+}
+
+func (tr *attemptTestTransport) Send(Message) error             { return nil }
+func (tr *attemptTestTransport) Incoming() <-chan ParsedMessage { return tr.in }
+func (tr *attemptTestTransport) Close() error                   { return nil }
+
+type attemptTestParty struct {
+	*BaseParty
+	params *Parameters
+}
+
+func newAttemptTestParty(pIDs SortedPartyIDs) *attemptTestParty {
+	params := NewParameters(S256(), NewPeerContext(pIDs), pIDs[0], len(pIDs), 1)
+	return &attemptTestParty{BaseParty: new(BaseParty), params: params}
+}
+
+func (p *attemptTestParty) FirstRound() Round { return &attemptTestRound{params: p.params} }
+func (p *attemptTestParty) Start() *Error     { return BaseStart(p, "attempt-test") }
+func (p *attemptTestParty) Update(msg ParsedMessage) (bool, *Error) {
+	return BaseUpdate(p, msg, "attempt-test")
+}
+func (p *attemptTestParty) UpdateFromBytes([]byte, *PartyID, bool) (bool, *Error) { return false, nil }
+func (p *attemptTestParty) StoreMessage(ParsedMessage) (bool, *Error)             { return true, nil }
+func (p *attemptTestParty) PartyID() *PartyID                                     { return p.params.PartyID() }
+
+// attemptTestRound never completes and waits for every other party
+type attemptTestRound struct {
+	params *Parameters
+}
+
+func (r *attemptTestRound) Params() *Parameters              { return r.params }
+func (r *attemptTestRound) Start() *Error                    { return nil }
+func (r *attemptTestRound) Update() (bool, *Error)           { return true, nil }
+func (r *attemptTestRound) RoundNumber() int                 { return 1 }
+func (r *attemptTestRound) CanAccept(msg ParsedMessage) bool { return false }
+func (r *attemptTestRound) CanProceed() bool                 { return false }
+func (r *attemptTestRound) NextRound() Round                 { return nil }
+func (r *attemptTestRound) WaitingFor() []*PartyID           { return r.params.Parties().IDs()[1:] }
+func (r *attemptTestRound) WrapError(err error, culprits ...*PartyID) *Error {
+	return NewError(err, "attempt-test", 1, r.params.PartyID(), culprits...)
+}
@@ -0,0 +1,105 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ipfs/go-log"
+
+	"github.com/bnb-chain/tss-lib/common"
+)
+
+// Log field keys set by the protocol
+const (
+	LogKeyParty   = "party"
+	LogKeySession = "session"
+	LogKeyTask    = "task"
+	LogKeyRound   = "round"
+)
+
+type (
+	// Logger is a structured logger. Each method takes a message and alternating key, value pairs, as zap's
+	// SugaredLogger.Infow and slog.Logger.Info do; With returns a Logger that adds the pairs to every entry.
+	// Implementations must be safe for concurrent use.
+	Logger interface {
+		Debug(msg string, keysAndValues ...interface{})
+		Info(msg string, keysAndValues ...interface{})
+		Warn(msg string, keysAndValues ...interface{})
+		Error(msg string, keysAndValues ...interface{})
+		With(keysAndValues ...interface{}) Logger
+	}
+
+	// ipfsLogger formats the fields as key=value after the message, for the unstructured ipfs logger
+	ipfsLogger struct {
+		l      log.StandardLogger
+		fields []interface{}
+	}
+
+	loggerContextKey struct{}
+)
+
+// DefaultLogger writes to common.Logger, the "tss-lib" ipfs logger.
+var DefaultLogger Logger = NewIPFSLogger(common.Logger)
+
+// NewIPFSLogger returns a Logger that writes to an ipfs/go-log logger, with the fields as key=value after the message.
+func NewIPFSLogger(l log.StandardLogger) Logger {
+	return &ipfsLogger{l: l}
+}
+
+// ContextWithLogger returns a copy of ctx that carries `logger`, for functions without Parameters such as
+// keygen.GeneratePreParamsWithContext.
+func ContextWithLogger(ctx context.Context, logger Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey{}, logger)
+}
+
+// LoggerFromContext returns the Logger carried by ctx, or DefaultLogger if there is none.
+func LoggerFromContext(ctx context.Context) Logger {
+	if logger, ok := ctx.Value(loggerContextKey{}).(Logger); ok && logger != nil {
+		return logger
+	}
+	return DefaultLogger
+}
+
+func (l *ipfsLogger) Debug(msg string, keysAndValues ...interface{}) {
+	l.l.Debug(l.format(msg, keysAndValues))
+}
+
+func (l *ipfsLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.l.Info(l.format(msg, keysAndValues))
+}
+
+func (l *ipfsLogger) Warn(msg string, keysAndValues ...interface{}) {
+	l.l.Warning(l.format(msg, keysAndValues))
+}
+
+func (l *ipfsLogger) Error(msg string, keysAndValues ...interface{}) {
+	l.l.Error(l.format(msg, keysAndValues))
+}
+
+func (l *ipfsLogger) With(keysAndValues ...interface{}) Logger {
+	fields := make([]interface{}, 0, len(l.fields)+len(keysAndValues))
+	fields = append(append(fields, l.fields...), keysAndValues...)
+	return &ipfsLogger{l: l.l, fields: fields}
+}
+
+func (l *ipfsLogger) format(msg string, keysAndValues []interface{}) string {
+	var b strings.Builder
+	b.WriteString(msg)
+	for _, kvs := range [][]interface{}{l.fields, keysAndValues} {
+		for i := 0; i < len(kvs); i += 2 {
+			if i+1 < len(kvs) {
+				fmt.Fprintf(&b, " %v=%v", kvs[i], kvs[i+1])
+			} else {
+				fmt.Fprintf(&b, " %v=(missing)", kvs[i])
+			}
+		}
+	}
+	return b.String()
+}
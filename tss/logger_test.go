@@ -0,0 +1,65 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ipfs/go-log"
+	"github.com/stretchr/testify/assert"
+)
+
+// lineLogger records the lines given to the ipfs logger
+type lineLogger struct {
+	log.StandardLogger
+	lines []string
+}
+
+func (l *lineLogger) add(level string, args []interface{}) {
+	l.lines = append(l.lines, level+" "+args[0].(string))
+}
+
+func (l *lineLogger) Debug(args ...interface{})   { l.add("DEBUG", args) }
+func (l *lineLogger) Info(args ...interface{})    { l.add("INFO", args) }
+func (l *lineLogger) Warning(args ...interface{}) { l.add("WARN", args) }
+func (l *lineLogger) Error(args ...interface{})   { l.add("ERROR", args) }
+
+func TestIPFSLoggerFormatsFields(t *testing.T) {
+	lines := new(lineLogger)
+	logger := NewIPFSLogger(lines).With(LogKeyTask, "ecdsa-keygen")
+	logger.Info("round started", LogKeyRound, 2)
+	logger.With(LogKeyRound, 3).Warn("odd", "key")
+	logger.Debug("plain")
+	assert.Equal(t, []string{
+		"INFO round started task=ecdsa-keygen round=2",
+		"WARN odd task=ecdsa-keygen round=3 key=(missing)",
+		"DEBUG plain task=ecdsa-keygen",
+	}, lines.lines)
+}
+
+func TestParametersLoggerFields(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(2)
+	params := NewParameters(S256(), NewPeerContext(pIDs), pIDs[1], len(pIDs), 1)
+	lines := new(lineLogger)
+	params.SetLogger(NewIPFSLogger(lines))
+
+	params.Logger().Info("before")
+	params.SetSessionNonce(big.NewInt(255))
+	params.Logger().Error("after")
+	assert.Equal(t, []string{
+		"INFO before party=" + pIDs[1].String(),
+		"ERROR after party=" + pIDs[1].String() + " session=ff",
+	}, lines.lines)
+}
+
+func TestLoggerFromContext(t *testing.T) {
+	assert.Equal(t, DefaultLogger, LoggerFromContext(context.Background()))
+	logger := NewIPFSLogger(new(lineLogger))
+	assert.Equal(t, logger, LoggerFromContext(ContextWithLogger(context.Background(), logger)))
+}
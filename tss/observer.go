@@ -0,0 +1,167 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"time"
+)
+
+// Proof types of ProofEvent
+const (
+	ProofDLN      = "dln"
+	ProofMod      = "mod"
+	ProofFactor   = "factor"
+	ProofPaillier = "paillier"
+	ProofVSS      = "vss"
+	// the MtA range proof of Alice's ciphertext, and Bob's proofs without and with check; Alice's is timed with the
+	// rest of Bob's step, which it starts, and Bob's with the decryption of his share
+	ProofMtARange = "mta-range"
+	ProofMtABob   = "mta-bob"
+	ProofMtABobWC = "mta-bob-wc"
+	ProofSchnorr  = "schnorr"
+)
+
+type (
+	// Observer receives the events of a party, e.g. to export metrics or traces. It is called synchronously from the
+	// party's goroutines, and from several at a time during proof verification, so it must be quick and safe for
+	// concurrent use. An Observer may be shared by the parties of a process; the events name their party.
+	Observer interface {
+		RoundStarted(RoundEvent)
+		// RoundFinished is called when the party has every message of the round and moves on; Duration includes the
+		// time spent waiting on peers
+		RoundFinished(RoundEvent)
+		MessageReceived(MessageEvent)
+		ProofVerified(ProofEvent)
+		// PreParamsGenerated is called when the party has generated its own pre-parameters, the safe primes and the
+		// Paillier key, or failed to; it is not called when they were given to the party
+		PreParamsGenerated(PreParamsEvent)
+		CulpritDetected(CulpritEvent)
+	}
+
+	// Event identifies the party, protocol and round an event happened in.
+	Event struct {
+		Party *PartyID
+		Task  string
+		Round int
+		Time  time.Time
+	}
+
+	RoundEvent struct {
+		Event
+		// Duration is the time since the round started; zero for RoundStarted
+		Duration time.Duration
+		// Last is set for RoundFinished of the last round of the protocol
+		Last bool
+	}
+
+	MessageEvent struct {
+		Event
+		From *PartyID
+		// Type is the protobuf name of the message content
+		Type      string
+		Size      int
+		Broadcast bool
+	}
+
+	ProofEvent struct {
+		Event
+		// Proof is one of the Proof constants
+		Proof    string
+		Prover   *PartyID
+		Duration time.Duration
+		Valid    bool
+	}
+
+	PreParamsEvent struct {
+		Event
+		Duration time.Duration
+		// Err is the reason the generation failed, e.g. its timeout, or nil
+		Err error
+	}
+
+	// CulpritEvent is sent once per error that blames parties, in the round of the error
+	CulpritEvent struct {
+		Event
+		Culprits []*PartyID
+		Err      *Error
+	}
+
+	// NoopObserver ignores every event.
+	NoopObserver struct{}
+
+	// MultiObserver passes every event to each of its observers in turn, e.g. to a metrics registry and a tracer.
+	MultiObserver []Observer
+)
+
+func (NoopObserver) RoundStarted(RoundEvent)           {}
+func (NoopObserver) RoundFinished(RoundEvent)          {}
+func (NoopObserver) MessageReceived(MessageEvent)      {}
+func (NoopObserver) ProofVerified(ProofEvent)          {}
+func (NoopObserver) PreParamsGenerated(PreParamsEvent) {}
+func (NoopObserver) CulpritDetected(CulpritEvent)      {}
+
+func (m MultiObserver) RoundStarted(e RoundEvent) {
+	for _, o := range m {
+		o.RoundStarted(e)
+	}
+}
+
+func (m MultiObserver) RoundFinished(e RoundEvent) {
+	for _, o := range m {
+		o.RoundFinished(e)
+	}
+}
+
+func (m MultiObserver) MessageReceived(e MessageEvent) {
+	for _, o := range m {
+		o.MessageReceived(e)
+	}
+}
+
+func (m MultiObserver) ProofVerified(e ProofEvent) {
+	for _, o := range m {
+		o.ProofVerified(e)
+	}
+}
+
+func (m MultiObserver) PreParamsGenerated(e PreParamsEvent) {
+	for _, o := range m {
+		o.PreParamsGenerated(e)
+	}
+}
+
+func (m MultiObserver) CulpritDetected(e CulpritEvent) {
+	for _, o := range m {
+		o.CulpritDetected(e)
+	}
+}
+
+// NewEvent returns an Event of the party of `params` happening now.
+func NewEvent(params *Parameters, task string, round int) Event {
+	return Event{Party: params.PartyID(), Task: task, Round: round, Time: time.Now()}
+}
+
+// ObserveProof reports the verification of a proof by `prover`, which took `took`, to the observer of `params`.
+func ObserveProof(params *Parameters, task string, round int, proof string, prover *PartyID, took time.Duration, valid bool) {
+	params.Observer().ProofVerified(ProofEvent{
+		Event:    NewEvent(params, task, round),
+		Proof:    proof,
+		Prover:   prover,
+		Duration: took,
+		Valid:    valid,
+	})
+}
+
+// ObservePreParams reports the generation of the pre-parameters of the party of `params`, which took `took` and
+// failed with `err` unless it is nil, to its observer.
+func ObservePreParams(params *Parameters, task string, round int, took time.Duration, err error) {
+	params.Observer().PreParamsGenerated(PreParamsEvent{
+		Event:    NewEvent(params, task, round),
+		Duration: took,
+		Err:      err,
+	})
+}
@@ -0,0 +1,60 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// proofObserver records the proof and pre-params events
+type proofObserver struct {
+	NoopObserver
+	proofs    []ProofEvent
+	preParams []PreParamsEvent
+}
+
+func (o *proofObserver) ProofVerified(e ProofEvent) {
+	o.proofs = append(o.proofs, e)
+}
+
+func (o *proofObserver) PreParamsGenerated(e PreParamsEvent) {
+	o.preParams = append(o.preParams, e)
+}
+
+func TestParametersObserver(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(2)
+	params := NewParameters(S256(), NewPeerContext(pIDs), pIDs[0], len(pIDs), 1)
+	assert.Equal(t, NoopObserver{}, params.Observer())
+
+	first, second := new(proofObserver), new(proofObserver)
+	params.SetObserver(MultiObserver{first, second})
+	ObserveProof(params, "ecdsa-keygen", 2, ProofDLN, pIDs[1], time.Second, true)
+	ObservePreParams(params, "ecdsa-keygen", 1, time.Minute, assert.AnError)
+	for _, o := range []*proofObserver{first, second} {
+		if assert.Len(t, o.proofs, 1) {
+			e := o.proofs[0]
+			assert.Equal(t, pIDs[0], e.Party)
+			assert.Equal(t, pIDs[1], e.Prover)
+			assert.Equal(t, "ecdsa-keygen", e.Task)
+			assert.Equal(t, 2, e.Round)
+			assert.Equal(t, ProofDLN, e.Proof)
+			assert.Equal(t, time.Second, e.Duration)
+			assert.True(t, e.Valid)
+			assert.False(t, e.Time.IsZero())
+		}
+		if assert.Len(t, o.preParams, 1) {
+			e := o.preParams[0]
+			assert.Equal(t, pIDs[0], e.Party)
+			assert.Equal(t, 1, e.Round)
+			assert.Equal(t, time.Minute, e.Duration)
+			assert.Equal(t, assert.AnError, e.Err)
+		}
+	}
+}
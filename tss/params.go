@@ -8,7 +8,9 @@ package tss
 
 import (
 	"crypto/elliptic"
+	"crypto/rand"
 	"fmt"
+	"io"
 	"math/big"
 	"runtime"
 	"time"
@@ -29,6 +31,20 @@ type (
 		// binding. Keygen and signing require callers to coordinate a shared
 		// positive nonce before Start.
 		sessionNonce *big.Int
+		// chainCodeGeneration makes keygen jointly generate a BIP-32 chain code
+		chainCodeGeneration bool
+		// proofCache lets keygen skip re-verifying peers' pre-params proven in an earlier ceremony
+		proofCache ProofCache
+		// weights holds the number of shares of each party, by index; nil when every party holds one share
+		weights []int
+		// ranks holds the rank of each party in a hierarchical access structure, by index; nil for a flat threshold
+		ranks []int
+		// rand is the source of the party's randomness; nil means crypto/rand
+		rand io.Reader
+		// logger receives the protocol logs; nil means DefaultLogger
+		logger Logger
+		// observer receives the round, message, proof and culprit events; nil means none
+		observer Observer
 	}
 )
 
@@ -59,6 +75,78 @@ func NewParameters(ec elliptic.Curve, ctx *PeerContext, partyID *PartyID, partyC
 	}
 }
 
+// NewWeightedParameters is NewParameters for weighted threshold sharing: the party at index j of the sorted ctx
+// holds weights[j] shares of the key, and any set of parties holding more than `threshold` shares in total can
+// sign. For signing, pass the weights of the signers only.
+func NewWeightedParameters(ec elliptic.Curve, ctx *PeerContext, partyID *PartyID, weights []int, threshold int) *Parameters {
+	if len(weights) < 2 {
+		panic("tss: party count must be at least 2")
+	}
+	if ctx != nil && len(ctx.IDs()) != len(weights) {
+		panic(fmt.Errorf("tss: got %d weights for %d parties", len(weights), len(ctx.IDs())))
+	}
+	if threshold < 1 {
+		panic("tss: threshold must be at least 1")
+	}
+	total := 0
+	for j, w := range weights {
+		if w < 1 {
+			panic(fmt.Errorf("tss: party %d has weight %d; weights must be at least 1", j, w))
+		}
+		total += w
+	}
+	if threshold >= total {
+		panic("tss: threshold must be less than the total weight")
+	}
+	assertDistinctIDsModQ(ec, ctx)
+	return &Parameters{
+		ec:                  ec,
+		parties:             ctx,
+		partyID:             partyID,
+		partyCount:          len(weights),
+		threshold:           threshold,
+		concurrency:         runtime.GOMAXPROCS(0),
+		safePrimeGenTimeout: defaultSafePrimeGenTimeout,
+		weights:             append([]int(nil), weights...),
+	}
+}
+
+// NewHierarchicalParameters is NewParameters for a hierarchical access structure (Tassa's hierarchical threshold
+// sharing): the party at index j of the sorted ctx has rank ranks[j], and a set of parties may sign if it has more
+// than `threshold` members and, for each r from 1 to `threshold`, at least r members of rank below r. For example,
+// ranks 0 for the security team and 1 for everyone else with threshold 2 means "any 3 signers, one of them from the
+// security team". For signing, pass the ranks of the signers only.
+func NewHierarchicalParameters(ec elliptic.Curve, ctx *PeerContext, partyID *PartyID, ranks []int, threshold int) *Parameters {
+	if len(ranks) < 2 {
+		panic("tss: party count must be at least 2")
+	}
+	if ctx != nil && len(ctx.IDs()) != len(ranks) {
+		panic(fmt.Errorf("tss: got %d ranks for %d parties", len(ranks), len(ctx.IDs())))
+	}
+	if threshold < 1 {
+		panic("tss: threshold must be at least 1")
+	}
+	if threshold >= len(ranks) {
+		panic("tss: threshold must be less than party count")
+	}
+	for j, r := range ranks {
+		if r < 0 || threshold < r {
+			panic(fmt.Errorf("tss: party %d has rank %d; ranks must be between 0 and the threshold", j, r))
+		}
+	}
+	assertDistinctIDsModQ(ec, ctx)
+	return &Parameters{
+		ec:                  ec,
+		parties:             ctx,
+		partyID:             partyID,
+		partyCount:          len(ranks),
+		threshold:           threshold,
+		concurrency:         runtime.GOMAXPROCS(0),
+		safePrimeGenTimeout: defaultSafePrimeGenTimeout,
+		ranks:               append([]int(nil), ranks...),
+	}
+}
+
 func assertDistinctIDsModQ(ec elliptic.Curve, ctx *PeerContext) {
 	if ec == nil || ctx == nil {
 		return
@@ -101,6 +189,44 @@ func (params *Parameters) Threshold() int {
 	return params.threshold
 }
 
+// Weights returns the number of shares of each party, by index, or nil if the parameters are not weighted.
+func (params *Parameters) Weights() []int {
+	return params.weights
+}
+
+// Weight returns the number of shares held by the party at index j; 1 unless the parameters are weighted.
+func (params *Parameters) Weight(j int) int {
+	if params.weights == nil {
+		return 1
+	}
+	return params.weights[j]
+}
+
+// TotalWeight returns the number of shares held by all parties together.
+func (params *Parameters) TotalWeight() int {
+	if params.weights == nil {
+		return params.partyCount
+	}
+	total := 0
+	for _, w := range params.weights {
+		total += w
+	}
+	return total
+}
+
+// Ranks returns the rank of each party, by index, or nil if the parameters are not hierarchical.
+func (params *Parameters) Ranks() []int {
+	return params.ranks
+}
+
+// Rank returns the rank of the party at index j; 0 unless the parameters are hierarchical.
+func (params *Parameters) Rank(j int) int {
+	if params.ranks == nil {
+		return 0
+	}
+	return params.ranks[j]
+}
+
 func (params *Parameters) Concurrency() int {
 	return params.concurrency
 }
@@ -118,6 +244,81 @@ func (params *Parameters) SetSafePrimeGenTimeout(timeout time.Duration) {
 	params.safePrimeGenTimeout = timeout
 }
 
+// ChainCodeGeneration reports whether keygen jointly generates a BIP-32 chain code.
+func (params *Parameters) ChainCodeGeneration() bool {
+	return params.chainCodeGeneration
+}
+
+// SetChainCodeGeneration makes keygen run a commit-reveal of random chain code shares alongside the VSS commitments,
+// so no single party controls the chain code stored in the save data. All parties must use the same setting.
+func (params *Parameters) SetChainCodeGeneration(enabled bool) {
+	params.chainCodeGeneration = enabled
+}
+
+// ProofCache returns the cache of verified peer pre-params, or nil if none was set.
+func (params *Parameters) ProofCache() ProofCache {
+	return params.proofCache
+}
+
+// SetProofCache makes keygen skip the DLN and mod proofs of a peer whose (N, NTilde, h1, h2) it has already
+// verified for that peer's identity, and record the ones it verifies. The cache may be shared by the
+// ceremonies of a party; it must not be shared with, or filled by, other parties.
+func (params *Parameters) SetProofCache(cache ProofCache) {
+	params.proofCache = cache
+}
+
+// Rand returns the source of the party's randomness, crypto/rand's Reader unless SetRand was called.
+func (params *Parameters) Rand() io.Reader {
+	if params.rand == nil {
+		return rand.Reader
+	}
+	return params.rand
+}
+
+// SetRand makes the party draw its secrets, nonces and proof randomness from `rand` instead of crypto/rand, e.g.
+// a seeded reader to regenerate fixtures and test vectors deterministically. Never use anything but a
+// cryptographically secure source outside of tests. The rounds fork `rand` with common.ForkRand where they draw
+// from several goroutines, so the reader itself is only read from one goroutine at a time.
+func (params *Parameters) SetRand(rand io.Reader) {
+	params.rand = rand
+}
+
+// Logger returns the logger set with SetLogger, or DefaultLogger, with the party and session fields.
+func (params *Parameters) Logger() Logger {
+	logger := params.logger
+	if logger == nil {
+		logger = DefaultLogger
+	}
+	var fields []interface{}
+	if params.partyID != nil {
+		fields = append(fields, LogKeyParty, params.partyID.String())
+	}
+	if params.sessionNonce != nil {
+		fields = append(fields, LogKeySession, params.sessionNonce.Text(16))
+	}
+	return logger.With(fields...)
+}
+
+// SetLogger routes the protocol logs of the party to `logger`, e.g. an adapter of the application's zap or slog
+// logger, which may carry fields of its own such as the application's session ID.
+func (params *Parameters) SetLogger(logger Logger) {
+	params.logger = logger
+}
+
+// Observer returns the observer set with SetObserver, or a NoopObserver.
+func (params *Parameters) Observer() Observer {
+	if params.observer == nil {
+		return NoopObserver{}
+	}
+	return params.observer
+}
+
+// SetObserver makes the party report its round, message, proof and culprit events to `observer`, e.g. a
+// metrics.Registry or a metrics.Tracer.
+func (params *Parameters) SetObserver(observer Observer) {
+	params.observer = observer
+}
+
 // SessionNonce returns the optional per-session nonce used in proof challenges.
 func (params *Parameters) SessionNonce() *big.Int {
 	return params.sessionNonce
@@ -7,7 +7,9 @@
 package tss
 
 import (
+	"crypto/rand"
 	"math/big"
+	mrand "math/rand"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -114,3 +116,58 @@ func TestSortPartyIDsRejectsDuplicateKeys(t *testing.T) {
 		})
 	})
 }
+
+func TestNewWeightedParameters(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(3)
+	ctx := NewPeerContext(pIDs)
+	params := NewWeightedParameters(S256(), ctx, pIDs[0], []int{3, 1, 1}, 3)
+	assert.Equal(t, 3, params.PartyCount())
+	assert.Equal(t, 5, params.TotalWeight())
+	assert.Equal(t, 3, params.Weight(0))
+	assert.Equal(t, []int{3, 1, 1}, params.Weights())
+
+	unweighted := NewParameters(S256(), ctx, pIDs[0], len(pIDs), 1)
+	assert.Nil(t, unweighted.Weights())
+	assert.Equal(t, 1, unweighted.Weight(2))
+	assert.Equal(t, 3, unweighted.TotalWeight())
+
+	assert.Panics(t, func() {
+		NewWeightedParameters(S256(), ctx, pIDs[0], []int{3, 1, 1}, 5)
+	}, "the threshold must be below the total weight")
+	assert.Panics(t, func() {
+		NewWeightedParameters(S256(), ctx, pIDs[0], []int{3, 0, 1}, 2)
+	}, "every party must hold a share")
+	assert.Panics(t, func() {
+		NewWeightedParameters(S256(), ctx, pIDs[0], []int{3, 1}, 2)
+	}, "every party must have a weight")
+}
+
+func TestNewHierarchicalParameters(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(4)
+	ctx := NewPeerContext(pIDs)
+	params := NewHierarchicalParameters(S256(), ctx, pIDs[0], []int{0, 1, 1, 1}, 2)
+	assert.Equal(t, 4, params.PartyCount())
+	assert.Equal(t, 1, params.Rank(3))
+	assert.Equal(t, []int{0, 1, 1, 1}, params.Ranks())
+
+	flat := NewParameters(S256(), ctx, pIDs[0], len(pIDs), 2)
+	assert.Nil(t, flat.Ranks())
+	assert.Equal(t, 0, flat.Rank(3))
+
+	assert.Panics(t, func() {
+		NewHierarchicalParameters(S256(), ctx, pIDs[0], []int{0, 1, 1, 3}, 2)
+	}, "a rank above the threshold is useless")
+	assert.Panics(t, func() {
+		NewHierarchicalParameters(S256(), ctx, pIDs[0], []int{0, 1, 1}, 2)
+	}, "every party must have a rank")
+}
+
+func TestRandDefaultsToCryptoRand(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(2)
+	params := NewParameters(S256(), NewPeerContext(pIDs), pIDs[0], len(pIDs), 1)
+	assert.Equal(t, rand.Reader, params.Rand())
+
+	seeded := mrand.New(mrand.NewSource(1))
+	params.SetRand(seeded)
+	assert.Equal(t, seeded, params.Rand())
+}
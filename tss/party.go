@@ -10,8 +10,9 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
-	"github.com/bnb-chain/tss-lib/common"
+	"google.golang.org/protobuf/proto"
 )
 
 var ErrDuplicateMessage = errors.New("duplicate message")
@@ -35,6 +36,8 @@ type Party interface {
 	// Private lifecycle methods
 	setRound(Round) *Error
 	round() Round
+	parameters() *Parameters
+	roundStarted() (number int, at time.Time)
 	advance()
 	lock()
 	unlock()
@@ -43,6 +46,9 @@ type Party interface {
 type BaseParty struct {
 	mtx        sync.Mutex
 	rnd        Round
+	params     *Parameters // of the first round, kept once the party has no round left
+	rndNumber  int
+	rndStarted time.Time
 	FirstRound Round
 }
 
@@ -94,7 +100,8 @@ func (p *BaseParty) setRound(round Round) *Error {
 	if p.rnd != nil {
 		return p.WrapError(errors.New("a round is already set on this party"))
 	}
-	p.rnd = round
+	p.rnd, p.rndNumber, p.rndStarted = round, 1, time.Now()
+	p.params = round.Params()
 	return nil
 }
 
@@ -102,8 +109,18 @@ func (p *BaseParty) round() Round {
 	return p.rnd
 }
 
+func (p *BaseParty) parameters() *Parameters {
+	return p.params
+}
+
+// roundStarted returns the number of the current round and when the party moved to it. The rounds set their number
+// as they start, so it is counted here for the RoundStarted event.
+func (p *BaseParty) roundStarted() (number int, at time.Time) {
+	return p.rndNumber, p.rndStarted
+}
+
 func (p *BaseParty) advance() {
-	p.rnd = p.rnd.NextRound()
+	p.rnd, p.rndNumber, p.rndStarted = p.rnd.NextRound(), p.rndNumber+1, time.Now()
 }
 
 func (p *BaseParty) lock() {
@@ -116,6 +133,37 @@ func (p *BaseParty) unlock() {
 
 // ----- //
 
+// partyLogger returns the logger of the party's parameters with the task field, and the round field while the party
+// has a round left; DefaultLogger with the party field if the party never started
+func partyLogger(p Party, task string) Logger {
+	if rnd := p.round(); rnd != nil {
+		return rnd.Params().Logger().With(LogKeyTask, task, LogKeyRound, rnd.RoundNumber())
+	}
+	if params := p.parameters(); params != nil {
+		return params.Logger().With(LogKeyTask, task)
+	}
+	return DefaultLogger.With(LogKeyParty, p.PartyID().String(), LogKeyTask, task)
+}
+
+// observeCulprits reports an error that blames parties to the observer of `rnd`
+func observeCulprits(rnd Round, task string, err *Error) {
+	if rnd == nil || err == nil || len(err.Culprits()) == 0 {
+		return
+	}
+	rnd.Params().Observer().CulpritDetected(CulpritEvent{
+		Event:    NewEvent(rnd.Params(), task, err.Round()),
+		Culprits: err.Culprits(),
+		Err:      err,
+	})
+}
+
+func observeRoundStarted(p Party, task string) {
+	number, at := p.roundStarted()
+	event := NewEvent(p.round().Params(), task, number)
+	event.Time = at
+	p.round().Params().Observer().RoundStarted(RoundEvent{Event: event})
+}
+
 func BaseStart(p Party, task string, prepare ...func(Round) *Error) *Error {
 	p.lock()
 	defer p.unlock()
@@ -137,50 +185,75 @@ func BaseStart(p Party, task string, prepare ...func(Round) *Error) *Error {
 			return err
 		}
 	}
-	common.Logger.Infof("party %s: %s round %d starting", p.round().Params().PartyID(), task, 1)
+	logger := partyLogger(p, task)
+	logger.Info("round starting")
 	defer func() {
-		common.Logger.Debugf("party %s: %s round %d finished", p.round().Params().PartyID(), task, 1)
+		logger.Debug("round finished")
 	}()
-	return p.round().Start()
+	observeRoundStarted(p, task)
+	err := round.Start()
+	observeCulprits(round, task, err)
+	return err
 }
 
 // an implementation of Update that is shared across the different types of parties (keygen, signing, dynamic groups)
 func BaseUpdate(p Party, msg ParsedMessage, task string) (ok bool, err *Error) {
 	// fast-fail on an invalid message; do not lock the mutex yet
 	if _, err := p.ValidateMessage(msg); err != nil {
+		p.lock()
+		observeCulprits(p.round(), task, err)
+		p.unlock()
 		return false, err
 	}
+	return baseUpdate(p, msg, task, true)
+}
+
+func baseUpdate(p Party, msg ParsedMessage, task string, received bool) (ok bool, err *Error) {
 	// lock the mutex. need this mtx unlock hook; L108 is recursive so cannot use defer
 	r := func(ok bool, err *Error) (bool, *Error) {
+		observeCulprits(p.round(), task, err)
 		p.unlock()
 		return ok, err
 	}
 	p.lock() // data is written to P state below
-	common.Logger.Debugf("party %s received message: %s", p.PartyID(), msg.String())
-	if p.round() != nil {
-		common.Logger.Debugf("party %s round %d update: %s", p.PartyID(), p.round().RoundNumber(), msg.String())
+	logger := partyLogger(p, task)
+	logger.Debug("received message", "msg", msg.String())
+	if rnd := p.round(); received && rnd != nil {
+		rnd.Params().Observer().MessageReceived(MessageEvent{
+			Event:     NewEvent(rnd.Params(), task, rnd.RoundNumber()),
+			From:      msg.GetFrom(),
+			Type:      msg.Type(),
+			Size:      proto.Size(msg.WireMsg().GetMessage()),
+			Broadcast: msg.IsBroadcast(),
+		})
 	}
 	if ok, err := p.StoreMessage(msg); err != nil || !ok {
 		return r(false, err)
 	}
 	if p.round() != nil {
-		common.Logger.Debugf("party %s: %s round %d update", p.round().Params().PartyID(), task, p.round().RoundNumber())
+		logger.Debug("round update")
 		if _, err := p.round().Update(); err != nil {
 			return r(false, err)
 		}
-		if p.round().CanProceed() {
-			if p.advance(); p.round() != nil {
+		if rnd := p.round(); rnd.CanProceed() {
+			finished := RoundEvent{Event: NewEvent(rnd.Params(), task, rnd.RoundNumber())}
+			_, startedAt := p.roundStarted()
+			finished.Duration = finished.Time.Sub(startedAt)
+			p.advance()
+			finished.Last = p.round() == nil
+			rnd.Params().Observer().RoundFinished(finished)
+			if p.round() != nil {
+				observeRoundStarted(p, task)
 				if err := p.round().Start(); err != nil {
 					return r(false, err)
 				}
-				rndNum := p.round().RoundNumber()
-				common.Logger.Infof("party %s: %s round %d started", p.round().Params().PartyID(), task, rndNum)
+				partyLogger(p, task).Info("round started")
 			} else {
 				// finished! the round implementation will have sent the data through the `end` channel.
-				common.Logger.Infof("party %s: %s finished!", p.PartyID(), task)
+				logger.Info("finished")
 			}
-			p.unlock()                      // recursive so can't defer after return
-			return BaseUpdate(p, msg, task) // re-run round update or finish)
+			p.unlock()                             // recursive so can't defer after return
+			return baseUpdate(p, msg, task, false) // re-run round update or finish)
 		}
 		return r(true, nil)
 	}
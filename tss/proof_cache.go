@@ -0,0 +1,68 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"sync"
+)
+
+type (
+	// ProofCache remembers the peers' pre-params whose proofs a party has already verified, so that a ceremony
+	// reusing them can skip the expensive re-verification. Keys are opaque digests built by the protocol
+	// packages; they bind the parameters to the long-term identity (PartyID.Key) of the party that proved them.
+	// Implementations must be safe for concurrent use.
+	ProofCache interface {
+		Contains(key []byte) bool
+		Add(key []byte)
+	}
+
+	// MemoryProofCache is an in-memory ProofCache holding at most `capacity` keys, evicting the oldest first.
+	MemoryProofCache struct {
+		mtx      sync.Mutex
+		capacity int
+		keys     map[string]struct{}
+		order    []string
+	}
+)
+
+var _ ProofCache = (*MemoryProofCache)(nil)
+
+// NewMemoryProofCache returns an empty MemoryProofCache. A capacity <= 0 means unbounded.
+func NewMemoryProofCache(capacity int) *MemoryProofCache {
+	return &MemoryProofCache{
+		capacity: capacity,
+		keys:     make(map[string]struct{}),
+	}
+}
+
+func (c *MemoryProofCache) Contains(key []byte) bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	_, ok := c.keys[string(key)]
+	return ok
+}
+
+func (c *MemoryProofCache) Add(key []byte) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if _, ok := c.keys[string(key)]; ok {
+		return
+	}
+	if c.capacity > 0 && len(c.order) >= c.capacity {
+		delete(c.keys, c.order[0])
+		c.order = c.order[1:]
+	}
+	c.keys[string(key)] = struct{}{}
+	c.order = append(c.order, string(key))
+}
+
+// Len returns the number of cached keys.
+func (c *MemoryProofCache) Len() int {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return len(c.keys)
+}
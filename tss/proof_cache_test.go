@@ -0,0 +1,31 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryProofCacheEvictsOldest(t *testing.T) {
+	c := NewMemoryProofCache(2)
+	c.Add([]byte("a"))
+	c.Add([]byte("b"))
+	c.Add([]byte("a"))
+	assert.Equal(t, 2, c.Len())
+	c.Add([]byte("c"))
+	assert.False(t, c.Contains([]byte("a")))
+	assert.True(t, c.Contains([]byte("b")))
+	assert.True(t, c.Contains([]byte("c")))
+
+	unbounded := NewMemoryProofCache(0)
+	for _, k := range []string{"a", "b", "c"} {
+		unbounded.Add([]byte(k))
+	}
+	assert.Equal(t, 3, unbounded.Len())
+}